@@ -2,73 +2,310 @@ package auth
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/user/redfish-server/internal/clock"
+	"github.com/user/redfish-server/internal/store"
 )
 
 // User represents a user account
 type User struct {
-	Username string
-	Password string // In production, this should be hashed
-	Role     string
-	Enabled  bool
+	Username     string
+	PasswordHash string // Salted hash from hashPassword; the password itself is never kept
+	Role         string
+	Enabled      bool
 }
 
-// Session represents an active user session
+// Session represents an active user session. Its Id names the Session
+// resource and may be listed; the Token authenticates and must never be.
 type Session struct {
+	ID       string
 	Token    string
 	Username string
 	Created  time.Time
 	Expires  time.Time
 }
 
+// SessionLifetime is how long a session stays valid after creation or refresh
+const SessionLifetime = 24 * time.Hour
+
 // AuthService manages authentication and sessions
 type AuthService struct {
 	users    map[string]*User
-	sessions map[string]*Session
+	sessions map[string]*Session // Keyed by token
+	lastID   uint64              // Id of the most recently created session
+	policy   AccountPolicy
+	failures map[string]*loginFailures
+	store    store.Store // Where accounts are saved on change; nil keeps them in memory only
+	clock    clock.Clock // Stamps sessions and lockouts
 	mutex    sync.RWMutex
+
+	generateToken TokenGenerator // nil selects defaultTokenGenerator
+}
+
+// Errors returned when creating users
+var (
+	ErrUserExists  = errors.New("user already exists")
+	ErrUnknownRole = errors.New("unknown role")
+)
+
+// ErrSessionNotFound is returned when rotating the token of a session that
+// does not exist
+var ErrSessionNotFound = errors.New("session not found")
+
+// rolePrivileges maps each predefined role to its assigned privileges
+var rolePrivileges = map[string][]string{
+	"Administrator": {"Login", "ConfigureManager", "ConfigureUsers", "ConfigureComponents", "ConfigureSelf"},
+	"Operator":      {"Login", "ConfigureComponents", "ConfigureSelf"},
+	"ReadOnly":      {"Login", "ConfigureSelf"},
 }
 
+// RolePrivileges returns the privileges assigned to a predefined role
+func RolePrivileges(role string) ([]string, bool) {
+	privileges, ok := rolePrivileges[role]
+	if !ok {
+		return nil, false
+	}
+	return append([]string(nil), privileges...), true
+}
+
+// DefaultPassword is the well-known password of the built-in accounts
+const DefaultPassword = "password"
+
 // NewAuthService creates a new authentication service with default users
 func NewAuthService() *AuthService {
 	auth := &AuthService{
 		users:    make(map[string]*User),
 		sessions: make(map[string]*Session),
+		policy:   DefaultAccountPolicy(),
+		failures: make(map[string]*loginFailures),
+		clock:    clock.Real,
 	}
 
-	// Add default admin user (for development)
-	auth.users["admin"] = &User{
-		Username: "admin",
-		Password: "password", // In production, use hashed passwords
-		Role:     "Administrator",
-		Enabled:  true,
-	}
-
-	// Add default operator user
-	auth.users["operator"] = &User{
-		Username: "operator",
-		Password: "password",
-		Role:     "Operator",
-		Enabled:  true,
+	// Add the default admin and operator users (for development). Should
+	// hashing fail, the account is left unable to log in.
+	for username, role := range map[string]string{"admin": "Administrator", "operator": "Operator"} {
+		hash, _ := hashPassword(DefaultPassword)
+		auth.users[username] = &User{
+			Username:     username,
+			PasswordHash: hash,
+			Role:         role,
+			Enabled:      true,
+		}
 	}
 
 	return auth
 }
 
-// ValidateBasicAuth validates username/password credentials
+// ValidateBasicAuth validates username/password credentials. Failed attempts
+// count towards the account lockout threshold of the account policy.
 func (a *AuthService) ValidateBasicAuth(username, password string) bool {
-	a.mutex.RLock()
-	defer a.mutex.RUnlock()
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 
 	user, exists := a.users[username]
 	if !exists || !user.Enabled {
 		return false
 	}
 
-	// In production, use proper password hashing (bcrypt)
-	return user.Password == password
+	now := a.clock.Now()
+	if a.lockedLocked(username, now) {
+		return false
+	}
+
+	if !verifyPassword(user.PasswordHash, password) {
+		a.recordFailureLocked(username, now)
+		return false
+	}
+
+	delete(a.failures, username)
+	return true
+}
+
+// CreateUser adds a user account after checking its password against the
+// account policy
+func (a *AuthService) CreateUser(username, password, role string, enabled bool) error {
+	if _, ok := rolePrivileges[role]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownRole, role)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, exists := a.users[username]; exists {
+		return fmt.Errorf("%w: %s", ErrUserExists, username)
+	}
+	if err := a.policy.ValidatePassword(password); err != nil {
+		return err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	a.users[username] = &User{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         role,
+		Enabled:      enabled,
+	}
+	if err := a.saveUsersLocked(); err != nil {
+		delete(a.users, username)
+		return err
+	}
+	return nil
+}
+
+// usersStateName is the name accounts are saved under in a store
+const usersStateName = "accounts"
+
+// policyStateName is the name the account policy is saved under in a store
+const policyStateName = "account-policy"
+
+// UseStore loads previously saved accounts and account policy from s,
+// replacing the defaults, and saves them to s whenever they change. When s
+// holds no accounts or policy yet, the current ones are saved to it.
+// Accounts saved with a plaintext Password, as earlier versions did, are
+// hashed and saved again.
+func (a *AuthService) UseStore(s store.Store) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var saved []struct {
+		User
+		Password string
+	}
+	usersFound := true
+	err := s.Load(usersStateName, &saved)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		usersFound = false
+	case err != nil:
+		return fmt.Errorf("failed to load accounts: %w", err)
+	}
+
+	var policy AccountPolicy
+	policyFound := true
+	err = s.Load(policyStateName, &policy)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		policyFound = false
+	case err != nil:
+		return fmt.Errorf("failed to load account policy: %w", err)
+	}
+	if policyFound {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("saved account policy is invalid: %w", err)
+		}
+	}
+
+	users := a.users
+	migrated := false
+	if usersFound {
+		users = make(map[string]*User, len(saved))
+		for _, entry := range saved {
+			user := entry.User
+			if user.PasswordHash == "" && entry.Password != "" {
+				if user.PasswordHash, err = hashPassword(entry.Password); err != nil {
+					return err
+				}
+				migrated = true
+			}
+			users[user.Username] = &user
+		}
+	}
+
+	a.users = users
+	if policyFound {
+		a.policy = policy
+	}
+	a.store = s
+	if !usersFound || migrated {
+		if err := a.saveUsersLocked(); err != nil {
+			return err
+		}
+	}
+	if !policyFound {
+		return a.savePolicyLocked()
+	}
+	return nil
+}
+
+// SecureDefaultAccounts replaces the well-known credentials of the built-in
+// accounts for a production deployment: the admin account gets
+// adminPassword, and any other enabled account still using DefaultPassword
+// is disabled.
+func (a *AuthService) SecureDefaultAccounts(adminPassword string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if adminPassword == DefaultPassword {
+		return fmt.Errorf("%w: the administrator password must not be the default password", ErrPasswordPolicy)
+	}
+	if err := a.policy.ValidatePassword(adminPassword); err != nil {
+		return err
+	}
+
+	hash, err := hashPassword(adminPassword)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]User, len(a.users))
+	for name, user := range a.users {
+		previous[name] = *user
+	}
+	for name, user := range a.users {
+		if name == "admin" {
+			user.PasswordHash = hash
+			continue
+		}
+		if user.Enabled && verifyPassword(user.PasswordHash, DefaultPassword) {
+			user.Enabled = false
+		}
+	}
+	if err := a.saveUsersLocked(); err != nil {
+		for name, user := range previous {
+			*a.users[name] = user
+		}
+		return err
+	}
+	return nil
+}
+
+// saveUsersLocked writes all accounts to the store, if one is in use. The
+// caller must hold the write lock.
+func (a *AuthService) saveUsersLocked() error {
+	if a.store == nil {
+		return nil
+	}
+
+	users := make([]*User, 0, len(a.users))
+	for _, user := range a.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	if err := a.store.Save(usersStateName, users); err != nil {
+		return fmt.Errorf("failed to save accounts: %w", err)
+	}
+	return nil
+}
+
+// savePolicyLocked saves the account policy to the store, if one is in use.
+// The caller must hold the write lock.
+func (a *AuthService) savePolicyLocked() error {
+	if a.store == nil {
+		return nil
+	}
+	if err := a.store.Save(policyStateName, a.policy); err != nil {
+		return fmt.Errorf("failed to save account policy: %w", err)
+	}
+	return nil
 }
 
 // CreateSession creates a new session for the authenticated user
@@ -76,18 +313,19 @@ func (a *AuthService) CreateSession(username string) (string, error) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	// Generate a random token
-	tokenBytes := make([]byte, 32)
-	if _, err := rand.Read(tokenBytes); err != nil {
+	token, err := a.newSessionTokenLocked()
+	if err != nil {
 		return "", err
 	}
-	token := hex.EncodeToString(tokenBytes)
 
+	now := a.clock.Now()
+	a.lastID++
 	session := &Session{
+		ID:       strconv.FormatUint(a.lastID, 10),
 		Token:    token,
 		Username: username,
-		Created:  time.Now(),
-		Expires:  time.Now().Add(24 * time.Hour), // 24 hour session
+		Created:  now,
+		Expires:  now.Add(SessionLifetime),
 	}
 
 	a.sessions[token] = session
@@ -95,28 +333,106 @@ func (a *AuthService) CreateSession(username string) (string, error) {
 	return token, nil
 }
 
-// ValidateSessionToken validates a session token and returns the username
+// RotateToken replaces the token of a session with a new one, keeping its
+// Id, user, creation time and expiry, so a token captured before a privilege
+// change or password change no longer authenticates. The old token stops
+// working immediately.
+func (a *AuthService) RotateToken(oldToken string) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	session, exists := a.sessions[oldToken]
+	if !exists {
+		return "", ErrSessionNotFound
+	}
+	token, err := a.newSessionTokenLocked()
+	if err != nil {
+		return "", err
+	}
+
+	rotated := *session
+	rotated.Token = token
+	delete(a.sessions, oldToken)
+	a.sessions[token] = &rotated
+	return token, nil
+}
+
+// SetClock replaces the clock sessions and lockouts are timed by, so tests
+// can expire them without waiting
+func (a *AuthService) SetClock(c clock.Clock) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.clock = c
+}
+
+// ValidateSessionToken validates a session token and returns the username.
+// An expired session is removed.
 func (a *AuthService) ValidateSessionToken(token string) (string, bool) {
-	a.mutex.RLock()
-	defer a.mutex.RUnlock()
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 
-	session, exists := a.sessions[token]
+	session, exists := a.liveSessionLocked(token)
 	if !exists {
 		return "", false
 	}
+	return session.Username, true
+}
+
+// GetSession returns a copy of the session for the given token
+func (a *AuthService) GetSession(token string) (Session, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	session, exists := a.liveSessionLocked(token)
+	if !exists {
+		return Session{}, false
+	}
+	return *session, true
+}
 
-	// Check if session has expired (disabled for testing)
-	// if time.Now().After(session.Expires) {
-	// 	// Clean up expired session
-	// 	go func() {
-	// 		a.mutex.Lock()
-	// 		delete(a.sessions, token)
-	// 		a.mutex.Unlock()
-	// 	}()
-	// 	return "", false
-	// }
+// GetSessionByID returns a copy of the session with the given Id
+func (a *AuthService) GetSessionByID(id string) (Session, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 
-	return session.Username, true
+	for token, session := range a.sessions {
+		if session.ID != id {
+			continue
+		}
+		if session, live := a.liveSessionLocked(token); live {
+			return *session, true
+		}
+		break
+	}
+	return Session{}, false
+}
+
+// RefreshSession slides the expiry of an existing session forward. A session
+// that has already expired cannot be refreshed.
+func (a *AuthService) RefreshSession(token string) (Session, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	session, exists := a.liveSessionLocked(token)
+	if !exists {
+		return Session{}, false
+	}
+	session.Expires = a.clock.Now().Add(SessionLifetime)
+	return *session, true
+}
+
+// liveSessionLocked returns the session for the given token, removing it
+// instead if it has expired. The caller must hold the mutex for writing.
+func (a *AuthService) liveSessionLocked(token string) (*Session, bool) {
+	session, exists := a.sessions[token]
+	if !exists {
+		return nil, false
+	}
+	if !a.clock.Now().Before(session.Expires) {
+		delete(a.sessions, token)
+		return nil, false
+	}
+	return session, true
 }
 
 // DeleteSession removes a session
@@ -126,6 +442,29 @@ func (a *AuthService) DeleteSession(token string) {
 	delete(a.sessions, token)
 }
 
+// ListSessions returns all active sessions ordered by creation time,
+// removing any that have expired
+func (a *AuthService) ListSessions() []*Session {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	sessions := make([]*Session, 0, len(a.sessions))
+	for token := range a.sessions {
+		if session, live := a.liveSessionLocked(token); live {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].Created.Equal(sessions[j].Created) {
+			// Ids count up, so the shorter one is older
+			a, b := sessions[i].ID, sessions[j].ID
+			return len(a) < len(b) || len(a) == len(b) && a < b
+		}
+		return sessions[i].Created.Before(sessions[j].Created)
+	})
+	return sessions
+}
+
 // GetUser returns user information
 func (a *AuthService) GetUser(username string) (*User, bool) {
 	a.mutex.RLock()
@@ -173,7 +512,8 @@ type userKey struct{}
 
 type UserContext struct {
 	Username string
-	Method   string // "Basic" or "Session"
+	Method   string // "Basic", "Session" or "Bearer"
+	Role     string // Set when the credential itself carries the role, as bearer tokens do
 }
 
 // SetUserContext adds user information to request context
@@ -184,6 +524,16 @@ func SetUserContext(ctx context.Context, username, method string) context.Contex
 	})
 }
 
+// SetUserContextWithRole adds user information to request context for
+// credentials that carry their own role
+func SetUserContextWithRole(ctx context.Context, username, method, role string) context.Context {
+	return context.WithValue(ctx, userKey{}, &UserContext{
+		Username: username,
+		Method:   method,
+		Role:     role,
+	})
+}
+
 // GetUserContext retrieves user information from request context
 func GetUserContext(ctx context.Context) (*UserContext, bool) {
 	userCtx, ok := ctx.Value(userKey{}).(*UserContext)
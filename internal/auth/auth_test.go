@@ -1,7 +1,14 @@
 package auth
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/clock"
+	"github.com/user/redfish-server/internal/store"
 )
 
 func TestValidateBasicAuth(t *testing.T) {
@@ -116,3 +123,306 @@ func TestGlobalAuthService(t *testing.T) {
 		t.Error("Global auth service should validate credentials")
 	}
 }
+
+func TestRefreshSession(t *testing.T) {
+	auth := NewAuthService()
+
+	token, err := auth.CreateSession("admin")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Pretend the session is close to expiring
+	auth.sessions[token].Expires = time.Now().Add(time.Minute)
+	before, _ := auth.GetSession(token)
+
+	refreshed, ok := auth.RefreshSession(token)
+	if !ok {
+		t.Fatal("Refreshing an existing session should succeed")
+	}
+	if !refreshed.Expires.After(before.Expires) {
+		t.Errorf("Expected Expires to move forward from %v, got %v", before.Expires, refreshed.Expires)
+	}
+	if refreshed.Expires.Before(time.Now().Add(SessionLifetime - time.Minute)) {
+		t.Errorf("Expected Expires to be a full lifetime away, got %v", refreshed.Expires)
+	}
+
+	if _, ok := auth.RefreshSession("invalid-token"); ok {
+		t.Error("Refreshing an unknown session should fail")
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	auth := NewAuthService()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	auth.SetClock(fake)
+
+	expiring, _ := auth.CreateSession("admin")
+	refreshed, _ := auth.CreateSession("operator")
+	if session, _ := auth.GetSession(expiring); !session.Expires.Equal(fake.Now().Add(SessionLifetime)) {
+		t.Errorf("Expected the session to expire a lifetime from now, got %v", session.Expires)
+	}
+
+	fake.Advance(SessionLifetime - time.Minute)
+	if _, ok := auth.ValidateSessionToken(expiring); !ok {
+		t.Fatal("Expected the session to be valid before it expires")
+	}
+	if _, ok := auth.RefreshSession(refreshed); !ok {
+		t.Fatal("Expected a live session to refresh")
+	}
+
+	fake.Advance(time.Minute)
+	if _, ok := auth.ValidateSessionToken(expiring); ok {
+		t.Error("Expected the session to be rejected once it expires")
+	}
+	if _, ok := auth.RefreshSession(expiring); ok {
+		t.Error("Expected an expired session not to refresh")
+	}
+	if _, ok := auth.ValidateSessionToken(refreshed); !ok {
+		t.Error("Expected the refreshed session to outlive its original expiry")
+	}
+	if sessions := auth.ListSessions(); len(sessions) != 1 || sessions[0].Token != refreshed {
+		t.Errorf("Expected only the refreshed session to be listed, got %d", len(sessions))
+	}
+	if _, exists := auth.sessions[expiring]; exists {
+		t.Error("Expected the expired session to be reaped")
+	}
+}
+
+func TestRotateToken(t *testing.T) {
+	auth := NewAuthService()
+
+	oldToken, err := auth.CreateSession("operator")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	before, _ := auth.GetSession(oldToken)
+
+	newToken, err := auth.RotateToken(oldToken)
+	if err != nil {
+		t.Fatalf("Failed to rotate token: %v", err)
+	}
+	if newToken == "" || newToken == oldToken {
+		t.Fatalf("Expected a new token, got %q", newToken)
+	}
+
+	// The old token no longer authenticates; the new one does as the same user
+	if _, valid := auth.ValidateSessionToken(oldToken); valid {
+		t.Error("The rotated-out token should be invalid")
+	}
+	username, valid := auth.ValidateSessionToken(newToken)
+	if !valid || username != "operator" {
+		t.Errorf("Expected the new token to authenticate operator, got %q, %v", username, valid)
+	}
+
+	// The session keeps its metadata
+	after, _ := auth.GetSession(newToken)
+	if after.ID != before.ID || !after.Created.Equal(before.Created) || !after.Expires.Equal(before.Expires) {
+		t.Errorf("Expected Id, Created and Expires to be kept, got %s, %v and %v", after.ID, after.Created, after.Expires)
+	}
+	if len(auth.ListSessions()) != 1 {
+		t.Errorf("Expected rotation to keep one session, got %d", len(auth.ListSessions()))
+	}
+
+	if _, err := auth.RotateToken(oldToken); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound rotating a stale token, got %v", err)
+	}
+}
+
+func TestAccountLockout(t *testing.T) {
+	auth := NewAuthService()
+	policy := DefaultAccountPolicy()
+	policy.AccountLockoutThreshold = 2
+	if err := auth.SetAccountPolicy(policy); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+
+	auth.ValidateBasicAuth("operator", "wrong")
+	if auth.IsLocked("operator") {
+		t.Error("Account should not be locked below the threshold")
+	}
+	auth.ValidateBasicAuth("operator", "wrong")
+	if !auth.IsLocked("operator") {
+		t.Error("Account should be locked once the threshold is reached")
+	}
+	if auth.ValidateBasicAuth("operator", "password") {
+		t.Error("Locked account should reject valid credentials")
+	}
+
+	// Lockout disabled
+	policy.AccountLockoutThreshold = 0
+	auth.SetAccountPolicy(policy)
+	for i := 0; i < 10; i++ {
+		auth.ValidateBasicAuth("admin", "wrong")
+	}
+	if !auth.ValidateBasicAuth("admin", "password") {
+		t.Error("Account should not lock when the threshold is 0")
+	}
+}
+
+func TestCreateUserPasswordPolicy(t *testing.T) {
+	auth := NewAuthService()
+
+	if err := auth.CreateUser("short", "abc", "ReadOnly", true); !errors.Is(err, ErrPasswordPolicy) {
+		t.Errorf("Expected ErrPasswordPolicy, got %v", err)
+	}
+	if err := auth.CreateUser("valid", "longenough", "ReadOnly", true); err != nil {
+		t.Errorf("Expected user creation to succeed, got %v", err)
+	}
+	if err := auth.CreateUser("valid", "longenough", "ReadOnly", true); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Expected ErrUserExists, got %v", err)
+	}
+	if err := auth.CreateUser("norole", "longenough", "Nobody", true); !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("Expected ErrUnknownRole, got %v", err)
+	}
+}
+
+func TestAuthServicePrivileges(t *testing.T) {
+	var a Authenticator = NewAuthService()
+
+	if !HasPrivilege(a, "admin", "ConfigureManager") {
+		t.Error("Administrator should hold ConfigureManager")
+	}
+	if HasPrivilege(a, "operator", "ConfigureManager") {
+		t.Error("Operator should not hold ConfigureManager")
+	}
+	if privileges := a.Privileges("nobody"); len(privileges) != 0 {
+		t.Errorf("Unknown users should have no privileges, got %v", privileges)
+	}
+}
+
+func TestAccountsPersistAcrossRestart(t *testing.T) {
+	s := store.NewMemoryStore()
+
+	first := NewAuthService()
+	if err := first.UseStore(s); err != nil {
+		t.Fatalf("UseStore failed: %v", err)
+	}
+	if err := first.CreateUser("alice", "longenough", "ReadOnly", true); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	restarted := NewAuthService()
+	if err := restarted.UseStore(s); err != nil {
+		t.Fatalf("UseStore failed: %v", err)
+	}
+	if !restarted.ValidateBasicAuth("alice", "longenough") {
+		t.Error("Account created before the restart should be able to log in")
+	}
+	if !restarted.ValidateBasicAuth("admin", "password") {
+		t.Error("Default accounts saved on first use should still exist")
+	}
+
+	// Only salted hashes are saved, never the passwords
+	var saved []map[string]any
+	if err := s.Load(usersStateName, &saved); err != nil {
+		t.Fatalf("Failed to load saved accounts: %v", err)
+	}
+	hashes := make(map[string]bool)
+	for _, account := range saved {
+		if data, _ := json.Marshal(account); strings.Contains(string(data), "longenough") || strings.Contains(string(data), `"password"`) {
+			t.Errorf("Expected no plaintext password in the saved account, got %s", data)
+		}
+		hash, _ := account["PasswordHash"].(string)
+		if !strings.HasPrefix(hash, passwordHashScheme+"$") || hashes[hash] {
+			t.Errorf("Expected a distinct salted hash for %v, got %q", account["Username"], hash)
+		}
+		hashes[hash] = true
+	}
+}
+
+func TestPlaintextAccountsAreHashedOnLoad(t *testing.T) {
+	s := store.NewMemoryStore()
+	s.Save(usersStateName, []map[string]any{{"Username": "legacy", "Password": "oldsecret", "Role": "ReadOnly", "Enabled": true}})
+
+	auth := NewAuthService()
+	if err := auth.UseStore(s); err != nil {
+		t.Fatalf("UseStore failed: %v", err)
+	}
+	if !auth.ValidateBasicAuth("legacy", "oldsecret") || auth.ValidateBasicAuth("legacy", "wrong") {
+		t.Error("Expected the legacy account to keep its password")
+	}
+
+	var saved []map[string]any
+	s.Load(usersStateName, &saved)
+	if len(saved) != 1 || saved[0]["Password"] != nil || saved[0]["PasswordHash"] == "" {
+		t.Errorf("Expected the account to be saved again with a hash only, got %v", saved)
+	}
+}
+
+func TestSecureDefaultAccounts(t *testing.T) {
+	auth := NewAuthService()
+	if err := auth.SecureDefaultAccounts(DefaultPassword); !errors.Is(err, ErrPasswordPolicy) {
+		t.Errorf("Expected the default password to be refused, got %v", err)
+	}
+	if err := auth.SecureDefaultAccounts("s3cure-admin"); err != nil {
+		t.Fatalf("Failed to secure the built-in accounts: %v", err)
+	}
+
+	if auth.ValidateBasicAuth("admin", DefaultPassword) || !auth.ValidateBasicAuth("admin", "s3cure-admin") {
+		t.Error("Expected admin to use the configured password only")
+	}
+	if auth.ValidateBasicAuth("operator", DefaultPassword) {
+		t.Error("Expected operator with the default password to be disabled")
+	}
+}
+
+func TestSessionTokens(t *testing.T) {
+	auth := NewAuthService()
+	seen := make(map[string]bool)
+	for range 1000 {
+		token, err := auth.CreateSession("admin")
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		if len(token) != 2*DefaultSessionTokenBytes {
+			t.Fatalf("Expected a %d character token, got %q", 2*DefaultSessionTokenBytes, token)
+		}
+		if seen[token] {
+			t.Fatalf("Token %s was issued twice", token)
+		}
+		seen[token] = true
+	}
+
+	generate, err := RandomTokenGenerator(MinSessionTokenBytes)
+	if err != nil {
+		t.Fatalf("Failed to create a token generator: %v", err)
+	}
+	auth.SetTokenGenerator(generate)
+	if token, _ := auth.CreateSession("admin"); len(token) != 2*MinSessionTokenBytes {
+		t.Errorf("Expected a %d character token, got %q", 2*MinSessionTokenBytes, token)
+	}
+	for _, n := range []int{MinSessionTokenBytes - 1, MaxSessionTokenBytes + 1} {
+		if _, err := RandomTokenGenerator(n); err == nil {
+			t.Errorf("Expected %d byte tokens to be refused", n)
+		}
+	}
+}
+
+func TestSessionTokenCollision(t *testing.T) {
+	auth := NewAuthService()
+	tokens := []string{"first", "first", "second"}
+	auth.SetTokenGenerator(func() (string, error) {
+		token := tokens[0]
+		if len(tokens) > 1 {
+			tokens = tokens[1:]
+		}
+		return token, nil
+	})
+
+	// A token already in use is regenerated rather than replacing the session
+	if token, err := auth.CreateSession("admin"); err != nil || token != "first" {
+		t.Fatalf("Expected the first token, got %q, %v", token, err)
+	}
+	if token, err := auth.CreateSession("operator"); err != nil || token != "second" {
+		t.Fatalf("Expected a colliding token to be regenerated, got %q, %v", token, err)
+	}
+	if username, ok := auth.ValidateSessionToken("first"); !ok || username != "admin" {
+		t.Errorf("Expected the first session to be kept, got %q", username)
+	}
+
+	// A generator that only repeats itself gives up
+	if _, err := auth.CreateSession("operator"); !errors.Is(err, ErrTokenCollision) {
+		t.Errorf("Expected ErrTokenCollision, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package auth
+
+import "sync"
+
+// Authenticator is implemented by authentication providers. The in-memory
+// AuthService is the default; external identity sources such as LDAP or
+// OAuth can be plugged in with SetAuthenticator.
+type Authenticator interface {
+	// AuthenticateBasic validates a username and password
+	AuthenticateBasic(username, password string) bool
+	// ValidateToken validates a session or bearer token and returns the user it belongs to
+	ValidateToken(token string) (string, bool)
+	// Privileges returns the Redfish privileges granted to the user
+	Privileges(username string) []string
+}
+
+// AuthenticateBasic implements Authenticator
+func (a *AuthService) AuthenticateBasic(username, password string) bool {
+	return a.ValidateBasicAuth(username, password)
+}
+
+// ValidateToken implements Authenticator by looking up session tokens
+func (a *AuthService) ValidateToken(token string) (string, bool) {
+	return a.ValidateSessionToken(token)
+}
+
+// Privileges implements Authenticator using the privileges of the user's role
+func (a *AuthService) Privileges(username string) []string {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	user, exists := a.users[username]
+	if !exists {
+		return nil
+	}
+	privileges, _ := RolePrivileges(user.Role)
+	return privileges
+}
+
+var (
+	authenticatorMutex sync.RWMutex
+	authenticator      Authenticator
+)
+
+// SetAuthenticator replaces the authentication provider used by the service.
+// Passing nil restores the default in-memory AuthService.
+func SetAuthenticator(a Authenticator) {
+	authenticatorMutex.Lock()
+	defer authenticatorMutex.Unlock()
+	authenticator = a
+}
+
+// GetAuthenticator returns the configured authentication provider
+func GetAuthenticator() Authenticator {
+	authenticatorMutex.RLock()
+	defer authenticatorMutex.RUnlock()
+	if authenticator == nil {
+		return GetAuthService()
+	}
+	return authenticator
+}
+
+// HasPrivilege reports whether the authenticator grants the user the privilege
+func HasPrivilege(a Authenticator, username, privilege string) bool {
+	for _, p := range a.Privileges(username) {
+		if p == privilege {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,378 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors returned when validating bearer tokens
+var (
+	ErrInvalidToken = errors.New("invalid bearer token")
+	ErrTokenExpired = errors.New("bearer token expired")
+)
+
+// KeySource looks up the public key that verifies a token signature
+type KeySource interface {
+	Key(kid string) (crypto.PublicKey, error)
+}
+
+// staticKey is a KeySource that always returns the same key
+type staticKey struct {
+	key crypto.PublicKey
+}
+
+func (s staticKey) Key(string) (crypto.PublicKey, error) {
+	return s.key, nil
+}
+
+// StaticKey returns a KeySource for a single configured public key
+func StaticKey(key crypto.PublicKey) KeySource {
+	return staticKey{key: key}
+}
+
+// ParsePublicKeyPEM parses a PEM encoded RSA or ECDSA public key or certificate
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
+
+// JWKS is a KeySource backed by a JSON Web Key Set URL. Keys are cached and
+// the set is fetched again when an unknown key ID is seen.
+type JWKS struct {
+	url     string
+	client  *http.Client
+	mutex   sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// jwksRefreshInterval limits how often an unknown key ID triggers a fetch
+const jwksRefreshInterval = time.Minute
+
+// NewJWKS creates a KeySource that fetches keys from the given URL
+func NewJWKS(url string, client *http.Client) *JWKS {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &JWKS{url: url, client: client, keys: make(map[string]crypto.PublicKey)}
+}
+
+// Key implements KeySource
+func (j *JWKS) Key(kid string) (crypto.PublicKey, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	if !j.fetched.IsZero() && time.Since(j.fetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown key ID %q", ErrInvalidToken, kid)
+	}
+
+	keys, err := j.fetch()
+	j.fetched = time.Now()
+	if err != nil {
+		return nil, err
+	}
+	j.keys = keys
+
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key ID %q", ErrInvalidToken, kid)
+}
+
+// fetch downloads and parses the key set. Unsupported keys are skipped.
+func (j *JWKS) fetch() (map[string]crypto.PublicKey, error) {
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := decodeBigInt(jwk.N)
+			e, errE := decodeBigInt(jwk.E)
+			if errN != nil || errE != nil || !e.IsInt64() {
+				continue
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			if jwk.Crv != "P-256" {
+				continue
+			}
+			x, errX := decodeBigInt(jwk.X)
+			y, errY := decodeBigInt(jwk.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		}
+	}
+	return keys, nil
+}
+
+// decodeBigInt decodes a base64url encoded unsigned big-endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// JWTValidator validates bearer JWTs signed with RS256 or ES256 and maps a
+// claim to a Redfish role
+type JWTValidator struct {
+	Keys      KeySource
+	RoleClaim string            // Claim holding the role; defaults to "role"
+	RoleMap   map[string]string // Claim value to RoleId; unmapped values are used as-is
+	Issuer    string            // Required "iss" when set
+	Audience  string            // Required "aud" entry when set
+	// DefaultRole is granted when the role claim is missing or names no
+	// known role; when empty such tokens are rejected
+	DefaultRole string
+	Now         func() time.Time // Defaults to time.Now
+}
+
+// Validate verifies the token and returns the subject and Redfish role it grants
+func (v *JWTValidator) Validate(token string) (string, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", "", err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", "", fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	key, err := v.Keys.Key(header.Kid)
+	if err != nil {
+		return "", "", err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return "", "", err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", "", err
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return "", "", err
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return "", "", fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+
+	role, err := v.role(claims)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, role, nil
+}
+
+// checkClaims validates the time, issuer and audience claims
+func (v *JWTValidator) checkClaims(claims map[string]interface{}) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: missing exp claim", ErrInvalidToken)
+	}
+	if !now.Before(time.Unix(int64(exp), 0)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+
+	if v.Issuer != "" && claims["iss"] != v.Issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.Audience != "" && !containsClaim(claims["aud"], v.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+// role maps the role claim to a predefined Redfish role
+func (v *JWTValidator) role(claims map[string]interface{}) (string, error) {
+	claimName := v.RoleClaim
+	if claimName == "" {
+		claimName = "role"
+	}
+
+	var values []string
+	switch claim := claims[claimName].(type) {
+	case string:
+		values = []string{claim}
+	case []interface{}:
+		for _, item := range claim {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	for _, value := range values {
+		role := value
+		if mapped, ok := v.RoleMap[value]; ok {
+			role = mapped
+		}
+		if _, ok := rolePrivileges[role]; ok {
+			return role, nil
+		}
+	}
+	if v.DefaultRole != "" {
+		return v.DefaultRole, nil
+	}
+	return "", fmt.Errorf("%w: no recognised role in %s claim", ErrInvalidToken, claimName)
+}
+
+// containsClaim reports whether a string or string array claim contains want
+func containsClaim(claim interface{}, want string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == want
+	case []interface{}:
+		for _, item := range c {
+			if item == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url encoded JSON token segment
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	return nil
+}
+
+// verifySignature checks a JWS signature for the supported algorithms
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: key does not match algorithm %s", ErrInvalidToken, alg)
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("%w: signature verification failed", ErrInvalidToken)
+		}
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return fmt.Errorf("%w: key does not match algorithm %s", ErrInvalidToken, alg)
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return fmt.Errorf("%w: signature verification failed", ErrInvalidToken)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+	}
+	return nil
+}
+
+// BearerAuthenticator is implemented by providers that accept bearer tokens
+type BearerAuthenticator interface {
+	// AuthenticateBearer validates the token and returns the user and the Redfish role it grants
+	AuthenticateBearer(token string) (string, string, bool)
+}
+
+// bearerAuthenticator adds JWT bearer token support to another provider
+type bearerAuthenticator struct {
+	Authenticator
+	validator *JWTValidator
+}
+
+// WithBearer extends an authenticator with JWT bearer token validation
+func WithBearer(base Authenticator, validator *JWTValidator) Authenticator {
+	return &bearerAuthenticator{Authenticator: base, validator: validator}
+}
+
+// AuthenticateBearer implements BearerAuthenticator
+func (b *bearerAuthenticator) AuthenticateBearer(token string) (string, string, bool) {
+	username, role, err := b.validator.Validate(token)
+	if err != nil {
+		return "", "", false
+	}
+	return username, role, true
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// signES256 creates a compact JWS for the claims signed with the key
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTValidator(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	validator := &JWTValidator{
+		Keys:     StaticKey(&key.PublicKey),
+		RoleMap:  map[string]string{"admins": "Administrator"},
+		Audience: "redfish",
+	}
+	exp := float64(time.Now().Add(time.Hour).Unix())
+
+	tests := []struct {
+		name     string
+		token    string
+		wantErr  error
+		wantRole string
+	}{
+		{"valid mapped role", signES256(t, key, "", map[string]interface{}{"sub": "alice", "role": "admins", "exp": exp, "aud": "redfish"}), nil, "Administrator"},
+		{"valid direct role", signES256(t, key, "", map[string]interface{}{"sub": "bob", "role": "Operator", "exp": exp, "aud": []string{"other", "redfish"}}), nil, "Operator"},
+		{"expired", signES256(t, key, "", map[string]interface{}{"sub": "alice", "role": "admins", "exp": float64(time.Now().Add(-time.Minute).Unix()), "aud": "redfish"}), ErrTokenExpired, ""},
+		{"wrong signature", signES256(t, otherKey, "", map[string]interface{}{"sub": "alice", "role": "admins", "exp": exp, "aud": "redfish"}), ErrInvalidToken, ""},
+		{"unknown role", signES256(t, key, "", map[string]interface{}{"sub": "alice", "role": "guests", "exp": exp, "aud": "redfish"}), ErrInvalidToken, ""},
+		{"wrong audience", signES256(t, key, "", map[string]interface{}{"sub": "alice", "role": "admins", "exp": exp, "aud": "elsewhere"}), ErrInvalidToken, ""},
+		{"missing exp", signES256(t, key, "", map[string]interface{}{"sub": "alice", "role": "admins", "aud": "redfish"}), ErrInvalidToken, ""},
+		{"unsigned", "eyJhbGciOiJub25lIn0.eyJzdWIiOiJhbGljZSJ9.", ErrInvalidToken, ""},
+		{"malformed", "not-a-token", ErrInvalidToken, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, role, err := validator.Validate(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if role != tt.wantRole {
+				t.Errorf("Expected role %q, got %q", tt.wantRole, role)
+			}
+		})
+	}
+}
+
+func TestJWTValidatorDefaultRole(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	validator := &JWTValidator{Keys: StaticKey(&key.PublicKey), DefaultRole: "ReadOnly"}
+	exp := float64(time.Now().Add(time.Hour).Unix())
+
+	for _, claims := range []map[string]interface{}{
+		{"sub": "erin", "role": "guests", "exp": exp},
+		{"sub": "erin", "exp": exp},
+	} {
+		_, role, err := validator.Validate(signES256(t, key, "", claims))
+		if err != nil || role != "ReadOnly" {
+			t.Errorf("Expected %v to be granted the default role, got %q, %v", claims, role, err)
+		}
+	}
+}
+
+func TestJWTValidatorRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicKey, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("Failed to parse PEM public key: %v", err)
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	payload, _ := json.Marshal(map[string]interface{}{"sub": "carol", "role": "ReadOnly", "exp": time.Now().Add(time.Hour).Unix()})
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	token := input + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	validator := &JWTValidator{Keys: StaticKey(publicKey)}
+	subject, role, err := validator.Validate(token)
+	if err != nil {
+		t.Fatalf("Expected token to validate, got %v", err)
+	}
+	if subject != "carol" || role != "ReadOnly" {
+		t.Errorf("Expected carol/ReadOnly, got %s/%s", subject, role)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	encode := func(n *big.Int) string {
+		b := make([]byte, 32)
+		n.FillBytes(b)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	fetches := 0
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{"kid": "k1", "kty": "EC", "crv": "P-256", "x": encode(key.X), "y": encode(key.Y)},
+			},
+		})
+	}))
+	defer jwks.Close()
+
+	validator := &JWTValidator{Keys: NewJWKS(jwks.URL, jwks.Client())}
+	claims := map[string]interface{}{"sub": "dave", "role": "Operator", "exp": time.Now().Add(time.Hour).Unix()}
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := validator.Validate(signES256(t, key, "k1", claims)); err != nil {
+			t.Fatalf("Expected token to validate, got %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected the key set to be fetched once, got %d", fetches)
+	}
+
+	if _, _, err := validator.Validate(signES256(t, key, "unknown", claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected unknown key ID to be rejected, got %v", err)
+	}
+}
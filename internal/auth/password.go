@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Passwords are kept as salted PBKDF2-SHA256 hashes, encoded as
+// "pbkdf2-sha256$<iterations>$<salt>$<hash>" with the salt and hash in
+// unpadded base64, so neither memory nor the account store holds them in
+// plaintext
+const (
+	passwordHashScheme     = "pbkdf2-sha256"
+	passwordHashIterations = 100000
+	passwordSaltBytes      = 16
+	passwordHashBytes      = 32
+)
+
+// hashPassword returns the encoded hash of password under a new random salt
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltBytes)
+	rand.Read(salt)
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordHashIterations, passwordHashBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return fmt.Sprintf("%s$%d$%s$%s", passwordHashScheme, passwordHashIterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verifyPassword reports whether password matches an encoded hash
+func verifyPassword(encoded, password string) bool {
+	fields := strings.Split(encoded, "$")
+	if len(fields) != 4 || fields[0] != passwordHashScheme {
+		return false
+	}
+	iterations, err := strconv.Atoi(fields[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(fields[3])
+	if err != nil || len(want) != passwordHashBytes {
+		return false
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, passwordHashBytes)
+	return err == nil && subtle.ConstantTimeCompare(key, want) == 1
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AccountPolicy holds the password and lockout settings of the AccountService
+type AccountPolicy struct {
+	MinPasswordLength               int
+	MaxPasswordLength               int
+	AccountLockoutThreshold         int // Failed logins before lockout; 0 disables lockout
+	AccountLockoutDuration          int // Seconds an account stays locked; 0 locks until unlocked
+	AccountLockoutCounterResetAfter int // Seconds after which the failed login count resets
+}
+
+// Limits accepted when the account policy is changed
+const (
+	MaxPasswordLengthLimit       = 256
+	MaxAccountLockoutThreshold   = 100
+	MaxAccountLockoutDurationSec = 86400
+)
+
+// ErrPasswordPolicy is returned when a password does not satisfy the account policy
+var ErrPasswordPolicy = errors.New("password does not satisfy the account policy")
+
+// PolicyRangeError reports an account policy property outside its allowed range
+type PolicyRangeError struct {
+	Property string
+	Value    int
+	Min      int
+	Max      int
+}
+
+func (e *PolicyRangeError) Error() string {
+	return fmt.Sprintf("%s must be between %d and %d, got %d", e.Property, e.Min, e.Max, e.Value)
+}
+
+// DefaultAccountPolicy returns the policy the service starts with
+func DefaultAccountPolicy() AccountPolicy {
+	return AccountPolicy{
+		MinPasswordLength:               8,
+		MaxPasswordLength:               64,
+		AccountLockoutThreshold:         5,
+		AccountLockoutDuration:          300,  // 5 minutes
+		AccountLockoutCounterResetAfter: 1800, // 30 minutes
+	}
+}
+
+// Validate checks that every policy value is within its allowed range
+func (p AccountPolicy) Validate() error {
+	checks := []PolicyRangeError{
+		{"MaxPasswordLength", p.MaxPasswordLength, 1, MaxPasswordLengthLimit},
+		{"MinPasswordLength", p.MinPasswordLength, 1, p.MaxPasswordLength},
+		{"AccountLockoutThreshold", p.AccountLockoutThreshold, 0, MaxAccountLockoutThreshold},
+		{"AccountLockoutDuration", p.AccountLockoutDuration, 0, MaxAccountLockoutDurationSec},
+		{"AccountLockoutCounterResetAfter", p.AccountLockoutCounterResetAfter, 0, MaxAccountLockoutDurationSec},
+	}
+	for _, check := range checks {
+		if check.Value < check.Min || check.Value > check.Max {
+			err := check
+			return &err
+		}
+	}
+	return nil
+}
+
+// ValidatePassword checks a password against the policy's length limits
+func (p AccountPolicy) ValidatePassword(password string) error {
+	if len(password) < p.MinPasswordLength || len(password) > p.MaxPasswordLength {
+		return fmt.Errorf("%w: length must be between %d and %d characters", ErrPasswordPolicy, p.MinPasswordLength, p.MaxPasswordLength)
+	}
+	return nil
+}
+
+// loginFailures tracks failed logins of a single account
+type loginFailures struct {
+	count       int
+	first       time.Time
+	locked      bool
+	lockedUntil time.Time // Zero when locked until explicitly unlocked
+}
+
+// GetAccountPolicy returns the account policy currently in effect
+func (a *AuthService) GetAccountPolicy() AccountPolicy {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.policy
+}
+
+// SetAccountPolicy validates, replaces and saves the account policy
+func (a *AuthService) SetAccountPolicy(policy AccountPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	previous := a.policy
+	a.policy = policy
+	if err := a.savePolicyLocked(); err != nil {
+		a.policy = previous
+		return err
+	}
+	return nil
+}
+
+// IsLocked reports whether the account is locked out after failed logins
+func (a *AuthService) IsLocked(username string) bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.lockedLocked(username, a.clock.Now())
+}
+
+// lockedLocked reports whether the account is locked at the given time.
+// The caller must hold the mutex.
+func (a *AuthService) lockedLocked(username string, now time.Time) bool {
+	f, exists := a.failures[username]
+	if !exists || !f.locked {
+		return false
+	}
+	return f.lockedUntil.IsZero() || now.Before(f.lockedUntil)
+}
+
+// recordFailureLocked counts a failed login and locks the account once the
+// policy threshold is reached. The caller must hold the write lock.
+func (a *AuthService) recordFailureLocked(username string, now time.Time) {
+	f, exists := a.failures[username]
+	resetAfter := time.Duration(a.policy.AccountLockoutCounterResetAfter) * time.Second
+	if !exists || (f.locked && !a.lockedLocked(username, now)) || (resetAfter > 0 && now.Sub(f.first) > resetAfter) {
+		f = &loginFailures{first: now}
+		a.failures[username] = f
+	}
+
+	f.count++
+	if a.policy.AccountLockoutThreshold > 0 && f.count >= a.policy.AccountLockoutThreshold {
+		f.locked = true
+		if a.policy.AccountLockoutDuration > 0 {
+			f.lockedUntil = now.Add(time.Duration(a.policy.AccountLockoutDuration) * time.Second)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// TokenGenerator creates session tokens. The tokens must be unpredictable
+// and safe to carry in an X-Auth-Token header and a session URI.
+type TokenGenerator func() (string, error)
+
+// Bounds on the random bytes in a session token. Tokens are hex encoded, so
+// they are twice as many characters long.
+const (
+	MinSessionTokenBytes     = 16
+	MaxSessionTokenBytes     = 64
+	DefaultSessionTokenBytes = 32
+)
+
+// maxTokenAttempts is how many tokens are generated before giving up on
+// finding one that no session already uses
+const maxTokenAttempts = 3
+
+// ErrTokenCollision is returned when every token generated for a new
+// session was already in use
+var ErrTokenCollision = errors.New("could not generate a unique session token")
+
+// RandomTokenGenerator returns a generator of tokens made of n random
+// bytes, hex encoded. n must be between MinSessionTokenBytes and
+// MaxSessionTokenBytes.
+func RandomTokenGenerator(n int) (TokenGenerator, error) {
+	if n < MinSessionTokenBytes || n > MaxSessionTokenBytes {
+		return nil, fmt.Errorf("session tokens must hold %d to %d random bytes, not %d", MinSessionTokenBytes, MaxSessionTokenBytes, n)
+	}
+	return func() (string, error) {
+		tokenBytes := make([]byte, n)
+		if _, err := rand.Read(tokenBytes); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(tokenBytes), nil
+	}, nil
+}
+
+// defaultTokenGenerator is used until SetTokenGenerator replaces it
+var defaultTokenGenerator, _ = RandomTokenGenerator(DefaultSessionTokenBytes)
+
+// SetTokenGenerator replaces how session tokens are generated. A nil
+// generator restores the default of DefaultSessionTokenBytes random bytes.
+func (a *AuthService) SetTokenGenerator(generate TokenGenerator) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.generateToken = generate
+}
+
+// newSessionTokenLocked generates a token that no session uses, retrying
+// on the rare collision rather than replacing another session. The caller
+// must hold the write lock.
+func (a *AuthService) newSessionTokenLocked() (string, error) {
+	generate := a.generateToken
+	if generate == nil {
+		generate = defaultTokenGenerator
+	}
+	for range maxTokenAttempts {
+		token, err := generate()
+		if err != nil {
+			return "", err
+		}
+		if _, exists := a.sessions[token]; !exists {
+			return token, nil
+		}
+	}
+	return "", ErrTokenCollision
+}
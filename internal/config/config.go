@@ -3,20 +3,73 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // Config holds all configuration for the Redfish server
 type Config struct {
-	Server ServerConfig
-	TLS    TLSConfig
+	// DevMode relaxes the secure defaults for local development: TLS may be
+	// disabled or use a generated certificate, CORS admits any origin, and
+	// the built-in accounts keep their well-known password. Without it, TLS
+	// must use certificate files and the administrator password must be
+	// configured.
+	DevMode bool
+
+	Server  ServerConfig
+	TLS     TLSConfig
+	Auth    AuthConfig
+	Service ServiceConfig
+	State   StateConfig
+	Reset   ResetConfig
+	Events  EventsConfig
+	Tasks   TasksConfig
+	Chassis ChassisConfig
 }
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
-	Address      string
-	ReadTimeout  int // seconds
+	Address           string
+	ReadTimeout       int // seconds
+	ReadHeaderTimeout int // seconds
+	// WriteTimeout bounds ordinary responses. Long-lived streams such as the
+	// SSE endpoint clear their own write deadline so they are not cut off.
 	WriteTimeout int // seconds
+	IdleTimeout  int // seconds
+	// MaxExpandLevels is the deepest $expand ($levels) a client may request,
+	// and what the ServiceRoot advertises in ProtocolFeaturesSupported. Zero
+	// selects DefaultMaxExpandLevels.
+	MaxExpandLevels int
+	// BasePath is the sub-path the service is published under by a reverse
+	// proxy, e.g. /bmc1. It is prepended to generated links and stripped from
+	// incoming request paths.
+	BasePath string
+	// AllowMethodOverride lets a POST carrying X-HTTP-Method-Override act as
+	// the PATCH, PUT or DELETE it names, for clients that can only send GET
+	// and POST. Off by default.
+	AllowMethodOverride bool
+	// CORSAllowedOrigins are the origins browsers may make cross-origin
+	// requests from. DevMode admits any origin.
+	CORSAllowedOrigins []string
+	// MaxHeaderBytes bounds the size of request headers. Zero selects the
+	// net/http default of 1 MB.
+	MaxHeaderBytes int
+	// MaxConnections caps the concurrent client connections; connections
+	// beyond it are refused. Zero means no limit.
+	MaxConnections int
+	// DisableKeepAlives closes each connection after one request
+	DisableKeepAlives bool
+	// ContentSecurityPolicy and FrameOptions are sent with every response
+	// but event streams; an empty value leaves the header out
+	ContentSecurityPolicy string
+	FrameOptions          string
+	// HSTSMaxAge is the Strict-Transport-Security max-age sent over TLS;
+	// zero leaves the header out
+	HSTSMaxAge int // seconds
+	// SlowRequestThreshold is the latency beyond which a request is logged
+	// as a warning; zero turns the warning off. Event streams are exempt.
+	SlowRequestThreshold int // milliseconds
 }
 
 // TLSConfig holds TLS-specific configuration
@@ -24,20 +77,277 @@ type TLSConfig struct {
 	Enabled  bool
 	CertFile string
 	KeyFile  string
+	// AutoGenerate creates an in-memory self-signed certificate when the
+	// cert/key files are missing. Intended for development only; when it is
+	// off the files must exist.
+	AutoGenerate      bool
+	AutoGenerateHosts []string // SANs for the generated cert; the first is also the CN
+	// Hostname is the public name clients reach the service by. The loaded
+	// certificate is checked against it, or against the host of the server
+	// address when it is unset. A mismatch is logged as a warning, or fails
+	// startup when StrictHostname is set.
+	Hostname       string
+	StrictHostname bool
+}
+
+// AuthConfig holds authentication configuration. Bearer token (OAuth2/JWT)
+// authentication is enabled when a public key file or a JWKS URL is set.
+type AuthConfig struct {
+	JWTPublicKeyFile string            // PEM public key or certificate that signs tokens
+	JWTJWKSURL       string            // JSON Web Key Set URL, as an alternative to a key file
+	JWTRoleClaim     string            // Claim holding the user's role
+	JWTRoleMap       map[string]string // Claim value to Redfish RoleId; unmapped values are used as-is
+	JWTIssuer        string            // Required "iss" claim, if set
+	JWTAudience      string            // Required "aud" claim, if set
+	// AdminPassword replaces the well-known password of the built-in admin
+	// account. It is required unless DevMode is set.
+	AdminPassword string
+	// DefaultRoleId is the role of accounts created without a RoleId and of
+	// bearer tokens whose role claim names no known role. Empty selects
+	// DefaultRoleId for accounts and rejects such tokens.
+	DefaultRoleId string
+	// PublicRoutes are the requests served without authentication, each
+	// "[METHOD ]path" with a trailing "/*" matching a whole subtree. Empty
+	// selects DefaultPublicRoutes; leaving "GET /redfish/v1" out of a
+	// configured list makes the service root require authentication.
+	PublicRoutes []string
+	// SessionTokenBytes is the number of random bytes in a session token,
+	// which is hex encoded; the server accepts 16 to 64. Zero selects 32.
+	SessionTokenBytes int
+}
+
+// BearerEnabled reports whether bearer token authentication is configured
+func (a AuthConfig) BearerEnabled() bool {
+	return a.JWTPublicKeyFile != "" || a.JWTJWKSURL != ""
+}
+
+// ServiceConfig identifies this service instance in the ServiceRoot
+type ServiceConfig struct {
+	Name string
+	// UUID is the stable identity of the instance. When empty, one is
+	// generated on first start and persisted to UUIDFile so it survives
+	// restarts; with no UUIDFile a new UUID is generated on every start.
+	UUID           string
+	UUIDFile       string
+	RedfishVersion string
+
+	// Identification tells people which instance they are talking to. When
+	// any of it is set it is served in the ServiceRoot's Oem block.
+	Welcome    string
+	Datacenter string
+	Rack       string
+	Contact    string
+}
+
+// StateConfig controls persistence of accounts and event subscriptions
+type StateConfig struct {
+	// Dir holds the JSON state files. When empty, state is kept in memory
+	// only and lost on restart.
+	Dir string
+}
+
+// ResetConfig narrows the reset types individual systems and managers
+// support, keyed by resource Id. Resources that are not listed support the
+// service defaults. A system listed with no types does not support reset at
+// all, e.g. RESET_TYPES_SYSTEMS="2=".
+type ResetConfig struct {
+	SystemTypes  map[string][]string
+	ManagerTypes map[string][]string
+}
+
+// EventsConfig controls the event delivery mechanisms, which are all on by
+// default
+type EventsConfig struct {
+	// DisableSSE removes the EventService's Server-Sent Events stream
+	DisableSSE bool
+	// SSEHeartbeatInterval is how often an idle SSE stream receives a
+	// heartbeat, which keeps proxies from closing it. Zero selects
+	// DefaultSSEHeartbeatInterval.
+	SSEHeartbeatInterval int // seconds
+	// MaxSSEConnections caps the concurrent SSE streams; zero means no limit
+	MaxSSEConnections int
+	// DestinationAllow, when set, lists the only hosts, IP addresses and
+	// CIDR ranges subscription destinations may resolve to. Loopback and
+	// link-local addresses are otherwise refused.
+	DestinationAllow []string
+	// DestinationDeny lists hosts, IP addresses and CIDR ranges subscription
+	// destinations may never resolve to, even when allowed
+	DestinationDeny []string
+	// DeliveryWorkers caps the event deliveries in flight at once, and
+	// DeliveryQueueSize the deliveries waiting for a worker. Zero selects
+	// DefaultDeliveryWorkers and DefaultDeliveryQueueSize.
+	DeliveryWorkers   int
+	DeliveryQueueSize int
+	// DeliveryQueueFullPolicy is what happens to a delivery when the queue
+	// is full: DropNewest discards it, DropOldest discards the longest
+	// waiting delivery to make room, and Block holds up the publisher until
+	// there is room. Empty selects DropNewest.
+	DeliveryQueueFullPolicy string
+}
+
+// TasksConfig controls how many tasks the TaskService keeps
+type TasksConfig struct {
+	// MaxTasks caps the tasks kept at once; zero means no limit
+	MaxTasks int
+	// CompletedTaskOverWritePolicy is what happens once MaxTasks is reached:
+	// Oldest deletes the oldest finished task to make room, while Manual
+	// refuses new tasks until clients delete some. Empty selects Manual.
+	CompletedTaskOverWritePolicy string
+}
+
+// ChassisConfig controls the resources chassis expose
+type ChassisConfig struct {
+	// PowerThermalModel selects the power and thermal resources: Legacy
+	// serves the deprecated Power and Thermal, Subsystems serves
+	// PowerSubsystem and ThermalSubsystem, and Both serves all of them.
+	// Empty selects DefaultPowerThermalModel.
+	PowerThermalModel string
+}
+
+// PowerThermalModels lists the supported PowerThermalModel values
+var PowerThermalModels = []string{"Legacy", "Subsystems", "Both"}
+
+// DefaultPowerThermalModel serves both the legacy and the subsystem power
+// and thermal resources, for clients written against either schema
+const DefaultPowerThermalModel = "Both"
+
+// DeliveryQueueFullPolicies lists the supported DeliveryQueueFullPolicy
+// values
+var DeliveryQueueFullPolicies = []string{"DropNewest", "DropOldest", "Block"}
+
+// DefaultDeliveryWorkers and DefaultDeliveryQueueSize size the event
+// delivery pool when it is not configured
+const (
+	DefaultDeliveryWorkers   = 8
+	DefaultDeliveryQueueSize = 1024
+)
+
+// CompletedTaskOverWritePolicies lists the supported
+// CompletedTaskOverWritePolicy values
+var CompletedTaskOverWritePolicies = []string{"Manual", "Oldest"}
+
+// ResetTypes lists every ResetType value defined by the Redfish Resource schema
+var ResetTypes = []string{
+	"On", "ForceOff", "GracefulShutdown", "GracefulRestart", "ForceRestart", "Nmi",
+	"ForceOn", "PushPowerButton", "PowerCycle", "Suspend", "Pause", "Resume", "FullPowerCycle",
+}
+
+// DefaultMaxExpandLevels is the $expand depth allowed when none is configured
+const DefaultMaxExpandLevels = 2
+
+// DefaultSSEHeartbeatInterval is the SSE heartbeat interval, in seconds,
+// when none is configured
+const DefaultSSEHeartbeatInterval = 30
+
+// DefaultSlowRequestThreshold is the latency, in milliseconds, beyond which
+// requests are logged as slow when no threshold is configured
+const DefaultSlowRequestThreshold = 2000
+
+// DefaultContentSecurityPolicy forbids loading or framing anything, which
+// suits a JSON API that serves no pages
+const DefaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// DefaultRoleId is the role of accounts created without one when no default
+// role is configured
+const DefaultRoleId = "ReadOnly"
+
+// DefaultPublicRoutes are served without authentication when none are
+// configured: the health check, service root and metadata, and login
+var DefaultPublicRoutes = []string{
+	"GET /health",
+	"GET /redfish",
+	"GET /redfish/v1",
+	"GET /redfish/v1/$metadata",
+	"GET /redfish/v1/odata",
+	"POST /redfish/v1/SessionService/Sessions",
+	"POST /redfish/v1/SessionService/Sessions/Members",
 }
 
 // Load loads configuration from environment variables with defaults
 func Load() (*Config, error) {
 	cfg := &Config{
+		DevMode: getEnvAsBool("DEV_MODE", false),
 		Server: ServerConfig{
-			Address:      getEnv("SERVER_ADDRESS", ":8443"),
-			ReadTimeout:  getEnvAsInt("SERVER_READ_TIMEOUT", 30),
-			WriteTimeout: getEnvAsInt("SERVER_WRITE_TIMEOUT", 30),
+			Address:           getEnv("SERVER_ADDRESS", ":8443"),
+			ReadTimeout:       getEnvAsInt("SERVER_READ_TIMEOUT", 30),
+			ReadHeaderTimeout: getEnvAsInt("SERVER_READ_HEADER_TIMEOUT", 10),
+			WriteTimeout:      getEnvAsInt("SERVER_WRITE_TIMEOUT", 30),
+			IdleTimeout:       getEnvAsInt("SERVER_IDLE_TIMEOUT", 120),
+			MaxExpandLevels:   getEnvAsInt("SERVER_MAX_EXPAND_LEVELS", DefaultMaxExpandLevels),
+			BasePath:          getEnv("SERVER_BASE_PATH", ""),
+
+			AllowMethodOverride: getEnvAsBool("SERVER_ALLOW_METHOD_OVERRIDE", false),
+			CORSAllowedOrigins:  getEnvAsSlice("SERVER_CORS_ALLOWED_ORIGINS", nil),
+
+			MaxHeaderBytes:    getEnvAsInt("SERVER_MAX_HEADER_BYTES", 0),
+			MaxConnections:    getEnvAsInt("SERVER_MAX_CONNECTIONS", 0),
+			DisableKeepAlives: getEnvAsBool("SERVER_DISABLE_KEEPALIVES", false),
+
+			ContentSecurityPolicy: getEnv("SERVER_CONTENT_SECURITY_POLICY", DefaultContentSecurityPolicy),
+			FrameOptions:          getEnv("SERVER_FRAME_OPTIONS", "DENY"),
+			HSTSMaxAge:            getEnvAsInt("SERVER_HSTS_MAX_AGE", 31536000),
+
+			SlowRequestThreshold: getEnvAsInt("SERVER_SLOW_REQUEST_THRESHOLD", DefaultSlowRequestThreshold),
 		},
 		TLS: TLSConfig{
 			Enabled:  getEnvAsBool("TLS_ENABLED", true),
 			CertFile: getEnv("TLS_CERT_FILE", "certs/server.crt"),
 			KeyFile:  getEnv("TLS_KEY_FILE", "certs/server.key"),
+
+			AutoGenerate:      getEnvAsBool("TLS_AUTO_GENERATE", false),
+			AutoGenerateHosts: getEnvAsSlice("TLS_AUTO_GENERATE_HOSTS", []string{"localhost", "127.0.0.1"}),
+
+			Hostname:       getEnv("TLS_HOSTNAME", ""),
+			StrictHostname: getEnvAsBool("TLS_STRICT_HOSTNAME", false),
+		},
+		Auth: AuthConfig{
+			JWTPublicKeyFile: getEnv("AUTH_JWT_PUBLIC_KEY_FILE", ""),
+			JWTJWKSURL:       getEnv("AUTH_JWT_JWKS_URL", ""),
+			JWTRoleClaim:     getEnv("AUTH_JWT_ROLE_CLAIM", "role"),
+			JWTRoleMap:       getEnvAsMap("AUTH_JWT_ROLE_MAP"),
+			JWTIssuer:        getEnv("AUTH_JWT_ISSUER", ""),
+			JWTAudience:      getEnv("AUTH_JWT_AUDIENCE", ""),
+
+			AdminPassword: getEnv("AUTH_ADMIN_PASSWORD", ""),
+			DefaultRoleId: getEnv("AUTH_DEFAULT_ROLE", DefaultRoleId),
+			PublicRoutes:  getEnvAsSlice("AUTH_PUBLIC_ROUTES", nil),
+
+			SessionTokenBytes: getEnvAsInt("AUTH_SESSION_TOKEN_BYTES", 0),
+		},
+		Service: ServiceConfig{
+			Name:           getEnv("SERVICE_NAME", "Root Service"),
+			UUID:           getEnv("SERVICE_UUID", ""),
+			UUIDFile:       getEnv("SERVICE_UUID_FILE", "data/service_uuid"),
+			RedfishVersion: getEnv("SERVICE_REDFISH_VERSION", "1.15.0"),
+			Welcome:        getEnv("SERVICE_WELCOME", ""),
+			Datacenter:     getEnv("SERVICE_DATACENTER", ""),
+			Rack:           getEnv("SERVICE_RACK", ""),
+			Contact:        getEnv("SERVICE_CONTACT", ""),
+		},
+		State: StateConfig{
+			Dir: getEnv("STATE_DIR", ""),
+		},
+		Reset: ResetConfig{
+			SystemTypes:  getEnvAsListMap("RESET_TYPES_SYSTEMS"),
+			ManagerTypes: getEnvAsListMap("RESET_TYPES_MANAGERS"),
+		},
+		Events: EventsConfig{
+			DisableSSE:           getEnvAsBool("EVENTS_DISABLE_SSE", false),
+			SSEHeartbeatInterval: getEnvAsInt("EVENTS_SSE_HEARTBEAT_INTERVAL", DefaultSSEHeartbeatInterval),
+			MaxSSEConnections:    getEnvAsInt("EVENTS_SSE_MAX_CONNECTIONS", 16),
+			DestinationAllow:     getEnvAsSlice("EVENTS_DESTINATION_ALLOW", nil),
+			DestinationDeny:      getEnvAsSlice("EVENTS_DESTINATION_DENY", nil),
+
+			DeliveryWorkers:         getEnvAsInt("EVENTS_DELIVERY_WORKERS", DefaultDeliveryWorkers),
+			DeliveryQueueSize:       getEnvAsInt("EVENTS_DELIVERY_QUEUE_SIZE", DefaultDeliveryQueueSize),
+			DeliveryQueueFullPolicy: getEnv("EVENTS_DELIVERY_QUEUE_FULL_POLICY", "DropNewest"),
+		},
+		Chassis: ChassisConfig{
+			PowerThermalModel: getEnv("CHASSIS_POWER_THERMAL_MODEL", DefaultPowerThermalModel),
+		},
+		Tasks: TasksConfig{
+			MaxTasks:                     getEnvAsInt("TASKS_MAX", 0),
+			CompletedTaskOverWritePolicy: getEnv("TASKS_OVERWRITE_POLICY", "Manual"),
 		},
 	}
 
@@ -72,15 +382,175 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvAsSlice gets a comma-separated environment variable as a slice or returns a default value
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return defaultValue
+}
+
+// getEnvAsMap gets a comma-separated list of key=value pairs as a map
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range getEnvAsSlice(key, nil) {
+		if k, v, ok := strings.Cut(pair, "="); ok {
+			result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return result
+}
+
+// getEnvAsListMap gets a comma-separated list of key=value pairs whose values
+// are |-separated lists, e.g. "1=On|ForceOff,2=On"
+func getEnvAsListMap(key string) map[string][]string {
+	result := make(map[string][]string)
+	for k, v := range getEnvAsMap(key) {
+		var items []string
+		for _, item := range strings.Split(v, "|") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		result[k] = items
+	}
+	return result
+}
+
+// validateResetTypes checks that every configured resource supports only
+// types the Redfish schema defines and, unless allowNone is set, at least one
+func validateResetTypes(kind string, types map[string][]string, allowNone bool) error {
+	for id, allowed := range types {
+		if len(allowed) == 0 && !allowNone {
+			return fmt.Errorf("%s %s must support at least one reset type", kind, id)
+		}
+		for _, resetType := range allowed {
+			if !slices.Contains(ResetTypes, resetType) {
+				return fmt.Errorf("%s %s: unknown reset type %q", kind, id, resetType)
+			}
+		}
+	}
+	return nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.Server.Address == "" {
 		return fmt.Errorf("server address cannot be empty")
 	}
+	if c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		return fmt.Errorf("server timeouts cannot be negative")
+	}
+	if c.Server.MaxExpandLevels < 0 {
+		return fmt.Errorf("maximum $expand levels cannot be negative")
+	}
+	if c.Server.MaxHeaderBytes < 0 || c.Server.MaxConnections < 0 {
+		return fmt.Errorf("maximum header bytes and connections cannot be negative")
+	}
+	if c.Server.HSTSMaxAge < 0 {
+		return fmt.Errorf("HSTS max-age cannot be negative")
+	}
+	if c.Auth.SessionTokenBytes < 0 {
+		return fmt.Errorf("session token length cannot be negative")
+	}
+	if c.Server.SlowRequestThreshold < 0 {
+		return fmt.Errorf("slow request threshold cannot be negative")
+	}
+	if c.Events.SSEHeartbeatInterval < 0 || c.Events.MaxSSEConnections < 0 {
+		return fmt.Errorf("SSE heartbeat interval and connection limit cannot be negative")
+	}
+	if c.Events.DeliveryWorkers < 0 || c.Events.DeliveryQueueSize < 0 {
+		return fmt.Errorf("event delivery workers and queue size cannot be negative")
+	}
+	if policy := c.Events.DeliveryQueueFullPolicy; policy != "" && !slices.Contains(DeliveryQueueFullPolicies, policy) {
+		return fmt.Errorf("unknown event delivery queue full policy %q", policy)
+	}
+	if c.Tasks.MaxTasks < 0 {
+		return fmt.Errorf("maximum tasks cannot be negative")
+	}
+	if policy := c.Tasks.CompletedTaskOverWritePolicy; policy != "" && !slices.Contains(CompletedTaskOverWritePolicies, policy) {
+		return fmt.Errorf("unknown completed task overwrite policy %q", policy)
+	}
+	if model := c.Chassis.PowerThermalModel; model != "" && !slices.Contains(PowerThermalModels, model) {
+		return fmt.Errorf("unknown power and thermal model %q", model)
+	}
+	if c.Server.BasePath != "" && (!strings.HasPrefix(c.Server.BasePath, "/") || strings.HasSuffix(c.Server.BasePath, "/")) {
+		return fmt.Errorf("server base path %q must start with / and must not end with /", c.Server.BasePath)
+	}
+	if err := validateResetTypes("system", c.Reset.SystemTypes, true); err != nil {
+		return err
+	}
+	if err := validateResetTypes("manager", c.Reset.ManagerTypes, false); err != nil {
+		return err
+	}
 	if c.TLS.Enabled {
-		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+		if c.TLS.AutoGenerate {
+			if len(c.TLS.AutoGenerateHosts) == 0 {
+				return fmt.Errorf("at least one host is required to auto-generate a TLS certificate")
+			}
+		} else if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
 			return fmt.Errorf("TLS cert and key files must be specified when TLS is enabled")
 		}
 	}
+	if !c.DevMode {
+		if !c.TLS.Enabled || c.TLS.AutoGenerate {
+			return fmt.Errorf("TLS with certificate files is required unless DEV_MODE is set")
+		}
+		if c.Auth.AdminPassword == "" {
+			return fmt.Errorf("an administrator password (AUTH_ADMIN_PASSWORD) is required unless DEV_MODE is set")
+		}
+		if slices.Contains(c.Server.CORSAllowedOrigins, "*") {
+			return fmt.Errorf("CORS cannot admit any origin unless DEV_MODE is set")
+		}
+	}
+	if c.Auth.JWTPublicKeyFile != "" && c.Auth.JWTJWKSURL != "" {
+		return fmt.Errorf("configure either a JWT public key file or a JWKS URL, not both")
+	}
+	if c.Service.UUID != "" && !ValidUUID(c.Service.UUID) {
+		return fmt.Errorf("service UUID %q is not a valid UUID", c.Service.UUID)
+	}
+	if c.Service.RedfishVersion != "" && !validVersion(c.Service.RedfishVersion) {
+		return fmt.Errorf("redfish version %q must have the form major.minor.errata", c.Service.RedfishVersion)
+	}
 	return nil
 }
+
+// ValidUUID reports whether s is a UUID in canonical 8-4-4-4-12 hex form
+func ValidUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validVersion reports whether s is a dotted version with three numeric parts
+func validVersion(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil || part == "" || part[0] == '-' {
+			return false
+		}
+	}
+	return true
+}
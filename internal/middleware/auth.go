@@ -2,14 +2,76 @@ package middleware
 
 import (
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/user/redfish-server/internal/auth"
 )
 
-// AuthMiddleware handles authentication for protected endpoints
-func AuthMiddleware(next http.Handler) http.Handler {
+// PublicRoute is a request served without authentication
+type PublicRoute struct {
+	// Method is the request method, or empty for any method. GET also
+	// matches HEAD.
+	Method string
+	// Path is matched ignoring trailing slashes. A path ending in "/*"
+	// matches itself and everything below it.
+	Path string
+}
+
+// ParsePublicRoutes parses public routes written as "[METHOD ]path", such
+// as "GET /redfish/v1" or "/health"
+func ParsePublicRoutes(entries []string) ([]PublicRoute, error) {
+	routes := make([]PublicRoute, 0, len(entries))
+	for _, entry := range entries {
+		fields := strings.Fields(entry)
+		var route PublicRoute
+		switch len(fields) {
+		case 1:
+			route.Path = fields[0]
+		case 2:
+			route.Method, route.Path = strings.ToUpper(fields[0]), fields[1]
+		default:
+			return nil, fmt.Errorf("public route %q is not of the form \"[METHOD ]path\"", entry)
+		}
+		if !strings.HasPrefix(route.Path, "/") {
+			return nil, fmt.Errorf("public route %q must have an absolute path", entry)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// matches reports whether the route covers a request
+func (p PublicRoute) matches(path, method string) bool {
+	if p.Method != "" && p.Method != method && !(p.Method == "GET" && method == "HEAD") {
+		return false
+	}
+	path = trimTrailingSlash(path)
+	if base, ok := strings.CutSuffix(p.Path, "/*"); ok {
+		base = trimTrailingSlash(base)
+		return path == base || strings.HasPrefix(path, strings.TrimSuffix(base, "/")+"/")
+	}
+	return path == trimTrailingSlash(p.Path)
+}
+
+// trimTrailingSlash removes trailing slashes from every path but the root
+func trimTrailingSlash(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
+
+// AuthMiddleware handles authentication for every endpoint but the public
+// routes using the globally configured authenticator
+func AuthMiddleware(public []PublicRoute, next http.Handler) http.Handler {
+	return AuthenticatorMiddleware(auth.GetAuthenticator(), public, next)
+}
+
+// AuthenticatorMiddleware handles authentication for every endpoint but the
+// public routes using the given authentication provider
+func AuthenticatorMiddleware(authenticator auth.Authenticator, public []PublicRoute, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check OData-Version header
 		if odataVersion := r.Header.Get("OData-Version"); odataVersion != "" && odataVersion != "4.0" {
@@ -17,21 +79,32 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Allow POST to Sessions and Members without authentication for login
-		if r.Method == "POST" && (r.URL.Path == "/redfish/v1/SessionService/Sessions" || r.URL.Path == "/redfish/v1/SessionService/Sessions/Members") {
+		// Check if authentication is required for this endpoint. Browsers
+		// send CORS preflights without credentials, so they pass through to
+		// be answered by the CORS middleware.
+		if !requiresAuth(public, r.URL.Path, r.Method) || IsPreflight(r) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Check if authentication is required for this endpoint
-		if !requiresAuth(r.URL.Path, r.Method) {
-			next.ServeHTTP(w, r)
+		// Bearer tokens (OAuth2/JWT) carry their own role
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			if bearer, supported := authenticator.(auth.BearerAuthenticator); supported {
+				if username, role, valid := bearer.AuthenticateBearer(token); valid {
+					ctx := auth.SetUserContextWithRole(r.Context(), username, "Bearer", role)
+					r = r.WithContext(ctx)
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="Redfish Service", error="invalid_token"`)
+			http.Error(w, `{"error": {"code": "Base.1.0.InsufficientPrivilege", "message": "Invalid bearer token"}}`, http.StatusUnauthorized)
 			return
 		}
 
-		// Try Basic Authentication first
+		// Try Basic Authentication
 		if username, password, ok := r.BasicAuth(); ok {
-			if auth.ValidateBasicAuth(username, password) {
+			if authenticator.AuthenticateBasic(username, password) {
 				// Set user context for later use
 				ctx := auth.SetUserContext(r.Context(), username, "Basic")
 				r = r.WithContext(ctx)
@@ -42,7 +115,7 @@ func AuthMiddleware(next http.Handler) http.Handler {
 
 		// Try Session Authentication (X-Auth-Token header)
 		if token := r.Header.Get("X-Auth-Token"); token != "" {
-			if username, ok := auth.ValidateSessionToken(token); ok {
+			if username, ok := authenticator.ValidateToken(token); ok {
 				ctx := auth.SetUserContext(r.Context(), username, "Session")
 				r = r.WithContext(ctx)
 				next.ServeHTTP(w, r)
@@ -56,35 +129,14 @@ func AuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// requiresAuth determines if authentication is required for the given path and method
-func requiresAuth(path, method string) bool {
-	// Public endpoints that don't require authentication
-	publicPaths := []string{
-		"/health",
-		"/redfish",
-		"/redfish/v1",
-		"/redfish/v1/",
-		"/redfish/v1/$metadata",
-		"/redfish/v1/odata",
-	}
-
-	for _, publicPath := range publicPaths {
-		if path == publicPath {
+// requiresAuth determines if authentication is required for the given path
+// and method: it is, unless a public route covers them
+func requiresAuth(public []PublicRoute, path, method string) bool {
+	for _, route := range public {
+		if route.matches(path, method) {
 			return false
 		}
 	}
-
-	// Session creation (POST) is public, but GET to sessions requires auth
-	if path == "/redfish/v1/SessionService/Sessions" {
-		return method != "POST"
-	}
-
-	// SessionService info requires auth
-	if path == "/redfish/v1/SessionService" {
-		return true
-	}
-
-	// All other endpoints require authentication
 	return true
 }
 
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/auth"
+)
+
+// fakeAuthenticator accepts any password equal to the reversed username and
+// tokens of the form "token-<username>"
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) AuthenticateBasic(username, password string) bool {
+	reversed := []rune(username)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	return username != "" && password == string(reversed)
+}
+
+func (fakeAuthenticator) ValidateToken(token string) (string, bool) {
+	username, ok := strings.CutPrefix(token, "token-")
+	return username, ok && username != ""
+}
+
+func (fakeAuthenticator) Privileges(username string) []string {
+	if username == "root" {
+		return []string{"Login", "ConfigureManager"}
+	}
+	return []string{"Login"}
+}
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	var gotUser string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userCtx, ok := auth.GetUserContext(r.Context()); ok {
+			gotUser = userCtx.Username
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AuthenticatorMiddleware(fakeAuthenticator{}, nil, next)
+
+	tests := []struct {
+		name       string
+		setup      func(r *http.Request)
+		wantStatus int
+		wantUser   string
+	}{
+		{"basic granted", func(r *http.Request) { r.SetBasicAuth("root", "toor") }, http.StatusOK, "root"},
+		{"basic denied", func(r *http.Request) { r.SetBasicAuth("root", "password") }, http.StatusUnauthorized, ""},
+		{"default credentials not accepted", func(r *http.Request) { r.SetBasicAuth("admin", "password") }, http.StatusUnauthorized, ""},
+		{"token granted", func(r *http.Request) { r.Header.Set("X-Auth-Token", "token-alice") }, http.StatusOK, "alice"},
+		{"token denied", func(r *http.Request) { r.Header.Set("X-Auth-Token", "bogus") }, http.StatusUnauthorized, ""},
+		{"no credentials", func(r *http.Request) {}, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUser = ""
+			req := httptest.NewRequest("GET", "/redfish/v1/Systems", nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if gotUser != tt.wantUser {
+				t.Errorf("Expected user %q, got %q", tt.wantUser, gotUser)
+			}
+		})
+	}
+}
+
+func TestHasPrivilegeWithFakeAuthenticator(t *testing.T) {
+	if !auth.HasPrivilege(fakeAuthenticator{}, "root", "ConfigureManager") {
+		t.Error("Expected root to hold ConfigureManager")
+	}
+	if auth.HasPrivilege(fakeAuthenticator{}, "alice", "ConfigureManager") {
+		t.Error("Expected alice not to hold ConfigureManager")
+	}
+}
+
+func TestSetAuthenticator(t *testing.T) {
+	auth.SetAuthenticator(fakeAuthenticator{})
+	defer auth.SetAuthenticator(nil)
+
+	if _, ok := auth.GetAuthenticator().(fakeAuthenticator); !ok {
+		t.Fatal("Expected the fake authenticator to be configured")
+	}
+
+	handler := AuthMiddleware(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems", nil)
+	req.Header.Set("X-Auth-Token", "token-bob")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with the configured authenticator, got %d", w.Code)
+	}
+
+	auth.SetAuthenticator(nil)
+	if _, ok := auth.GetAuthenticator().(*auth.AuthService); !ok {
+		t.Error("Expected nil to restore the default AuthService")
+	}
+}
+
+func TestBearerAuthentication(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	wrongKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	sign := func(key *ecdsa.PrivateKey, exp time.Time) string {
+		header, _ := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT"})
+		payload, _ := json.Marshal(map[string]interface{}{"sub": "oauth-user", "role": "Operator", "exp": exp.Unix()})
+		input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		digest := sha256.Sum256([]byte(input))
+		r, s, _ := ecdsa.Sign(rand.Reader, key, digest[:])
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	var gotUser *auth.UserContext
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = auth.GetUserContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	validator := &auth.JWTValidator{Keys: auth.StaticKey(&key.PublicKey)}
+	handler := AuthenticatorMiddleware(auth.WithBearer(auth.NewAuthService(), validator), nil, next)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{"signed", sign(key, time.Now().Add(time.Hour)), http.StatusOK},
+		{"expired", sign(key, time.Now().Add(-time.Hour)), http.StatusUnauthorized},
+		{"wrong signature", sign(wrongKey, time.Now().Add(time.Hour)), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUser = nil
+			req := httptest.NewRequest("GET", "/redfish/v1/Systems", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if gotUser == nil || gotUser.Username != "oauth-user" || gotUser.Role != "Operator" || gotUser.Method != "Bearer" {
+					t.Errorf("Unexpected user context: %+v", gotUser)
+				}
+			} else if !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "Bearer") {
+				t.Errorf("Expected a Bearer challenge, got %q", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestPublicRoutes(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defaults, err := ParsePublicRoutes([]string{
+		"GET /redfish/v1",
+		"POST /redfish/v1/SessionService/Sessions",
+		"GET /redfish/v1/Registries/*",
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse public routes: %v", err)
+	}
+	privateRoot, _ := ParsePublicRoutes([]string{"POST /redfish/v1/SessionService/Sessions"})
+
+	tests := []struct {
+		name       string
+		public     []PublicRoute
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"service root", defaults, "GET", "/redfish/v1", http.StatusOK},
+		{"service root with trailing slash", defaults, "GET", "/redfish/v1/", http.StatusOK},
+		{"HEAD of a public GET", defaults, "HEAD", "/redfish/v1", http.StatusOK},
+		{"other method on a public path", defaults, "DELETE", "/redfish/v1", http.StatusUnauthorized},
+		{"login", defaults, "POST", "/redfish/v1/SessionService/Sessions", http.StatusOK},
+		{"login with trailing slash", defaults, "POST", "/redfish/v1/SessionService/Sessions/", http.StatusOK},
+		{"session below the collection", defaults, "POST", "/redfish/v1/SessionService/Sessions/1", http.StatusUnauthorized},
+		{"listing sessions", defaults, "GET", "/redfish/v1/SessionService/Sessions/", http.StatusUnauthorized},
+		{"subtree root", defaults, "GET", "/redfish/v1/Registries", http.StatusOK},
+		{"subtree member", defaults, "GET", "/redfish/v1/Registries/Base", http.StatusOK},
+		{"sibling of a subtree", defaults, "GET", "/redfish/v1/RegistriesExtra", http.StatusUnauthorized},
+		{"private service root", privateRoot, "GET", "/redfish/v1", http.StatusUnauthorized},
+		{"private service root with trailing slash", privateRoot, "GET", "/redfish/v1/", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := AuthenticatorMiddleware(fakeAuthenticator{}, tt.public, next)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+
+	for _, entry := range []string{"GET", "GET redfish/v1", "GET /redfish/v1 extra"} {
+		if _, err := ParsePublicRoutes([]string{entry}); err == nil {
+			t.Errorf("Expected public route %q to be rejected", entry)
+		}
+	}
+}
@@ -2,19 +2,61 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 )
 
-// CORSMiddleware adds CORS headers for cross-origin requests
-func CORSMiddleware(next http.Handler) http.Handler {
+// CORSRoute narrows what CORS preflights for one path are told: the methods
+// the path accepts and the request headers a client may send to it
+type CORSRoute struct {
+	Methods string
+	Headers string
+}
+
+// Headers advertised for every path without a CORSRoute
+const (
+	corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE, OPTIONS, HEAD"
+	corsAllowHeaders  = "Content-Type, Authorization, X-Auth-Token, OData-Version"
+	corsExposeHeaders = "OData-Version, Location, Link, X-Auth-Token"
+)
+
+// IsPreflight reports whether a request is a CORS preflight
+func IsPreflight(r *http.Request) bool {
+	return r.Method == "OPTIONS" && r.Header.Get("Origin") != "" && r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// CORSMiddleware adds CORS headers for cross-origin requests from the allowed
+// origins. An allowed origin of "*" admits any origin; requests from other
+// origins get no CORS headers, so browsers keep them same-origin. Preflights
+// for a path in routes are told only about that route's methods and headers.
+func CORSMiddleware(allowedOrigins []string, routes map[string]CORSRoute, next http.Handler) http.Handler {
+	anyOrigin := slices.Contains(allowedOrigins, "*")
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS, HEAD")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Auth-Token, OData-Version")
-		w.Header().Set("Access-Control-Expose-Headers", "OData-Version, Location, Link, X-Auth-Token")
+		origin := r.Header.Get("Origin")
+		switch {
+		case anyOrigin:
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case origin != "" && slices.Contains(allowedOrigins, origin):
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+		route, narrowed := routes[r.URL.Path]
+		if !narrowed {
+			route = CORSRoute{Methods: corsAllowMethods, Headers: corsAllowHeaders}
+		}
+		w.Header().Set("Access-Control-Allow-Methods", route.Methods)
+		w.Header().Set("Access-Control-Allow-Headers", route.Headers)
+		w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
 
 		// Handle preflight requests
 		if r.Method == "OPTIONS" {
+			if narrowed {
+				w.Header().Set("Allow", route.Methods)
+			}
 			w.WriteHeader(http.StatusOK)
 			return
 		}
@@ -6,8 +6,11 @@ import (
 	"time"
 )
 
-// LoggingMiddleware logs HTTP requests
-func LoggingMiddleware(next http.Handler) http.Handler {
+// LoggingMiddleware logs HTTP requests, and warns about those that take
+// longer than slowThreshold. A zero threshold turns the warning off.
+// Streamed responses, which flush as they go and stay open by design, are
+// never reported as slow.
+func LoggingMiddleware(slowThreshold time.Duration, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
@@ -18,6 +21,9 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 
 		duration := time.Since(start)
 		log.Printf("%s %s %d %v", r.Method, r.URL.Path, wrapped.statusCode, duration)
+		if slowThreshold > 0 && duration > slowThreshold && !wrapped.flushed {
+			log.Printf("WARNING: slow request %s %s took %v, over the %v threshold (status %d)", r.Method, r.URL.Path, duration, slowThreshold, wrapped.statusCode)
+		}
 	})
 }
 
@@ -25,9 +31,23 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	flushed    bool // the handler streamed its response
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Flush forwards to the underlying writer so streaming handlers keep working
+func (rw *responseWriter) Flush() {
+	rw.flushed = true
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowRequestWarning(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	handler := LoggingMiddleware(10*time.Millisecond, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(20 * time.Millisecond)
+		case "/stream":
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	serve := func(path string) string {
+		logged.Reset()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		return logged.String()
+	}
+
+	if out := serve("/slow"); !strings.Contains(out, "WARNING: slow request GET /slow") {
+		t.Errorf("Expected a slow request warning naming the route, got %q", out)
+	}
+	if out := serve("/fast"); strings.Contains(out, "WARNING") {
+		t.Errorf("Expected no warning for a fast request, got %q", out)
+	}
+	if out := serve("/stream"); strings.Contains(out, "WARNING") {
+		t.Errorf("Expected no warning for a streamed response, got %q", out)
+	}
+
+	// A zero threshold turns the warning off
+	handler = LoggingMiddleware(0, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	if out := serve("/slow"); strings.Contains(out, "WARNING") {
+		t.Errorf("Expected no warning with the threshold disabled, got %q", out)
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	headers := SecurityHeaders{ContentSecurityPolicy: "default-src 'none'", FrameOptions: "DENY", HSTSMaxAge: 600}
+	handler := SecurityHeadersMiddleware(headers, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	}))
+
+	req := httptest.NewRequest("GET", "/redfish/v1", nil)
+	req.TLS = &tls.ConnectionState{}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	for name, expected := range map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Content-Security-Policy":   "default-src 'none'",
+		"Strict-Transport-Security": "max-age=600; includeSubDomains",
+	} {
+		if got := w.Header().Get(name); got != expected {
+			t.Errorf("Expected %s %q, got %q", name, expected, got)
+		}
+	}
+
+	// HSTS is meaningless over plain HTTP
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/redfish/v1", nil))
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("Expected no Strict-Transport-Security over plain HTTP, got %q", got)
+	}
+
+	// Event streams are not framed or rendered as documents
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/stream", nil))
+	if w.Header().Get("Content-Security-Policy") != "" || w.Header().Get("X-Frame-Options") != "" {
+		t.Errorf("Expected no document headers on an event stream, got %v", w.Header())
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("Expected nosniff on an event stream, got %q", got)
+	}
+}
@@ -8,11 +8,11 @@ type AccountService struct {
 	Roles                           Link   `json:"Roles,omitempty"`
 	PrivilegeMap                    Link   `json:"PrivilegeMap,omitempty"`
 	Status                          Status `json:"Status,omitempty"`
-	MinPasswordLength               int    `json:"MinPasswordLength,omitempty"`
-	MaxPasswordLength               int    `json:"MaxPasswordLength,omitempty"`
-	AccountLockoutThreshold         int    `json:"AccountLockoutThreshold,omitempty"`
-	AccountLockoutDuration          int    `json:"AccountLockoutDuration,omitempty"`
-	AccountLockoutCounterResetAfter int    `json:"AccountLockoutCounterResetAfter,omitempty"`
+	MinPasswordLength               int    `json:"MinPasswordLength"`
+	MaxPasswordLength               int    `json:"MaxPasswordLength"`
+	AccountLockoutThreshold         int    `json:"AccountLockoutThreshold"`
+	AccountLockoutDuration          int    `json:"AccountLockoutDuration"`
+	AccountLockoutCounterResetAfter int    `json:"AccountLockoutCounterResetAfter"`
 }
 
 // NewAccountService creates a new AccountService instance
@@ -63,7 +63,7 @@ func NewManagerAccount(username, roleId string, enabled bool) *ManagerAccount {
 	return &ManagerAccount{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#ManagerAccount.ManagerAccount",
-			ODataID:      ODataID("/redfish/v1/AccountService/Accounts/" + username),
+			ODataID:      NewODataID("/redfish/v1/AccountService/Accounts", username),
 			ODataType:    "#ManagerAccount.v1_13_0.ManagerAccount",
 			ID:           username,
 			Name:         "User Account",
@@ -74,7 +74,7 @@ func NewManagerAccount(username, roleId string, enabled bool) *ManagerAccount {
 		Enabled:      enabled,
 		Locked:       false,
 		Links: AccountLinks{
-			Role: ODataID("/redfish/v1/AccountService/Roles/" + roleId),
+			Role: NewODataID("/redfish/v1/AccountService/Roles", roleId),
 		},
 	}
 }
@@ -0,0 +1,80 @@
+package models
+
+import "fmt"
+
+// ActionParameter describes one parameter of an action
+type ActionParameter struct {
+	Name            string   `json:"Name"`
+	Required        bool     `json:"Required"`
+	DataType        string   `json:"DataType"`
+	AllowableValues []string `json:"AllowableValues,omitempty"`
+}
+
+// ActionInfo describes the parameters an action accepts. Actions validate
+// their requests against the same ActionInfo they advertise, so the two
+// cannot drift.
+type ActionInfo struct {
+	Resource
+	Parameters []ActionParameter `json:"Parameters"`
+}
+
+// NewActionInfo creates the ActionInfo of the action at target
+func NewActionInfo(target ODataID, id, name string, parameters ...ActionParameter) *ActionInfo {
+	return &ActionInfo{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#ActionInfo.ActionInfo",
+			ODataID:      target,
+			ODataType:    "#ActionInfo.v1_1_2.ActionInfo",
+			ID:           id,
+			Name:         name,
+		},
+		Parameters: parameters,
+	}
+}
+
+// Parameter returns the named parameter
+func (a *ActionInfo) Parameter(name string) (ActionParameter, bool) {
+	for _, parameter := range a.Parameters {
+		if parameter.Name == name {
+			return parameter, true
+		}
+	}
+	return ActionParameter{}, false
+}
+
+// MissingParameterError reports a required action parameter that a request
+// left out
+type MissingParameterError struct {
+	Action    string
+	Parameter string
+}
+
+func (e *MissingParameterError) Error() string {
+	return fmt.Sprintf("The action %s requires the parameter %s", e.Action, e.Parameter)
+}
+
+// Validate checks the parameters of an action request: every required
+// parameter must be present, and parameters with AllowableValues must hold
+// one of them. It returns a *MissingParameterError or an *EnumError.
+func (a *ActionInfo) Validate(parameters map[string]interface{}) error {
+	for _, parameter := range a.Parameters {
+		value, ok := parameters[parameter.Name]
+		if !ok || value == nil {
+			if parameter.Required {
+				return &MissingParameterError{Action: a.ID, Parameter: parameter.Name}
+			}
+			continue
+		}
+		if len(parameter.AllowableValues) == 0 {
+			continue
+		}
+		s, isString := value.(string)
+		if !isString {
+			s = fmt.Sprint(value)
+		}
+		if err := ValidateEnum(parameter.Name, s, parameter.AllowableValues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
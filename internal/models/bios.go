@@ -0,0 +1,53 @@
+package models
+
+// Bios represents the BIOS attributes of a computer system
+type Bios struct {
+	Resource
+	AttributeRegistry string                 `json:"AttributeRegistry,omitempty"`
+	Attributes        map[string]interface{} `json:"Attributes"`
+	Settings          *Settings              `json:"@Redfish.Settings,omitempty"`
+	SettingsApplyTime *SettingsApplyTime     `json:"@Redfish.SettingsApplyTime,omitempty"`
+}
+
+// DefaultBiosAttributes returns the factory BIOS attributes of a system
+func DefaultBiosAttributes() map[string]interface{} {
+	return map[string]interface{}{
+		"BootMode":           "Uefi",
+		"ProcTurboMode":      "Enabled",
+		"ProcVirtualization": "Enabled",
+		"SriovEnable":        "Enabled",
+		"NumaNodesPerSocket": 1,
+	}
+}
+
+// NewBios creates the Bios resource of a system with its current attributes
+func NewBios(systemID string, attributes map[string]interface{}) *Bios {
+	return &Bios{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Bios.Bios",
+			ODataID:      NewODataID("/redfish/v1/Systems", systemID, "Bios"),
+			ODataType:    "#Bios.v1_2_0.Bios",
+			ID:           "Bios",
+			Name:         "BIOS Configuration Current Settings",
+		},
+		AttributeRegistry: "BiosAttributeRegistry.1.0.0",
+		Attributes:        attributes,
+		Settings:          NewSettings(NewODataID("/redfish/v1/Systems", systemID, "Bios/Settings"), []string{"OnReset"}),
+	}
+}
+
+// NewBiosSettings creates the Bios settings object holding staged attributes
+func NewBiosSettings(systemID string, attributes map[string]interface{}) *Bios {
+	return &Bios{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Bios.Bios",
+			ODataID:      NewODataID("/redfish/v1/Systems", systemID, "Bios/Settings"),
+			ODataType:    "#Bios.v1_2_0.Bios",
+			ID:           "Settings",
+			Name:         "BIOS Configuration Pending Settings",
+		},
+		AttributeRegistry: "BiosAttributeRegistry.1.0.0",
+		Attributes:        attributes,
+		SettingsApplyTime: NewSettingsApplyTime("OnReset"),
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+// Capabilities is the Contoso OEM resource listing the optional
+// features this service implements, for conformance tooling and clients
+type Capabilities struct {
+	Resource
+	Services           []ODataID `json:"Services"`
+	QueryParameters    []string  `json:"QueryParameters"`
+	MaxExpandLevels    int       `json:"MaxExpandLevels"`
+	Actions            []string  `json:"Actions"`
+	AsyncOperations    bool      `json:"AsyncOperations"`
+	EventSubscriptions bool      `json:"EventSubscriptions"`
+	ServerSentEvents   bool      `json:"ServerSentEvents"`
+	MethodOverride     bool      `json:"MethodOverride"`
+	Authentication     []string  `json:"Authentication"`
+	OemVendors         []string  `json:"OemVendors"`
+}
+
+// NewCapabilities creates an empty Capabilities document
+func NewCapabilities() *Capabilities {
+	return &Capabilities{
+		Resource: Resource{
+			ODataID:     "/redfish/v1/Oem/Contoso/Capabilities",
+			ODataType:   "#ContosoCapabilities.v1_0_0.Capabilities",
+			ID:          "Capabilities",
+			Name:        "Service Capabilities",
+			Description: "Optional features implemented by this service",
+		},
+		Services:        []ODataID{},
+		QueryParameters: []string{},
+		Actions:         []string{},
+		Authentication:  []string{},
+		OemVendors:      []string{},
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+// Certificate represents a certificate installed on the service, such as one
+// pinned for an event destination
+type Certificate struct {
+	Resource
+	CertificateString string                `json:"CertificateString"`
+	CertificateType   string                `json:"CertificateType"` // PEM
+	Issuer            CertificateIdentifier `json:"Issuer,omitempty"`
+	Subject           CertificateIdentifier `json:"Subject,omitempty"`
+	ValidNotBefore    string                `json:"ValidNotBefore,omitempty"`
+	ValidNotAfter     string                `json:"ValidNotAfter,omitempty"`
+}
+
+// CertificateIdentifier identifies the issuer or subject of a certificate
+type CertificateIdentifier struct {
+	CommonName   string `json:"CommonName,omitempty"`
+	Organization string `json:"Organization,omitempty"`
+}
+
+// NewCertificate creates a PEM Certificate as a member of the collection at collectionURI
+func NewCertificate(collectionURI ODataID, id, pem string) *Certificate {
+	return &Certificate{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Certificate.Certificate",
+			ODataID:      NewODataID(string(collectionURI), id),
+			ODataType:    "#Certificate.v1_7_0.Certificate",
+			ID:           id,
+			Name:         "Certificate " + id,
+		},
+		CertificateString: pem,
+		CertificateType:   "PEM",
+	}
+}
+
+// CertificateCollection represents a collection of certificates
+type CertificateCollection struct {
+	Collection
+}
+
+// NewCertificateCollection creates a CertificateCollection at uri with the given members
+func NewCertificateCollection(uri ODataID, members []Link) *CertificateCollection {
+	return &CertificateCollection{
+		Collection: Collection{
+			ODataContext:      "/redfish/v1/$metadata#CertificateCollection.CertificateCollection",
+			ODataID:           uri,
+			ODataType:         "#CertificateCollection.CertificateCollection",
+			Name:              "Certificate Collection",
+			Members:           CollectionMembers(members),
+			MembersODataCount: len(members),
+		},
+	}
+}
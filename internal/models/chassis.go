@@ -19,10 +19,13 @@ type Chassis struct {
 	WeightKg           float64      `json:"WeightKg,omitempty"`
 	Power              ODataID      `json:"Power,omitempty"`
 	Thermal            ODataID      `json:"Thermal,omitempty"`
+	PowerSubsystem     *Link        `json:"PowerSubsystem,omitempty"`
+	ThermalSubsystem   *Link        `json:"ThermalSubsystem,omitempty"`
 	NetworkAdapters    ODataID      `json:"NetworkAdapters,omitempty"`
 	Drives             ODataID      `json:"Drives,omitempty"`
 	PCIeDevices        ODataID      `json:"PCIeDevices,omitempty"`
 	Links              ChassisLinks `json:"Links,omitempty"`
+	Oem                OEM          `json:"Oem,omitempty"`
 }
 
 // ChassisLinks represents links to related resources
@@ -41,12 +44,15 @@ func NewChassis(id string) *Chassis {
 	return &Chassis{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#Chassis.Chassis",
-			ODataID:      ODataID("/redfish/v1/Chassis/" + id),
+			ODataID:      NewODataID("/redfish/v1/Chassis", id),
 			ODataType:    "#Chassis.v1_23_0.Chassis",
 			ID:           id,
 			Name:         "Chassis",
 		},
-		ChassisType: "Rack",
+		ChassisType:  "Rack",
+		Manufacturer: "Contoso",
+		Model:        demoModel("RC", "Chassis", id),
+		SerialNumber: demoSerialNumber("CH", "Chassis", id),
 		Status: Status{
 			State:  "Enabled",
 			Health: "OK",
@@ -56,12 +62,19 @@ func NewChassis(id string) *Chassis {
 		WidthMm:    482.6, // Standard rack width
 		DepthMm:    711.2, // Standard depth
 		WeightKg:   15.0,
-		Power:      ODataID("/redfish/v1/Chassis/" + id + "/Power"),
-		Thermal:    ODataID("/redfish/v1/Chassis/" + id + "/Thermal"),
+		Power:      NewODataID("/redfish/v1/Chassis", id, "Power"),
+		Thermal:    NewODataID("/redfish/v1/Chassis", id, "Thermal"),
+		PowerSubsystem: &Link{
+			ODataID: NewODataID("/redfish/v1/Chassis", id, "PowerSubsystem"),
+		},
+		ThermalSubsystem: &Link{
+			ODataID: NewODataID("/redfish/v1/Chassis", id, "ThermalSubsystem"),
+		},
 		Links: ChassisLinks{
 			ComputerSystems: []ODataID{ODataID("/redfish/v1/Systems/1")},
 			ManagedBy:       []ODataID{ODataID("/redfish/v1/Managers/1")},
 		},
+		Oem: BuildOem("Chassis", id),
 	}
 }
 
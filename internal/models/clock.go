@@ -0,0 +1,26 @@
+package models
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/user/redfish-server/internal/clock"
+)
+
+// timeSource stamps the times models record, such as when a task started
+var timeSource atomic.Pointer[clock.Clock]
+
+func init() {
+	SetClock(clock.Real)
+}
+
+// SetClock replaces the clock models stamp times with, so tests can control
+// task timestamps
+func SetClock(c clock.Clock) {
+	timeSource.Store(&c)
+}
+
+// now returns the current time of the models' clock
+func now() time.Time {
+	return (*timeSource.Load()).Now()
+}
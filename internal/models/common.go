@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
 // Common Redfish objects and types used across multiple schemas
 
 // ODataContext represents the @odata.context annotation
@@ -8,6 +15,36 @@ type ODataContext string
 // ODataID represents the @odata.id annotation
 type ODataID string
 
+// serviceRootID is the one @odata.id that keeps its trailing slash
+const serviceRootID = "/redfish/v1/"
+
+// NewODataID joins path parts into a canonical @odata.id: rooted, with
+// repeated slashes collapsed, "." and ".." segments resolved and no trailing
+// slash, except on the service root, which Redfish spells /redfish/v1/.
+func NewODataID(parts ...string) ODataID {
+	joined := path.Clean("/" + strings.Join(parts, "/"))
+	if joined+"/" == serviceRootID {
+		return serviceRootID
+	}
+	return ODataID(joined)
+}
+
+// Validate reports whether the @odata.id is a canonical path within the
+// Redfish service, as NewODataID produces
+func (id ODataID) Validate() error {
+	s := string(id)
+	if s != serviceRootID && !strings.HasPrefix(s, serviceRootID) {
+		return fmt.Errorf("@odata.id %q is not within %s", s, serviceRootID)
+	}
+	if strings.ContainsAny(s, "?#") || strings.IndexFunc(s, unicode.IsSpace) >= 0 || strings.IndexFunc(s, unicode.IsControl) >= 0 {
+		return fmt.Errorf("@odata.id %q must be a bare path without whitespace, query or fragment", s)
+	}
+	if NewODataID(s) != id {
+		return fmt.Errorf("@odata.id %q is not normalized; expected %q", s, NewODataID(s))
+	}
+	return nil
+}
+
 // Link represents a reference to another resource
 type Link struct {
 	ODataID ODataID `json:"@odata.id"`
@@ -70,12 +107,18 @@ type Resource struct {
 	ODataContext ODataContext `json:"@odata.context,omitempty"`
 	ODataID      ODataID      `json:"@odata.id,omitempty"`
 	ODataType    ODataType    `json:"@odata.type,omitempty"`
+	ODataEtag    string       `json:"@odata.etag,omitempty"`
 	ID           string       `json:"Id"`
 	Name         string       `json:"Name"`
 	Description  string       `json:"Description,omitempty"`
 	Oem          *Oem         `json:"Oem,omitempty"`
 }
 
+// SetODataETag records the resource's current ETag in its @odata.etag annotation
+func (r *Resource) SetODataETag(etag string) {
+	r.ODataEtag = etag
+}
+
 // Collection represents a collection of resources
 type Collection struct {
 	ODataContext      ODataContext `json:"@odata.context,omitempty"`
@@ -84,15 +127,26 @@ type Collection struct {
 	Name              string       `json:"Name"`
 	Members           []Link       `json:"Members"`
 	MembersODataCount int          `json:"Members@odata.count"`
+	MembersNextLink   ODataID      `json:"Members@odata.nextLink,omitempty"`
 	Oem               *Oem         `json:"Oem,omitempty"`
 }
 
+// CollectionMembers returns members as a non-nil slice, so that an empty
+// collection serializes Members as [] rather than null
+func CollectionMembers(members []Link) []Link {
+	if members == nil {
+		return []Link{}
+	}
+	return members
+}
+
 // Message represents an error message
 type Message struct {
-	MessageID  string `json:"MessageId"`
-	Message    string `json:"Message,omitempty"`
-	Severity   string `json:"Severity,omitempty"` // OK, Warning, Critical
-	Resolution string `json:"Resolution,omitempty"`
+	MessageID   string   `json:"MessageId"`
+	Message     string   `json:"Message,omitempty"`
+	MessageArgs []string `json:"MessageArgs,omitempty"`
+	Severity    string   `json:"Severity,omitempty"` // OK, Warning, Critical
+	Resolution  string   `json:"Resolution,omitempty"`
 }
 
 // RedfishError represents a Redfish error response
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewODataIDNormalizes(t *testing.T) {
+	tests := []struct {
+		parts    []string
+		expected ODataID
+	}{
+		{[]string{"/redfish/v1/Systems", "1"}, "/redfish/v1/Systems/1"},
+		{[]string{"/redfish/v1/Systems/", "1"}, "/redfish/v1/Systems/1"},
+		{[]string{"/redfish/v1/Systems/", "/1/", "/Bios/"}, "/redfish/v1/Systems/1/Bios"},
+		{[]string{"redfish//v1///Managers", "1"}, "/redfish/v1/Managers/1"},
+		{[]string{"/redfish/v1/Chassis/./1"}, "/redfish/v1/Chassis/1"},
+		{[]string{"/redfish/v1/Chassis/1/../2"}, "/redfish/v1/Chassis/2"},
+		{[]string{"/redfish/v1"}, "/redfish/v1/"},
+		{[]string{"/redfish/v1//"}, "/redfish/v1/"},
+	}
+
+	for _, tt := range tests {
+		if got := NewODataID(tt.parts...); got != tt.expected {
+			t.Errorf("NewODataID(%q) = %q, expected %q", tt.parts, got, tt.expected)
+		}
+		if err := tt.expected.Validate(); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", tt.expected, err)
+		}
+	}
+}
+
+func TestODataIDValidate(t *testing.T) {
+	for _, id := range []ODataID{
+		"",
+		"redfish/v1/Systems/1",
+		"/redfish/v1/Systems/1/",
+		"/redfish/v1//Systems/1",
+		"/redfish/v1/Systems/1?$expand=*",
+		"/redfish/v1/Systems/my system",
+		"/other/v1/Systems/1",
+	} {
+		if err := id.Validate(); err == nil {
+			t.Errorf("Expected %q to be rejected", id)
+		}
+	}
+}
+
+func TestConstructorsProduceCanonicalIDs(t *testing.T) {
+	for _, id := range []ODataID{
+		NewComputerSystem("1/").ODataID,
+		NewComputerSystem("1").Bios.ODataID,
+		NewManager("/1").SerialInterfaces.ODataID,
+		NewTask("abc/", "POST", "/redfish/v1/Systems").ODataID,
+		NewCertificate("/redfish/v1/EventService/Subscriptions/1/Certificates/", "1", "").ODataID,
+	} {
+		if err := id.Validate(); err != nil {
+			t.Errorf("Constructor produced a non-canonical @odata.id: %v", err)
+		}
+	}
+}
+
+func TestEmptyCollectionsSerializeMembers(t *testing.T) {
+	for _, collection := range []interface{}{
+		NewCertificateCollection("/redfish/v1/Managers/1/NetworkProtocol/HTTPS/Certificates", nil),
+		NewEthernetInterfaceCollection("1", nil),
+		NewSerialInterfaceCollection("1", nil),
+		NewPowerSupplyCollection("1", nil),
+		NewBatteryCollection("1", nil),
+		NewFanCollection("1", nil),
+	} {
+		data, err := json.Marshal(collection)
+		if err != nil {
+			t.Fatalf("Failed to marshal %T: %v", collection, err)
+		}
+		if !strings.Contains(string(data), `"Members":[],"Members@odata.count":0`) {
+			t.Errorf("Expected %T to serialize empty Members, got %s", collection, data)
+		}
+	}
+}
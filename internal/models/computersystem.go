@@ -12,6 +12,7 @@ type ComputerSystem struct {
 	PartNumber         string                `json:"PartNumber,omitempty"`
 	UUID               string                `json:"UUID,omitempty"`
 	HostName           string                `json:"HostName,omitempty"`
+	IndicatorLED       string                `json:"IndicatorLED,omitempty"` // Lit, Blinking, Off
 	Status             Status                `json:"Status,omitempty"`
 	PowerState         string                `json:"PowerState,omitempty"` // On, Off, PoweringOn, etc.
 	Boot               Boot                  `json:"Boot,omitempty"`
@@ -25,9 +26,10 @@ type ComputerSystem struct {
 	NetworkInterfaces  ODataID               `json:"NetworkInterfaces,omitempty"`
 	EthernetInterfaces ODataID               `json:"EthernetInterfaces,omitempty"`
 	LogServices        ODataID               `json:"LogServices,omitempty"`
+	Bios               *Link                 `json:"Bios,omitempty"`
 	Links              ComputerSystemLinks   `json:"Links,omitempty"`
 	Actions            ComputerSystemActions `json:"Actions,omitempty"`
-	Oem                *OEM                  `json:"Oem,omitempty"`
+	Oem                OEM                   `json:"Oem,omitempty"`
 }
 
 // Boot represents boot configuration
@@ -73,13 +75,18 @@ type ComputerSystemLinks struct {
 	Oem       Oem    `json:"Oem,omitempty"`
 }
 
-// ComputerSystemActions represents available actions
+// ComputerSystemActions represents available actions. An action the system
+// does not support is left nil so it is not advertised.
 type ComputerSystemActions struct {
-	ComputerSystemReset struct {
-		Target string `json:"target"`
-		Title  string `json:"title,omitempty"`
-	} `json:"#ComputerSystem.Reset,omitempty"`
-	Oem Oem `json:"Oem,omitempty"`
+	ComputerSystemReset *ActionTarget `json:"#ComputerSystem.Reset,omitempty"`
+	// Oem advertises the registered OEM actions, keyed by #Vendor.Action
+	Oem map[string]interface{} `json:"Oem,omitempty"`
+}
+
+// ActionTarget represents the target URI and title of an advertised action
+type ActionTarget struct {
+	Target string `json:"target"`
+	Title  string `json:"title,omitempty"`
 }
 
 // NewComputerSystem creates a new ComputerSystem instance
@@ -87,13 +94,18 @@ func NewComputerSystem(id string) *ComputerSystem {
 	return &ComputerSystem{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#ComputerSystem.ComputerSystem",
-			ODataID:      ODataID("/redfish/v1/Systems/" + id),
+			ODataID:      NewODataID("/redfish/v1/Systems", id),
 			ODataType:    "#ComputerSystem.v1_20_0.ComputerSystem",
 			ID:           id,
 			Name:         "Computer System",
 		},
-		SystemType: "Physical",
-		PowerState: "On",
+		SystemType:   "Physical",
+		Manufacturer: "Contoso",
+		Model:        demoModel("RS", "ComputerSystem", id),
+		SerialNumber: demoSerialNumber("SYS", "ComputerSystem", id),
+		UUID:         demoUUID("ComputerSystem", id),
+		PowerState:   "On",
+		IndicatorLED: "Off",
 		Status: Status{
 			State:  "Enabled",
 			Health: "OK",
@@ -116,30 +128,27 @@ func NewComputerSystem(id string) *ComputerSystem {
 				Health: "OK",
 			},
 		},
-		Processors:  ODataID("/redfish/v1/Systems/" + id + "/Processors"),
-		Memory:      ODataID("/redfish/v1/Systems/" + id + "/Memory"),
-		LogServices: ODataID("/redfish/v1/Systems/" + id + "/LogServices"),
+		Processors:  NewODataID("/redfish/v1/Systems", id, "Processors"),
+		Memory:      NewODataID("/redfish/v1/Systems", id, "Memory"),
+		LogServices: NewODataID("/redfish/v1/Systems", id, "LogServices"),
+		Bios:        &Link{ODataID: NewODataID("/redfish/v1/Systems", id, "Bios")},
 		Links: ComputerSystemLinks{
 			ManagedBy: []Link{Link{ODataID: "/redfish/v1/Managers/1"}},
 		},
 		Actions: ComputerSystemActions{
-			ComputerSystemReset: struct {
-				Target string `json:"target"`
-				Title  string `json:"title,omitempty"`
-			}{
+			ComputerSystemReset: &ActionTarget{
 				Target: "/redfish/v1/Systems/" + id + "/Actions/ComputerSystem.Reset",
 				Title:  "Reset Computer System",
 			},
 		},
-		Oem: &OEM{
-			Contoso: NewContosoOEM(),
-		},
+		Oem: BuildOem("ComputerSystem", id),
 	}
 }
 
 // ComputerSystemCollection represents a collection of computer systems
 type ComputerSystemCollection struct {
 	Collection
+	Actions *Actions `json:"Actions,omitempty"`
 }
 
 // NewComputerSystemCollection creates a new ComputerSystemCollection instance
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+)
+
+// demoSeed hashes the kind and id of a demo resource, so each resource gets
+// its own identifying data that stays the same across restarts
+func demoSeed(kind, id string) [sha1.Size]byte {
+	return sha1.Sum([]byte(kind + "/" + id))
+}
+
+// demoUUID derives a name-based (version 5 layout) UUID for a demo resource
+func demoUUID(kind, id string) string {
+	sum := demoSeed(kind, id)
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// demoSerialNumber derives a serial number for a demo resource, e.g. CN7A3F09B2
+func demoSerialNumber(prefix, kind, id string) string {
+	sum := demoSeed(kind, id)
+	return fmt.Sprintf("%s%08X", prefix, binary.BigEndian.Uint32(sum[16:20]))
+}
+
+// demoModel derives a model name in a product line for a demo resource,
+// e.g. RS-450
+func demoModel(line, kind, id string) string {
+	sum := demoSeed(kind, id)
+	return fmt.Sprintf("%s-%d", line, 100+binary.BigEndian.Uint16(sum[0:2])%900)
+}
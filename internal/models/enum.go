@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// baseRegistry supplies the severity and resolution of enum validation messages
+var baseRegistry = NewMessageRegistry("en")
+
+// EnumError reports a value that is not a member of a Redfish enumeration
+type EnumError struct {
+	Property string
+	Value    string
+	Allowed  []string
+}
+
+// Error describes the rejected value and lists the acceptable ones
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("Invalid %s %q; allowed values: %s", e.Property, e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// Message returns the Base PropertyValueNotInList message for the error
+func (e *EnumError) Message() Message {
+	entry := baseRegistry.Messages["PropertyValueNotInList"]
+	return Message{
+		MessageID:  baseRegistry.MessageID("PropertyValueNotInList"),
+		Message:    e.Error(),
+		Severity:   entry.MessageSeverity,
+		Resolution: entry.Resolution,
+	}
+}
+
+// ValidateEnum checks that value is one of the allowed values of the
+// enumeration backing property, returning nil if it is
+func ValidateEnum[T ~string](property string, value T, allowed []T) *EnumError {
+	if slices.Contains(allowed, value) {
+		return nil
+	}
+	names := make([]string, len(allowed))
+	for i, v := range allowed {
+		names[i] = string(v)
+	}
+	return &EnumError{Property: property, Value: string(value), Allowed: names}
+}
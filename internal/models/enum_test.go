@@ -0,0 +1,38 @@
+package models
+
+import "testing"
+
+type powerState string
+
+func TestValidateEnum(t *testing.T) {
+	allowed := []powerState{"On", "Off"}
+	if err := ValidateEnum("PowerState", powerState("On"), allowed); err != nil {
+		t.Errorf("Expected On to be accepted, got %v", err)
+	}
+
+	err := ValidateEnum("PowerState", powerState("Sleeping"), allowed)
+	if err == nil {
+		t.Fatal("Expected Sleeping to be rejected")
+	}
+	if want := `Invalid PowerState "Sleeping"; allowed values: On, Off`; err.Error() != want {
+		t.Errorf("Expected error %q, got %q", want, err.Error())
+	}
+
+	message := err.Message()
+	if message.MessageID != "Base.1.0.PropertyValueNotInList" {
+		t.Errorf("Expected MessageId Base.1.0.PropertyValueNotInList, got %s", message.MessageID)
+	}
+	if message.Message != err.Error() || message.Severity != "Warning" || message.Resolution == "" {
+		t.Errorf("Unexpected message %+v", message)
+	}
+}
+
+func TestValidateEnumEmptyList(t *testing.T) {
+	err := ValidateEnum("ResetType", "On", nil)
+	if err == nil {
+		t.Fatal("Expected every value to be rejected by an empty enumeration")
+	}
+	if want := `Invalid ResetType "On"; allowed values: `; err.Error() != want {
+		t.Errorf("Expected error %q, got %q", want, err.Error())
+	}
+}
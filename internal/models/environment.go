@@ -0,0 +1,96 @@
+package models
+
+import (
+	"math"
+	"strconv"
+)
+
+// ChassisEnvironment is the simulated power and thermal state of a chassis.
+// The legacy Power and Thermal resources and the PowerSubsystem and
+// ThermalSubsystem are all built from it, so they report the same readings.
+type ChassisEnvironment struct {
+	PowerSupplies []PowerSupplyReading
+	Batteries     []BatteryReading
+	Fans          []FanReading
+	Temperatures  []TemperatureReading
+}
+
+// PowerSupplyReading is the simulated state of a power supply
+type PowerSupplyReading struct {
+	ID            string
+	Name          string
+	CapacityWatts float64
+	InputWatts    float64
+	OutputWatts   float64
+}
+
+// BatteryReading is the simulated state of a battery
+type BatteryReading struct {
+	ID                string
+	Name              string
+	CapacityWattHours float64
+	ChargePercent     float64
+}
+
+// FanReading is the simulated state of a fan
+type FanReading struct {
+	ID           string
+	Name         string
+	SpeedRPM     int
+	SpeedPercent float64
+}
+
+// TemperatureReading is the simulated reading of a temperature sensor
+type TemperatureReading struct {
+	ID            string
+	Name          string
+	Celsius       float64
+	UpperCritical float64
+}
+
+// maxFanRPM is the speed at which a simulated fan runs at 100%
+const maxFanRPM = 16000
+
+// NewChassisEnvironment derives the simulated power and thermal state of a
+// chassis, which differs between chassis but stays the same across restarts
+func NewChassisEnvironment(chassisID string) *ChassisEnvironment {
+	seed := demoSeed("ChassisEnvironment", chassisID)
+	environment := &ChassisEnvironment{
+		Batteries: []BatteryReading{
+			{ID: "0", Name: "Battery 0", CapacityWattHours: 50, ChargePercent: 100},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		output := 200 + float64(seed[i]%100)
+		environment.PowerSupplies = append(environment.PowerSupplies, PowerSupplyReading{
+			ID:            strconv.Itoa(i),
+			Name:          "Power Supply " + strconv.Itoa(i),
+			CapacityWatts: 800,
+			InputWatts:    roundTenth(output / 0.94),
+			OutputWatts:   output,
+		})
+	}
+	for i := 0; i < 4; i++ {
+		rpm := 6000 + int(seed[2+i]%40)*100
+		environment.Fans = append(environment.Fans, FanReading{
+			ID:           strconv.Itoa(i),
+			Name:         "Fan " + strconv.Itoa(i),
+			SpeedRPM:     rpm,
+			SpeedPercent: roundTenth(float64(rpm) * 100 / maxFanRPM),
+		})
+	}
+
+	inlet := 20 + float64(seed[6]%8)
+	environment.Temperatures = []TemperatureReading{
+		{ID: "0", Name: "Inlet Temp", Celsius: inlet, UpperCritical: 42},
+		{ID: "1", Name: "Exhaust Temp", Celsius: inlet + 12, UpperCritical: 70},
+		{ID: "2", Name: "CPU1 Temp", Celsius: 40 + float64(seed[7]%20), UpperCritical: 95},
+	}
+	return environment
+}
+
+// roundTenth rounds a reading to one decimal place
+func roundTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,130 @@
+package models
+
+// EthernetInterface represents a network interface of a manager
+type EthernetInterface struct {
+	Resource
+	Status                 Status        `json:"Status"`
+	InterfaceEnabled       bool          `json:"InterfaceEnabled"`
+	PermanentMACAddress    string        `json:"PermanentMACAddress,omitempty"`
+	MACAddress             string        `json:"MACAddress,omitempty"`
+	SpeedMbps              int           `json:"SpeedMbps,omitempty"`
+	HostName               string        `json:"HostName,omitempty"`
+	DHCPv4                 DHCPv4        `json:"DHCPv4"`
+	IPv4Addresses          []IPv4Address `json:"IPv4Addresses"`
+	IPv4StaticAddresses    []IPv4Address `json:"IPv4StaticAddresses"`
+	IPv6Addresses          []IPv6Address `json:"IPv6Addresses"`
+	IPv6StaticAddresses    []IPv6Address `json:"IPv6StaticAddresses"`
+	IPv6DefaultGateway     string        `json:"IPv6DefaultGateway,omitempty"`
+	MaxIPv6StaticAddresses int           `json:"MaxIPv6StaticAddresses"`
+
+	// dhcpLease is the IPv4 address DHCP assigns while it is enabled
+	dhcpLease IPv4Address
+}
+
+// DHCPv4 is the DHCPv4 configuration of an EthernetInterface
+type DHCPv4 struct {
+	DHCPEnabled bool `json:"DHCPEnabled"`
+}
+
+// MaxIPv6StaticAddresses is how many static IPv6 addresses an interface holds
+const MaxIPv6StaticAddresses = 4
+
+// NewEthernetInterface creates an EthernetInterface with a DHCP-assigned IPv4
+// address and an IPv6 link-local address
+func NewEthernetInterface(managerID, id string) *EthernetInterface {
+	lease := IPv4Address{
+		Address:       "192.0.2.10",
+		SubnetMask:    "255.255.255.0",
+		Gateway:       "192.0.2.1",
+		AddressOrigin: "DHCP",
+	}
+	return &EthernetInterface{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#EthernetInterface.EthernetInterface",
+			ODataID:      NewODataID("/redfish/v1/Managers", managerID, "EthernetInterfaces", id),
+			ODataType:    "#EthernetInterface.v1_6_0.EthernetInterface",
+			ID:           id,
+			Name:         "Manager Ethernet Interface",
+		},
+		Status:              Status{State: "Enabled", Health: "OK"},
+		InterfaceEnabled:    true,
+		PermanentMACAddress: "00:00:5e:00:53:01",
+		MACAddress:          "00:00:5e:00:53:01",
+		SpeedMbps:           1000,
+		HostName:            "bmc",
+		DHCPv4:              DHCPv4{DHCPEnabled: true},
+		IPv4Addresses:       []IPv4Address{lease},
+		IPv4StaticAddresses: []IPv4Address{},
+		IPv6Addresses: []IPv6Address{{
+			Address:       "fe80::200:5eff:fe00:5301",
+			PrefixLength:  64,
+			AddressOrigin: "LinkLocal",
+			AddressState:  "Preferred",
+		}},
+		IPv6StaticAddresses:    []IPv6Address{},
+		MaxIPv6StaticAddresses: MaxIPv6StaticAddresses,
+		dhcpLease:              lease,
+	}
+}
+
+// SetIPv4Configuration sets whether DHCP assigns the IPv4 address and the
+// static addresses used otherwise. IPv4Addresses then lists the DHCP lease
+// or the static addresses. Callers ensure static addresses are only given
+// with DHCP disabled.
+func (e *EthernetInterface) SetIPv4Configuration(dhcpEnabled bool, addresses []IPv4Address) {
+	e.DHCPv4.DHCPEnabled = dhcpEnabled
+	e.IPv4StaticAddresses = make([]IPv4Address, 0, len(addresses))
+	for _, address := range addresses {
+		e.IPv4StaticAddresses = append(e.IPv4StaticAddresses, IPv4Address{Address: address.Address, SubnetMask: address.SubnetMask, Gateway: address.Gateway})
+	}
+	if dhcpEnabled {
+		e.IPv4Addresses = []IPv4Address{e.dhcpLease}
+		return
+	}
+	e.IPv4Addresses = make([]IPv4Address, 0, len(addresses))
+	for _, address := range e.IPv4StaticAddresses {
+		address.AddressOrigin = "Static"
+		e.IPv4Addresses = append(e.IPv4Addresses, address)
+	}
+}
+
+// SetIPv6StaticAddresses replaces the static IPv6 addresses, which are then
+// listed in IPv6Addresses alongside the addresses of other origins
+func (e *EthernetInterface) SetIPv6StaticAddresses(addresses []IPv6Address) {
+	e.IPv6StaticAddresses = make([]IPv6Address, 0, len(addresses))
+	assigned := make([]IPv6Address, 0, len(e.IPv6Addresses)+len(addresses))
+	for _, address := range e.IPv6Addresses {
+		if address.AddressOrigin != "Static" {
+			assigned = append(assigned, address)
+		}
+	}
+	for _, address := range addresses {
+		e.IPv6StaticAddresses = append(e.IPv6StaticAddresses, IPv6Address{Address: address.Address, PrefixLength: address.PrefixLength})
+		assigned = append(assigned, IPv6Address{
+			Address:       address.Address,
+			PrefixLength:  address.PrefixLength,
+			AddressOrigin: "Static",
+			AddressState:  "Preferred",
+		})
+	}
+	e.IPv6Addresses = assigned
+}
+
+// EthernetInterfaceCollection represents the Ethernet interfaces of a manager
+type EthernetInterfaceCollection struct {
+	Collection
+}
+
+// NewEthernetInterfaceCollection creates an EthernetInterfaceCollection with the given members
+func NewEthernetInterfaceCollection(managerID string, members []Link) *EthernetInterfaceCollection {
+	return &EthernetInterfaceCollection{
+		Collection: Collection{
+			ODataContext:      "/redfish/v1/$metadata#EthernetInterfaceCollection.EthernetInterfaceCollection",
+			ODataID:           NewODataID("/redfish/v1/Managers", managerID, "EthernetInterfaces"),
+			ODataType:         "#EthernetInterfaceCollection.EthernetInterfaceCollection",
+			Name:              "Ethernet Interface Collection",
+			Members:           CollectionMembers(members),
+			MembersODataCount: len(members),
+		},
+	}
+}
@@ -3,7 +3,7 @@ package models
 // EventService represents the EventService resource
 type EventService struct {
 	Resource
-	ServiceEnabled                    bool              `json:"ServiceEnabled,omitempty"`
+	ServiceEnabled                    bool              `json:"ServiceEnabled"`
 	DeliveryRetryAttempts             int               `json:"DeliveryRetryAttempts,omitempty"`
 	DeliveryRetryIntervalSeconds      int               `json:"DeliveryRetryIntervalSeconds,omitempty"`
 	EventFormatTypes                  []string          `json:"EventFormatTypes,omitempty"`
@@ -61,24 +61,72 @@ func NewEventService() *EventService {
 // EventSubscription represents an event subscription (EventDestination)
 type EventSubscription struct {
 	Resource
-	Context                  string       `json:"Context,omitempty"`
-	DeliveryRetryPolicy      string       `json:"DeliveryRetryPolicy,omitempty"`
-	Destination              string       `json:"Destination"`
-	EventFormatType          string       `json:"EventFormatType,omitempty"`
-	ExcludeMessageIds        []string     `json:"ExcludeMessageIds,omitempty"`
-	ExcludeRegistryPrefixes  []string     `json:"ExcludeRegistryPrefixes,omitempty"`
-	HttpHeaders              []HttpHeader `json:"HttpHeaders,omitempty"`
-	IncludeOriginOfCondition bool         `json:"IncludeOriginOfCondition,omitempty"`
-	MessageIds               []string     `json:"MessageIds,omitempty"`
-	OriginResources          []ODataID    `json:"OriginResources,omitempty"`
-	Protocol                 string       `json:"Protocol"`
-	RegistryPrefixes         []string     `json:"RegistryPrefixes,omitempty"`
-	ResourceTypes            []string     `json:"ResourceTypes,omitempty"`
-	Severities               []string     `json:"Severities,omitempty"`
-	Status                   Status       `json:"Status,omitempty"`
-	SubordinateResources     bool         `json:"SubordinateResources,omitempty"`
-	SubscriptionType         string       `json:"SubscriptionType"`
-	Actions                  Actions      `json:"Actions,omitempty"`
+	Context                  string                `json:"Context,omitempty"`
+	DeliveryRetryPolicy      string                `json:"DeliveryRetryPolicy,omitempty"`
+	Destination              string                `json:"Destination"`
+	EventFormatType          string                `json:"EventFormatType,omitempty"`
+	ExcludeMessageIds        []string              `json:"ExcludeMessageIds,omitempty"`
+	ExcludeRegistryPrefixes  []string              `json:"ExcludeRegistryPrefixes,omitempty"`
+	HttpHeaders              []HttpHeader          `json:"HttpHeaders,omitempty"`
+	IncludeOriginOfCondition bool                  `json:"IncludeOriginOfCondition,omitempty"`
+	MessageIds               []string              `json:"MessageIds,omitempty"`
+	OriginResources          []ODataID             `json:"OriginResources,omitempty"`
+	Protocol                 string                `json:"Protocol"`
+	RegistryPrefixes         []string              `json:"RegistryPrefixes,omitempty"`
+	ResourceTypes            []string              `json:"ResourceTypes,omitempty"`
+	Severities               []string              `json:"Severities,omitempty"`
+	Status                   Status                `json:"Status,omitempty"`
+	SubordinateResources     bool                  `json:"SubordinateResources,omitempty"`
+	SubscriptionType         string                `json:"SubscriptionType"`
+	VerifyCertificate        *bool                 `json:"VerifyCertificate,omitempty"` // Only an explicit false skips TLS verification
+	Certificates             *Link                 `json:"Certificates,omitempty"`      // Certificates pinned for the destination
+	Actions                  Actions               `json:"Actions,omitempty"`
+	Oem                      *EventSubscriptionOem `json:"Oem,omitempty"`
+}
+
+// EventSubscriptionOem holds this service's Oem extensions to a subscription
+type EventSubscriptionOem struct {
+	Contoso *ContosoSubscriptionOem `json:"Contoso,omitempty"`
+}
+
+// ContosoSubscriptionOem is the Contoso vendor section of a subscription's
+// Oem block
+type ContosoSubscriptionOem struct {
+	EventBatching *EventBatching `json:"EventBatching,omitempty"`
+}
+
+// EventBatching groups a subscription's event records into fewer deliveries.
+// Records are held until MaxEvents of them are pending or FlushIntervalMs
+// has passed since the first, then sent together in one Event payload.
+type EventBatching struct {
+	MaxEvents       int `json:"MaxEvents"`
+	FlushIntervalMs int `json:"FlushIntervalMs"`
+}
+
+// Batching returns the subscription's event batching settings, or nil when
+// each event is delivered on its own
+func (s *EventSubscription) Batching() *EventBatching {
+	if s.Oem == nil || s.Oem.Contoso == nil {
+		return nil
+	}
+	return s.Oem.Contoso.EventBatching
+}
+
+// IsSSE reports whether the subscription stands for an open Server-Sent
+// Events stream. Such a subscription lasts only as long as its stream.
+func (s *EventSubscription) IsSSE() bool {
+	return s.SubscriptionType == "SSE"
+}
+
+// NewSSESubscription creates the transient subscription of an SSE stream
+// opened from destination, the client's address
+func NewSSESubscription(id string, destination string) *EventSubscription {
+	subscription := NewEventSubscription(id, destination, "Redfish")
+	subscription.Name = "SSE Subscription " + id
+	subscription.SubscriptionType = "SSE"
+	subscription.VerifyCertificate = nil
+	subscription.Certificates = nil
+	return subscription
 }
 
 // HttpHeader represents an HTTP header for event delivery
@@ -89,10 +137,11 @@ type HttpHeader struct {
 
 // NewEventSubscription creates a new EventSubscription instance
 func NewEventSubscription(id string, destination string, protocol string) *EventSubscription {
+	verify := true
 	return &EventSubscription{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#EventDestination.EventDestination",
-			ODataID:      ODataID("/redfish/v1/EventService/Subscriptions/" + id),
+			ODataID:      NewODataID("/redfish/v1/EventService/Subscriptions", id),
 			ODataType:    "#EventDestination.v1_15_1.EventDestination",
 			ID:           id,
 			Name:         "Event Subscription " + id,
@@ -103,6 +152,8 @@ func NewEventSubscription(id string, destination string, protocol string) *Event
 		EventFormatType:          "Event",
 		IncludeOriginOfCondition: false,
 		SubordinateResources:     false,
+		VerifyCertificate:        &verify,
+		Certificates:             &Link{ODataID: NewODataID("/redfish/v1/EventService/Subscriptions", id, "Certificates")},
 		Status: Status{
 			State:  "Enabled",
 			Health: "OK",
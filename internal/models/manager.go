@@ -19,6 +19,7 @@ type Manager struct {
 	VirtualMedia          Link           `json:"VirtualMedia,omitempty"`
 	Links                 ManagerLinks   `json:"Links,omitempty"`
 	Actions               ManagerActions `json:"Actions,omitempty"`
+	Oem                   OEM            `json:"Oem,omitempty"`
 }
 
 // ManagerLinks represents links to related resources
@@ -39,7 +40,8 @@ type ManagerActions struct {
 		Target string `json:"target"`
 		Title  string `json:"title,omitempty"`
 	} `json:"#Manager.ForceFailover,omitempty"`
-	Oem Oem `json:"Oem,omitempty"`
+	// Oem advertises the registered OEM actions, keyed by #Vendor.Action
+	Oem map[string]interface{} `json:"Oem,omitempty"`
 }
 
 // NewManager creates a new Manager instance
@@ -47,7 +49,7 @@ func NewManager(id string) *Manager {
 	return &Manager{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#Manager.Manager",
-			ODataID:      ODataID("/redfish/v1/Managers/" + id),
+			ODataID:      NewODataID("/redfish/v1/Managers", id),
 			ODataType:    "#Manager.v1_20_0.Manager",
 			ID:           id,
 			Name:         "Manager",
@@ -60,13 +62,14 @@ func NewManager(id string) *Manager {
 		},
 		PowerState:            "On",
 		ServiceIdentification: "BMC",
-		UUID:                  "00000000-0000-0000-0000-000000000001",
+		UUID:                  demoUUID("Manager", id),
 		Model:                 "Baseboard Management Controller",
 		DateTime:              "2025-10-29T18:48:45+00:00",
 		DateTimeLocalOffset:   "+00:00",
-		NetworkProtocol:       Link{ODataID: ODataID("/redfish/v1/Managers/" + id + "/NetworkProtocol")},
-		EthernetInterfaces:    Link{ODataID: ODataID("/redfish/v1/Managers/" + id + "/EthernetInterfaces")},
-		LogServices:           Link{ODataID: ODataID("/redfish/v1/Managers/" + id + "/LogServices")},
+		NetworkProtocol:       Link{ODataID: NewODataID("/redfish/v1/Managers", id, "NetworkProtocol")},
+		EthernetInterfaces:    Link{ODataID: NewODataID("/redfish/v1/Managers", id, "EthernetInterfaces")},
+		SerialInterfaces:      Link{ODataID: NewODataID("/redfish/v1/Managers", id, "SerialInterfaces")},
+		LogServices:           Link{ODataID: NewODataID("/redfish/v1/Managers", id, "LogServices")},
 		Links: ManagerLinks{
 			ManagerForServers: []Link{Link{ODataID: "/redfish/v1/Systems/1"}},
 			ManagerForChassis: []Link{Link{ODataID: "/redfish/v1/Chassis/1"}},
@@ -80,6 +83,7 @@ func NewManager(id string) *Manager {
 				Title:  "Reset Manager",
 			},
 		},
+		Oem: BuildOem("Manager", id),
 	}
 }
 
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"sort"
+	"sync"
+)
+
+// OEM holds the vendor-specific Oem payloads of a resource, keyed by vendor
+type OEM map[string]interface{}
+
+// OemFactory builds a vendor's Oem payload for a resource.
+// Returning nil leaves the vendor out of that resource's Oem block.
+type OemFactory func(resourceType, id string) interface{}
+
+var (
+	oemMutex     sync.RWMutex
+	oemFactories = make(map[string]OemFactory)
+)
+
+// RegisterOem registers the Oem payload factory for a vendor, replacing any
+// previous registration under the same vendor key
+func RegisterOem(vendor string, factory OemFactory) {
+	oemMutex.Lock()
+	defer oemMutex.Unlock()
+	oemFactories[vendor] = factory
+}
+
+// UnregisterOem removes a vendor's Oem payload factory
+func UnregisterOem(vendor string) {
+	oemMutex.Lock()
+	defer oemMutex.Unlock()
+	delete(oemFactories, vendor)
+}
+
+// OemVendors returns the registered vendor keys in sorted order
+func OemVendors() []string {
+	oemMutex.RLock()
+	defer oemMutex.RUnlock()
+
+	vendors := make([]string, 0, len(oemFactories))
+	for vendor := range oemFactories {
+		vendors = append(vendors, vendor)
+	}
+	sort.Strings(vendors)
+	return vendors
+}
+
+// BuildOem assembles the Oem block for a resource from all registered vendors.
+// It returns nil when no vendor contributes, so the block is omitted.
+func BuildOem(resourceType, id string) OEM {
+	oemMutex.RLock()
+	defer oemMutex.RUnlock()
+
+	var oem OEM
+	for vendor, factory := range oemFactories {
+		payload := factory(resourceType, id)
+		if payload == nil {
+			continue
+		}
+		if oem == nil {
+			oem = make(OEM)
+		}
+		oem[vendor] = payload
+	}
+	return oem
+}
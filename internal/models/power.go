@@ -0,0 +1,194 @@
+package models
+
+import "strconv"
+
+// Power represents the legacy Power resource of a chassis, superseded by
+// PowerSubsystem
+type Power struct {
+	Resource
+	PowerControl  []PowerControl     `json:"PowerControl"`
+	PowerSupplies []PowerSupplyEntry `json:"PowerSupplies"`
+}
+
+// PowerControl reports the power consumption of a chassis in the legacy
+// Power resource
+type PowerControl struct {
+	ODataID            string  `json:"@odata.id"`
+	MemberID           string  `json:"MemberId"`
+	Name               string  `json:"Name"`
+	PowerConsumedWatts float64 `json:"PowerConsumedWatts"`
+	PowerCapacityWatts float64 `json:"PowerCapacityWatts"`
+	Status             Status  `json:"Status"`
+}
+
+// PowerSupplyEntry is a power supply listed in the legacy Power resource
+type PowerSupplyEntry struct {
+	ODataID              string  `json:"@odata.id"`
+	MemberID             string  `json:"MemberId"`
+	Name                 string  `json:"Name"`
+	PowerCapacityWatts   float64 `json:"PowerCapacityWatts"`
+	PowerInputWatts      float64 `json:"PowerInputWatts"`
+	LastPowerOutputWatts float64 `json:"LastPowerOutputWatts"`
+	Status               Status  `json:"Status"`
+}
+
+// NewPower creates the legacy Power resource of a chassis from its environment
+func NewPower(chassisID string, environment *ChassisEnvironment) *Power {
+	id := NewODataID("/redfish/v1/Chassis", chassisID, "Power")
+	power := &Power{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Power.Power",
+			ODataID:      id,
+			ODataType:    "#Power.v1_7_1.Power",
+			ID:           "Power",
+			Name:         "Power",
+		},
+		PowerSupplies: make([]PowerSupplyEntry, 0, len(environment.PowerSupplies)),
+	}
+
+	var consumed, capacity float64
+	for i, supply := range environment.PowerSupplies {
+		consumed += supply.OutputWatts
+		capacity += supply.CapacityWatts
+		power.PowerSupplies = append(power.PowerSupplies, PowerSupplyEntry{
+			ODataID:              string(id) + "#/PowerSupplies/" + strconv.Itoa(i),
+			MemberID:             supply.ID,
+			Name:                 supply.Name,
+			PowerCapacityWatts:   supply.CapacityWatts,
+			PowerInputWatts:      supply.InputWatts,
+			LastPowerOutputWatts: supply.OutputWatts,
+			Status:               Status{State: "Enabled", Health: "OK"},
+		})
+	}
+	power.PowerControl = []PowerControl{{
+		ODataID:            string(id) + "#/PowerControl/0",
+		MemberID:           "0",
+		Name:               "Chassis Power Control",
+		PowerConsumedWatts: consumed,
+		PowerCapacityWatts: capacity,
+		Status:             Status{State: "Enabled", Health: "OK"},
+	}}
+	return power
+}
+
+// PowerSubsystem represents the power supplies and batteries of a chassis
+type PowerSubsystem struct {
+	Resource
+	CapacityWatts float64 `json:"CapacityWatts"`
+	Status        Status  `json:"Status"`
+	PowerSupplies Link    `json:"PowerSupplies"`
+	Batteries     Link    `json:"Batteries"`
+}
+
+// NewPowerSubsystem creates the PowerSubsystem of a chassis from its environment
+func NewPowerSubsystem(chassisID string, environment *ChassisEnvironment) *PowerSubsystem {
+	var capacity float64
+	for _, supply := range environment.PowerSupplies {
+		capacity += supply.CapacityWatts
+	}
+	return &PowerSubsystem{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#PowerSubsystem.PowerSubsystem",
+			ODataID:      NewODataID("/redfish/v1/Chassis", chassisID, "PowerSubsystem"),
+			ODataType:    "#PowerSubsystem.v1_1_0.PowerSubsystem",
+			ID:           "PowerSubsystem",
+			Name:         "Power Subsystem",
+		},
+		CapacityWatts: capacity,
+		Status:        Status{State: "Enabled", Health: "OK"},
+		PowerSupplies: Link{ODataID: NewODataID("/redfish/v1/Chassis", chassisID, "PowerSubsystem", "PowerSupplies")},
+		Batteries:     Link{ODataID: NewODataID("/redfish/v1/Chassis", chassisID, "PowerSubsystem", "Batteries")},
+	}
+}
+
+// PowerSupply represents a power supply in a PowerSubsystem
+type PowerSupply struct {
+	Resource
+	PowerSupplyType    string  `json:"PowerSupplyType"`
+	PowerCapacityWatts float64 `json:"PowerCapacityWatts"`
+	LineInputStatus    string  `json:"LineInputStatus"`
+	Status             Status  `json:"Status"`
+}
+
+// NewPowerSupply creates a PowerSupply of a chassis from its reading
+func NewPowerSupply(chassisID string, reading PowerSupplyReading) *PowerSupply {
+	return &PowerSupply{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#PowerSupply.PowerSupply",
+			ODataID:      NewODataID("/redfish/v1/Chassis", chassisID, "PowerSubsystem", "PowerSupplies", reading.ID),
+			ODataType:    "#PowerSupply.v1_5_0.PowerSupply",
+			ID:           reading.ID,
+			Name:         reading.Name,
+		},
+		PowerSupplyType:    "AC",
+		PowerCapacityWatts: reading.CapacityWatts,
+		LineInputStatus:    "Normal",
+		Status:             Status{State: "Enabled", Health: "OK"},
+	}
+}
+
+// Battery represents a battery in a PowerSubsystem
+type Battery struct {
+	Resource
+	CapacityActualWattHours float64 `json:"CapacityActualWattHours"`
+	ChargeState             string  `json:"ChargeState"`
+	Status                  Status  `json:"Status"`
+}
+
+// NewBattery creates a Battery of a chassis from its reading
+func NewBattery(chassisID string, reading BatteryReading) *Battery {
+	chargeState := "Idle"
+	if reading.ChargePercent < 100 {
+		chargeState = "Charging"
+	}
+	return &Battery{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Battery.Battery",
+			ODataID:      NewODataID("/redfish/v1/Chassis", chassisID, "PowerSubsystem", "Batteries", reading.ID),
+			ODataType:    "#Battery.v1_2_0.Battery",
+			ID:           reading.ID,
+			Name:         reading.Name,
+		},
+		CapacityActualWattHours: reading.CapacityWattHours,
+		ChargeState:             chargeState,
+		Status:                  Status{State: "Enabled", Health: "OK"},
+	}
+}
+
+// PowerSupplyCollection represents the PowerSupplies of a chassis PowerSubsystem
+type PowerSupplyCollection struct {
+	Collection
+}
+
+// NewPowerSupplyCollection creates a PowerSupplyCollection with the given members
+func NewPowerSupplyCollection(chassisID string, members []Link) *PowerSupplyCollection {
+	return &PowerSupplyCollection{
+		Collection: Collection{
+			ODataContext:      "/redfish/v1/$metadata#PowerSupplyCollection.PowerSupplyCollection",
+			ODataID:           NewODataID("/redfish/v1/Chassis", chassisID, "PowerSubsystem", "PowerSupplies"),
+			ODataType:         "#PowerSupplyCollection.PowerSupplyCollection",
+			Name:              "Power Supply Collection",
+			Members:           CollectionMembers(members),
+			MembersODataCount: len(members),
+		},
+	}
+}
+
+// BatteryCollection represents the Batteries of a chassis PowerSubsystem
+type BatteryCollection struct {
+	Collection
+}
+
+// NewBatteryCollection creates a BatteryCollection with the given members
+func NewBatteryCollection(chassisID string, members []Link) *BatteryCollection {
+	return &BatteryCollection{
+		Collection: Collection{
+			ODataContext:      "/redfish/v1/$metadata#BatteryCollection.BatteryCollection",
+			ODataID:           NewODataID("/redfish/v1/Chassis", chassisID, "PowerSubsystem", "Batteries"),
+			ODataType:         "#BatteryCollection.BatteryCollection",
+			Name:              "Battery Collection",
+			Members:           CollectionMembers(members),
+			MembersODataCount: len(members),
+		},
+	}
+}
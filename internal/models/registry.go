@@ -1,10 +1,40 @@
 package models
 
+import (
+	"strconv"
+	"strings"
+)
+
 // MessageRegistry represents a message registry containing message definitions
 type MessageRegistry struct {
 	Resource
-	Language string                     `json:"Language"`
-	Messages map[string]RegistryMessage `json:"Messages"`
+	Language        string                     `json:"Language"`
+	OwningEntity    string                     `json:"OwningEntity"`
+	RegistryPrefix  string                     `json:"RegistryPrefix"`
+	RegistryVersion string                     `json:"RegistryVersion"`
+	Messages        map[string]RegistryMessage `json:"Messages"`
+}
+
+// MessageID returns the fully qualified MessageId for a message key,
+// e.g. Base.1.0.ResourceNotFound
+func (m *MessageRegistry) MessageID(key string) string {
+	version := strings.Join(strings.Split(m.RegistryVersion, ".")[:2], ".")
+	return m.RegistryPrefix + "." + version + "." + key
+}
+
+// Resolve returns the text of a message with its %1, %2, ... placeholders
+// replaced by args, or false if the registry has no such message
+func (m *MessageRegistry) Resolve(key string, args ...string) (string, bool) {
+	entry, ok := m.Messages[key]
+	if !ok {
+		return "", false
+	}
+	// Higher placeholders go first so %1 does not consume the start of %10
+	pairs := make([]string, 0, 2*len(args))
+	for i := len(args); i >= 1; i-- {
+		pairs = append(pairs, "%"+strconv.Itoa(i), args[i-1])
+	}
+	return strings.NewReplacer(pairs...).Replace(entry.Message), true
 }
 
 // RegistryMessage represents a single message in a message registry
@@ -52,7 +82,10 @@ func NewMessageRegistry(language string) *MessageRegistry {
 			ID:           "Base.1.0.0",
 			Name:         "Base Message Registry",
 		},
-		Language: language,
+		Language:        language,
+		OwningEntity:    "DMTF",
+		RegistryPrefix:  "Base",
+		RegistryVersion: "1.0.0",
 		Messages: map[string]RegistryMessage{
 			"Success": {
 				Description:     "Indicates a successful operation",
@@ -90,38 +123,278 @@ func NewMessageRegistry(language string) *MessageRegistry {
 				ParamTypes:      []string{"string", "string"},
 				ArgDescriptions: []string{"Property value", "Property name"},
 			},
+			"PropertyValueTypeError": {
+				Description:     "The property value is of a different type than the property can accept",
+				Message:         "The value %1 for the property %2 is of a different type than the property can accept",
+				NumberOfArgs:    2,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Correct the value for the property in the request body and resubmit the request",
+				ParamTypes:      []string{"string", "string"},
+				ArgDescriptions: []string{"Property value", "Property name"},
+			},
+			"PropertyValueOutOfRange": {
+				Description:     "The property value is outside the range the service supports",
+				Message:         "The value %1 for the property %2 is not in the supported range of acceptable values",
+				NumberOfArgs:    2,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Correct the value for the property in the request body and resubmit the request",
+				ParamTypes:      []string{"string", "string"},
+				ArgDescriptions: []string{"Property value", "Property name"},
+			},
+			"PropertyValueFormatError": {
+				Description:     "The property value is of a different format than the property can accept",
+				Message:         "The value %1 for the property %2 is of a different format than the property can accept",
+				NumberOfArgs:    2,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Correct the value for the property in the request body and resubmit the request",
+				ParamTypes:      []string{"string", "string"},
+				ArgDescriptions: []string{"Property value", "Property name"},
+			},
+			"ResourceAlreadyExists": {
+				Description:     "The requested resource already exists",
+				Message:         "The requested resource of type %1 with the property %2 with the value %3 already exists",
+				NumberOfArgs:    3,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "Do not repeat the create operation as the resource has already been created",
+				ParamTypes:      []string{"string", "string", "string"},
+				ArgDescriptions: []string{"Resource type", "Property name", "Property value"},
+			},
+			"PropertyValueConflict": {
+				Description:     "Indicates that the requested write of a property could not be completed because of a conflict with another property",
+				Message:         "The property %1 could not be written because its value would conflict with the value of the %2 property",
+				NumberOfArgs:    2,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "No resolution is required",
+				ParamTypes:      []string{"string", "string"},
+				ArgDescriptions: []string{"The name of the property for which a write was requested", "The name of the conflicting property"},
+			},
+			"PropertyNotWritable": {
+				Description:     "The property is a read only property and cannot be assigned a value",
+				Message:         "The property %1 is a read only property and cannot be assigned a value",
+				NumberOfArgs:    1,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Remove the property from the request body and resubmit the request",
+				ParamTypes:      []string{"string"},
+				ArgDescriptions: []string{"Property name"},
+			},
+			"QueryParameterValueFormatError": {
+				Description:     "The value of a query parameter is of a different format than the parameter can accept",
+				Message:         "The value %1 for the parameter %2 is of a different format than the parameter can accept",
+				NumberOfArgs:    2,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Correct the value for the query parameter in the request and resubmit the request",
+				ParamTypes:      []string{"string", "string"},
+				ArgDescriptions: []string{"Parameter value", "Parameter name"},
+			},
+			"PropertyUnknown": {
+				Description:     "The property is not known to the resource",
+				Message:         "The property %1 is not in the list of valid properties for the resource",
+				NumberOfArgs:    1,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Remove the unknown property from the request body and resubmit the request",
+				ParamTypes:      []string{"string"},
+				ArgDescriptions: []string{"Property name"},
+			},
+			"PropertyMissing": {
+				Description:     "A required property was not supplied in the request",
+				Message:         "The property %1 is a required property and must be included in the request",
+				NumberOfArgs:    1,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Ensure that the property is in the request body and has a valid value and resubmit the request",
+				ParamTypes:      []string{"string"},
+				ArgDescriptions: []string{"Property name"},
+			},
+			"OperationNotAllowed": {
+				Description:     "Indicates that the HTTP method in the request is not allowed on this resource",
+				Message:         "The HTTP method is not allowed on this resource",
+				NumberOfArgs:    0,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "None",
+			},
+			"OperationNotImplemented": {
+				Description:     "Indicates that the HTTP method in the request is valid for this resource but is not implemented by the service",
+				Message:         "The HTTP method is not implemented on this resource",
+				NumberOfArgs:    0,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "None",
+			},
+			"ServiceInUse": {
+				Description:     "Indicates that the operation failed because the service is in use or in transition",
+				Message:         "The operation failed because the service is in use or in transition",
+				NumberOfArgs:    0,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Wait for the service to become available and resubmit the request",
+			},
+			"ServiceShuttingDown": {
+				Description:     "Indicates that the operation failed because the service is shutting down, such as when the service reboots",
+				Message:         "The operation failed because the service is shutting down and can no longer take incoming requests",
+				NumberOfArgs:    0,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "When the service becomes available, resubmit the request if the operation failed",
+			},
+			"CreateLimitReachedForResource": {
+				Description:     "Indicates that no more resources can be created on the resource because it has reached its create limit",
+				Message:         "The create operation failed because the resource has reached the limit of possible resources",
+				NumberOfArgs:    0,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "Either delete resources and resubmit the request if the operation failed or do not resubmit the request",
+			},
+			"ServiceDisabled": {
+				Description:     "Indicates that the operation failed because the service, such as the account service, is disabled and cannot accept requests",
+				Message:         "The operation failed because the service at %1 is disabled and cannot accept requests",
+				NumberOfArgs:    1,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Enable the service and resubmit the request if the operation failed",
+				ParamTypes:      []string{"string"},
+				ArgDescriptions: []string{"The URI of the disabled service"},
+			},
+			"QueryCombinationInvalid": {
+				Description:     "Indicates that the request contains multiple query parameters and that two or more of them cannot be used together",
+				Message:         "Two or more query parameters in the request cannot be used together",
+				NumberOfArgs:    0,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Remove one or more of the query parameters and resubmit the request",
+			},
+			"QueryParameterOutOfRange": {
+				Description:     "Indicates that a query parameter was supplied that is out of range for the given resource",
+				Message:         "The value %1 for the query parameter %2 is out of range %3",
+				NumberOfArgs:    3,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Reduce the value for the query parameter to a value that is within range",
+				ParamTypes:      []string{"string", "string", "string"},
+				ArgDescriptions: []string{"Query parameter value", "Query parameter name", "Valid range"},
+			},
+			"ActionNotSupported": {
+				Description:     "Indicates that the action supplied with the POST operation is not supported by the resource",
+				Message:         "The action %1 is not supported by the resource",
+				NumberOfArgs:    1,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "The action supplied cannot be resubmitted to the implementation",
+				ParamTypes:      []string{"string"},
+				ArgDescriptions: []string{"Action name"},
+			},
+			"ActionParameterNotSupported": {
+				Description:     "Indicates that the parameter supplied for the action is not supported on the resource",
+				Message:         "The parameter %1 for the action %2 is not supported on the target resource",
+				NumberOfArgs:    2,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Remove the parameter supplied and resubmit the request if the operation failed",
+				ParamTypes:      []string{"string", "string"},
+				ArgDescriptions: []string{"Parameter name", "Action name"},
+			},
+			"ActionParameterValueNotInList": {
+				Description:     "Indicates that a parameter was given the correct value type but the value is not supported",
+				Message:         "The value %1 for the parameter %2 in the action %3 is not in the list of acceptable values",
+				NumberOfArgs:    3,
+				MessageSeverity: "Warning",
+				Severity:        "Warning",
+				Resolution:      "Choose a value from the enumeration list and resubmit the request",
+				ParamTypes:      []string{"string", "string", "string"},
+				ArgDescriptions: []string{"Parameter value", "Parameter name", "Action name"},
+			},
+			"ActionParameterMissing": {
+				Description:     "Indicates that the action requested was missing a parameter that is required to process the action",
+				Message:         "The action %1 requires the parameter %2 to be present in the request body",
+				NumberOfArgs:    2,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "Supply the action with the required parameter in the request body when the request is resubmitted",
+				ParamTypes:      []string{"string", "string"},
+				ArgDescriptions: []string{"Action name", "Parameter name"},
+			},
+			"MalformedJSON": {
+				Description:     "Indicates that the request body was malformed JSON",
+				Message:         "The request body submitted was malformed JSON and could not be parsed by the receiving service",
+				NumberOfArgs:    0,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "Ensure that the request body is valid JSON and resubmit the request",
+			},
+			"HeaderInvalid": {
+				Description:     "Indicates that a request header is invalid",
+				Message:         "The header %1 is invalid",
+				NumberOfArgs:    1,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "Resubmit the request with a valid header",
+				ParamTypes:      []string{"string"},
+				ArgDescriptions: []string{"Header name"},
+			},
+			"InsufficientPrivilege": {
+				Description:     "Indicates that the credentials associated with the established session do not have sufficient privileges",
+				Message:         "There are insufficient privileges for the account or credentials associated with the current session to perform the requested operation",
+				NumberOfArgs:    0,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "Either abandon the operation or change the associated access rights and resubmit the request",
+			},
+			"PreconditionFailed": {
+				Description:     "Indicates that the ETag supplied did not match the current ETag of the resource",
+				Message:         "The ETag supplied did not match the ETag required to change this resource",
+				NumberOfArgs:    0,
+				MessageSeverity: "Critical",
+				Severity:        "Critical",
+				Resolution:      "Try the operation again using the appropriate ETag",
+			},
 		},
 	}
 }
 
-// NewMessageRegistryFile creates a new MessageRegistryFile instance
-func NewMessageRegistryFile(id string, registry string) *MessageRegistryFile {
+// NewMessageRegistryFile creates a new MessageRegistryFile instance locating
+// the registry in each of languages, English when none are given. Only the
+// English registry has a DMTF publication; translations are served under a
+// language-suffixed URI.
+func NewMessageRegistryFile(id string, registry string, languages ...string) *MessageRegistryFile {
+	if len(languages) == 0 {
+		languages = []string{"en"}
+	}
+	locations := make([]RegistryFileLocation, 0, len(languages))
+	for _, language := range languages {
+		location := RegistryFileLocation{
+			Language: language,
+			Uri:      "/redfish/v1/Registries/" + id + "." + language + ".json",
+		}
+		if language == "en" {
+			location.Uri = "/redfish/v1/Registries/" + id + ".json"
+			location.PublicationUri = "https://www.dmtf.org/sites/default/files/standards/documents/DSP8011_" + registry + ".json"
+		}
+		locations = append(locations, location)
+	}
+
 	return &MessageRegistryFile{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#MessageRegistryFile.MessageRegistryFile",
-			ODataID:      ODataID("/redfish/v1/Registries/" + id),
+			ODataID:      NewODataID("/redfish/v1/Registries", id),
 			ODataType:    "#MessageRegistryFile.v1_1_5.MessageRegistryFile",
 			ID:           id,
 			Name:         registry + " Message Registry File",
 			Description:  registry + " Message Registry File locations",
 		},
-		Languages: []string{"en"},
+		Languages: languages,
 		Registry:  registry,
-		Location: []RegistryFileLocation{
-			{
-				Language:       "en",
-				Uri:            "/redfish/v1/Registries/" + id + ".json",
-				PublicationUri: "https://www.dmtf.org/sites/default/files/standards/documents/DSP8011_" + registry + ".json",
-			},
-		},
+		Location:  locations,
 	}
 }
 
-// OEM represents OEM-specific extensions
-type OEM struct {
-	Contoso *ContosoOEM `json:"Contoso,omitempty"`
-}
-
 // ContosoOEM represents Contoso-specific OEM extensions
 type ContosoOEM struct {
 	VendorID         string                 `json:"VendorId,omitempty"`
@@ -14,7 +14,7 @@ func NewRole(id, name string, privileges []string, predefined bool) *Role {
 	return &Role{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#Role.Role",
-			ODataID:      ODataID("/redfish/v1/AccountService/Roles/" + id),
+			ODataID:      NewODataID("/redfish/v1/AccountService/Roles", id),
 			ODataType:    "#Role.v1_2_0.Role",
 			ID:           id,
 			Name:         name,
@@ -0,0 +1,63 @@
+package models
+
+// SerialInterface represents a serial port of a manager
+type SerialInterface struct {
+	Resource
+	InterfaceEnabled bool   `json:"InterfaceEnabled"`
+	SignalType       string `json:"SignalType,omitempty"` // Rs232, Rs485
+	BitRate          string `json:"BitRate,omitempty"`
+	Parity           string `json:"Parity,omitempty"`      // None, Even, Odd, Mark, Space
+	DataBits         string `json:"DataBits,omitempty"`    // 5, 6, 7, 8
+	StopBits         string `json:"StopBits,omitempty"`    // 1, 2
+	FlowControl      string `json:"FlowControl,omitempty"` // None, Software, Hardware
+	ConnectorType    string `json:"ConnectorType,omitempty"`
+}
+
+// Allowable values of the writable SerialInterface properties
+var (
+	SerialBitRates     = []string{"1200", "2400", "4800", "9600", "19200", "38400", "57600", "115200", "230400"}
+	SerialParities     = []string{"None", "Even", "Odd", "Mark", "Space"}
+	SerialDataBits     = []string{"5", "6", "7", "8"}
+	SerialStopBits     = []string{"1", "2"}
+	SerialFlowControls = []string{"None", "Software", "Hardware"}
+)
+
+// NewSerialInterface creates a SerialInterface with common console defaults
+func NewSerialInterface(managerID, id string) *SerialInterface {
+	return &SerialInterface{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#SerialInterface.SerialInterface",
+			ODataID:      NewODataID("/redfish/v1/Managers", managerID, "SerialInterfaces", id),
+			ODataType:    "#SerialInterface.v1_2_0.SerialInterface",
+			ID:           id,
+			Name:         "Manager Serial Interface",
+		},
+		InterfaceEnabled: true,
+		SignalType:       "Rs232",
+		BitRate:          "115200",
+		Parity:           "None",
+		DataBits:         "8",
+		StopBits:         "1",
+		FlowControl:      "None",
+		ConnectorType:    "DB9 Male",
+	}
+}
+
+// SerialInterfaceCollection represents the serial interfaces of a manager
+type SerialInterfaceCollection struct {
+	Collection
+}
+
+// NewSerialInterfaceCollection creates a SerialInterfaceCollection with the given members
+func NewSerialInterfaceCollection(managerID string, members []Link) *SerialInterfaceCollection {
+	return &SerialInterfaceCollection{
+		Collection: Collection{
+			ODataContext:      "/redfish/v1/$metadata#SerialInterfaceCollection.SerialInterfaceCollection",
+			ODataID:           NewODataID("/redfish/v1/Managers", managerID, "SerialInterfaces"),
+			ODataType:         "#SerialInterfaceCollection.SerialInterfaceCollection",
+			Name:              "Serial Interface Collection",
+			Members:           CollectionMembers(members),
+			MembersODataCount: len(members),
+		},
+	}
+}
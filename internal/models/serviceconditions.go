@@ -0,0 +1,44 @@
+package models
+
+// ServiceConditions summarizes the conditions active anywhere in the service
+type ServiceConditions struct {
+	Resource
+	HealthRollup string      `json:"HealthRollup"` // OK, Warning, Critical
+	Conditions   []Condition `json:"Conditions"`
+}
+
+// Condition represents a condition requiring attention on a resource
+type Condition struct {
+	MessageId         string   `json:"MessageId"`
+	Message           string   `json:"Message,omitempty"`
+	MessageArgs       []string `json:"MessageArgs,omitempty"`
+	Severity          string   `json:"Severity"` // Warning, Critical
+	OriginOfCondition *Link    `json:"OriginOfCondition,omitempty"`
+	Timestamp         string   `json:"Timestamp,omitempty"`
+}
+
+// NewServiceConditions creates a ServiceConditions resource listing the
+// given conditions, rolling their severities up into HealthRollup
+func NewServiceConditions(conditions []Condition) *ServiceConditions {
+	rollup := "OK"
+	for _, condition := range conditions {
+		switch {
+		case condition.Severity == "Critical":
+			rollup = "Critical"
+		case condition.Severity == "Warning" && rollup == "OK":
+			rollup = "Warning"
+		}
+	}
+
+	return &ServiceConditions{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#ServiceConditions.ServiceConditions",
+			ODataID:      "/redfish/v1/ServiceConditions",
+			ODataType:    "#ServiceConditions.v1_0_1.ServiceConditions",
+			ID:           "ServiceConditions",
+			Name:         "Redfish Service Conditions",
+		},
+		HealthRollup: rollup,
+		Conditions:   conditions,
+	}
+}
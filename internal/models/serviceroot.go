@@ -3,19 +3,68 @@ package models
 // ServiceRoot represents the root of the Redfish service
 type ServiceRoot struct {
 	Resource
-	RedfishVersion string           `json:"RedfishVersion"`
-	UUID           string           `json:"UUID,omitempty"`
-	Systems        Link             `json:"Systems,omitempty"`
-	Chassis        Link             `json:"Chassis,omitempty"`
-	Managers       Link             `json:"Managers,omitempty"`
-	Tasks          Link             `json:"Tasks,omitempty"`
-	SessionService Link             `json:"SessionService,omitempty"`
-	AccountService Link             `json:"AccountService,omitempty"`
-	EventService   Link             `json:"EventService,omitempty"`
-	Registries     Link             `json:"Registries,omitempty"`
-	JsonSchemas    Link             `json:"JsonSchemas,omitempty"`
-	UpdateService  Link             `json:"UpdateService,omitempty"`
-	Links          ServiceRootLinks `json:"Links,omitempty"`
+	RedfishVersion    string           `json:"RedfishVersion"`
+	UUID              string           `json:"UUID,omitempty"`
+	Systems           Link             `json:"Systems,omitempty"`
+	Chassis           Link             `json:"Chassis,omitempty"`
+	Managers          Link             `json:"Managers,omitempty"`
+	Tasks             Link             `json:"Tasks,omitempty"`
+	SessionService    Link             `json:"SessionService,omitempty"`
+	AccountService    Link             `json:"AccountService,omitempty"`
+	EventService      Link             `json:"EventService,omitempty"`
+	Registries        Link             `json:"Registries,omitempty"`
+	JsonSchemas       Link             `json:"JsonSchemas,omitempty"`
+	UpdateService     Link             `json:"UpdateService,omitempty"`
+	ServiceConditions *Link            `json:"ServiceConditions,omitempty"`
+	Links             ServiceRootLinks `json:"Links,omitempty"`
+	Oem               *ServiceRootOem  `json:"Oem,omitempty"`
+
+	ProtocolFeaturesSupported *ProtocolFeaturesSupported `json:"ProtocolFeaturesSupported,omitempty"`
+}
+
+// ProtocolFeaturesSupported advertises the optional protocol features the service implements
+type ProtocolFeaturesSupported struct {
+	ExpandQuery  ExpandQuery `json:"ExpandQuery"`
+	FilterQuery  bool        `json:"FilterQuery"`
+	SelectQuery  bool        `json:"SelectQuery"`
+	TopSkipQuery bool        `json:"TopSkipQuery"`
+}
+
+// ExpandQuery describes the $expand forms the service supports
+type ExpandQuery struct {
+	ExpandAll bool `json:"ExpandAll"`
+	Levels    bool `json:"Levels"`
+	Links     bool `json:"Links"`
+	NoLinks   bool `json:"NoLinks"`
+	MaxLevels int  `json:"MaxLevels"`
+}
+
+// NewProtocolFeaturesSupported describes the supported query parameters,
+// with $expand allowed up to maxExpandLevels deep
+func NewProtocolFeaturesSupported(maxExpandLevels int) *ProtocolFeaturesSupported {
+	return &ProtocolFeaturesSupported{
+		ExpandQuery: ExpandQuery{
+			Levels:    true,
+			MaxLevels: maxExpandLevels,
+		},
+		FilterQuery:  true,
+		SelectQuery:  true,
+		TopSkipQuery: true,
+	}
+}
+
+// ServiceRootOem holds the vendor extensions to the service root
+type ServiceRootOem struct {
+	Contoso *ContosoServiceRootOem `json:"Contoso,omitempty"`
+}
+
+// ContosoServiceRootOem is the Contoso vendor section of the service root's
+// Oem block: the operator's identification of this instance
+type ContosoServiceRootOem struct {
+	Welcome    string `json:"Welcome,omitempty"`
+	Datacenter string `json:"Datacenter,omitempty"`
+	Rack       string `json:"Rack,omitempty"`
+	Contact    string `json:"Contact,omitempty"`
 }
 
 // ServiceRootLinks represents the links in the ServiceRoot
@@ -23,27 +72,28 @@ type ServiceRootLinks struct {
 	Sessions Link `json:"Sessions,omitempty"`
 }
 
-// NewServiceRoot creates a new ServiceRoot instance
-func NewServiceRoot() *ServiceRoot {
+// NewServiceRoot creates a new ServiceRoot instance for the given service identity
+func NewServiceRoot(name, uuid, redfishVersion string) *ServiceRoot {
 	return &ServiceRoot{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#ServiceRoot.ServiceRoot",
 			ODataID:      "/redfish/v1/",
 			ODataType:    "#ServiceRoot.v1_15_0.ServiceRoot",
 			ID:           "RootService",
-			Name:         "Root Service",
+			Name:         name,
 		},
-		RedfishVersion: "1.15.0",
-		UUID:           "00000000-0000-0000-0000-000000000000",
-		Systems:        Link{ODataID: "/redfish/v1/Systems"},
-		Chassis:        Link{ODataID: "/redfish/v1/Chassis"},
-		Managers:       Link{ODataID: "/redfish/v1/Managers"},
-		Tasks:          Link{ODataID: "/redfish/v1/TaskService"},
-		SessionService: Link{ODataID: "/redfish/v1/SessionService"},
-		AccountService: Link{ODataID: "/redfish/v1/AccountService"},
-		EventService:   Link{ODataID: "/redfish/v1/EventService"},
-		Registries:     Link{ODataID: "/redfish/v1/Registries"},
-		JsonSchemas:    Link{ODataID: "/redfish/v1/JsonSchemas"},
+		RedfishVersion:    redfishVersion,
+		UUID:              uuid,
+		Systems:           Link{ODataID: "/redfish/v1/Systems"},
+		Chassis:           Link{ODataID: "/redfish/v1/Chassis"},
+		Managers:          Link{ODataID: "/redfish/v1/Managers"},
+		Tasks:             Link{ODataID: "/redfish/v1/TaskService"},
+		SessionService:    Link{ODataID: "/redfish/v1/SessionService"},
+		AccountService:    Link{ODataID: "/redfish/v1/AccountService"},
+		EventService:      Link{ODataID: "/redfish/v1/EventService"},
+		Registries:        Link{ODataID: "/redfish/v1/Registries"},
+		JsonSchemas:       Link{ODataID: "/redfish/v1/JsonSchemas"},
+		ServiceConditions: &Link{ODataID: "/redfish/v1/ServiceConditions"},
 		Links: ServiceRootLinks{
 			Sessions: Link{ODataID: "/redfish/v1/SessionService/Sessions"},
 		},
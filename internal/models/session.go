@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Session represents a user session
+type Session struct {
+	Resource
+	UserName string      `json:"UserName"`
+	Oem      *SessionOem `json:"Oem,omitempty"`
+}
+
+// SessionOem holds the vendor extensions to a session
+type SessionOem struct {
+	Contoso *ContosoSessionOem `json:"Contoso,omitempty"`
+}
+
+// ContosoSessionOem is the Contoso vendor section of a session's Oem block
+type ContosoSessionOem struct {
+	Expires string `json:"Expires"`
+}
+
+// NewSession creates the Session resource for the session with the given Id
+func NewSession(id, userName string, expires time.Time) *Session {
+	return &Session{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Session.Session",
+			ODataID:      NewODataID("/redfish/v1/SessionService/Sessions", id),
+			ODataType:    "#Session.v1_1_6.Session",
+			ID:           id,
+			Name:         "User Session",
+		},
+		UserName: userName,
+		Oem: &SessionOem{
+			Contoso: &ContosoSessionOem{Expires: expires.Format(time.RFC3339)},
+		},
+	}
+}
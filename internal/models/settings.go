@@ -0,0 +1,42 @@
+package models
+
+// Settings is the @Redfish.Settings annotation. It points at the settings
+// object where changes are staged until they can be applied.
+type Settings struct {
+	ODataType           string    `json:"@odata.type"`
+	SettingsObject      Link      `json:"SettingsObject"`
+	SupportedApplyTimes []string  `json:"SupportedApplyTimes,omitempty"`
+	Time                string    `json:"Time,omitempty"` // When staged settings were last applied
+	Messages            []Message `json:"Messages,omitempty"`
+}
+
+// SettingsApplyTime is the @Redfish.SettingsApplyTime annotation on a settings object
+type SettingsApplyTime struct {
+	ODataType string `json:"@odata.type"`
+	ApplyTime string `json:"ApplyTime"` // Immediate, OnReset, AtMaintenanceWindowStart, etc.
+}
+
+// NewSettings creates a @Redfish.Settings annotation for the given settings object
+func NewSettings(settingsObject ODataID, applyTimes []string) *Settings {
+	return &Settings{
+		ODataType:           "#Settings.v1_3_5.Settings",
+		SettingsObject:      Link{ODataID: settingsObject},
+		SupportedApplyTimes: applyTimes,
+	}
+}
+
+// NewSettingsApplyTime creates a @Redfish.SettingsApplyTime annotation
+func NewSettingsApplyTime(applyTime string) *SettingsApplyTime {
+	return &SettingsApplyTime{
+		ODataType: "#Settings.v1_3_5.PreferredApplyTime",
+		ApplyTime: applyTime,
+	}
+}
+
+// MaintenanceWindow is the @Redfish.MaintenanceWindow annotation. Operations
+// requested with the AtMaintenanceWindowStart apply time run when it opens.
+type MaintenanceWindow struct {
+	ODataType                          string `json:"@odata.type,omitempty"`
+	MaintenanceWindowStartTime         string `json:"MaintenanceWindowStartTime"` // RFC 3339
+	MaintenanceWindowDurationInSeconds int    `json:"MaintenanceWindowDurationInSeconds"`
+}
@@ -1,11 +1,17 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // TaskService represents the TaskService resource
 type TaskService struct {
 	Resource
-	ServiceEnabled                  bool             `json:"ServiceEnabled,omitempty"`
+	ServiceEnabled                  bool             `json:"ServiceEnabled"`
 	CompletedTaskOverWritePolicy    string           `json:"CompletedTaskOverWritePolicy,omitempty"`
 	DateTime                        string           `json:"DateTime,omitempty"`
 	LifeCycleEventOnTaskStateChange bool             `json:"LifeCycleEventOnTaskStateChange,omitempty"`
@@ -31,7 +37,7 @@ func NewTaskService() *TaskService {
 		},
 		ServiceEnabled:                  true,
 		CompletedTaskOverWritePolicy:    "Manual",
-		DateTime:                        time.Now().Format(time.RFC3339),
+		DateTime:                        now().Format(time.RFC3339),
 		LifeCycleEventOnTaskStateChange: true,
 		TaskAutoDeleteTimeoutMinutes:    60,
 		Status: Status{
@@ -51,7 +57,7 @@ type Task struct {
 	TaskStatus        string        `json:"TaskStatus,omitempty"`
 	StartTime         string        `json:"StartTime,omitempty"`
 	EndTime           string        `json:"EndTime,omitempty"`
-	PercentComplete   int           `json:"PercentComplete,omitempty"`
+	PercentComplete   int           `json:"PercentComplete"`
 	TaskMonitor       string        `json:"TaskMonitor,omitempty"`
 	Messages          []Message     `json:"Messages,omitempty"`
 	Payload           *TaskPayload  `json:"Payload,omitempty"`
@@ -59,6 +65,10 @@ type Task struct {
 	EstimatedDuration string        `json:"EstimatedDuration,omitempty"`
 	SubTasks          *TaskSubTasks `json:"SubTasks,omitempty"`
 	Links             TaskLinks     `json:"Links,omitempty"`
+
+	version  uint64    // incremented by every update
+	created  time.Time // when the task was created, for ordering tasks
+	subTasks []*Task   // the steps of a composite task, in order
 }
 
 // TaskPayload represents the payload information for a task
@@ -82,18 +92,19 @@ type TaskLinks struct {
 
 // NewTask creates a new Task instance
 func NewTask(id string, operation string, targetUri string) *Task {
-	now := time.Now().Format(time.RFC3339)
+	created := now()
+	started := created.Format(time.RFC3339)
 	return &Task{
 		Resource: Resource{
 			ODataContext: "/redfish/v1/$metadata#Task.Task",
-			ODataID:      ODataID("/redfish/v1/TaskService/Tasks/" + id),
+			ODataID:      NewODataID("/redfish/v1/TaskService/Tasks", id),
 			ODataType:    "#Task.v1_7_4.Task",
 			ID:           id,
 			Name:         "Task " + id,
 		},
 		TaskState:       "New",
 		TaskStatus:      "OK",
-		StartTime:       now,
+		StartTime:       started,
 		PercentComplete: 0,
 		TaskMonitor:     "/redfish/v1/TaskService/Tasks/" + id + "/Monitor",
 		Messages:        []Message{},
@@ -105,34 +116,185 @@ func NewTask(id string, operation string, targetUri string) *Task {
 		Links: TaskLinks{
 			CreatedResources: []ODataID{},
 		},
+		created: created,
 	}
 }
 
-// UpdateTaskState updates the task state and related properties
+// UpdateTaskState updates the task state and related properties. Only a
+// task that completed is 100% done; one that ends any other way keeps the
+// progress it had made.
 func (t *Task) UpdateTaskState(newState string) {
 	t.TaskState = newState
+	t.version++
 
 	switch newState {
 	case "Running":
 		if t.StartTime == "" {
-			t.StartTime = time.Now().Format(time.RFC3339)
+			t.StartTime = now().Format(time.RFC3339)
 		}
-	case "Completed", "Cancelled", "Exception":
-		t.EndTime = time.Now().Format(time.RFC3339)
+	case "Completed", "Cancelled", "Exception", "Killed":
+		t.EndTime = now().Format(time.RFC3339)
 		if newState == "Completed" {
 			t.PercentComplete = 100
+			break
+		}
+		// Steps that had not finished end with the task
+		for _, sub := range t.subTasks {
+			if !sub.IsFinished() {
+				sub.UpdateTaskState(newState)
+			}
 		}
 	}
+	t.updateTaskStatus()
 }
 
 // AddMessage adds a message to the task
 func (t *Task) AddMessage(message Message) {
 	t.Messages = append(t.Messages, message)
+	t.version++
+	t.updateTaskStatus()
 }
 
-// SetPercentComplete sets the completion percentage
+// taskStatusRank orders the TaskStatus health values from best to worst
+var taskStatusRank = map[string]int{"OK": 0, "Warning": 1, "Critical": 2}
+
+// updateTaskStatus sets TaskStatus to the worst health the task reports:
+// that of its most severe message, or of how it ended. A task that failed
+// (Exception) or was killed is Critical, and one that was cancelled is at
+// least Warning, so clients can tell success from failure.
+func (t *Task) updateTaskStatus() {
+	status := "OK"
+	worsen := func(health string) {
+		if taskStatusRank[health] > taskStatusRank[status] {
+			status = health
+		}
+	}
+	for _, message := range t.Messages {
+		worsen(message.Severity)
+	}
+	switch t.TaskState {
+	case "Exception", "Killed":
+		worsen("Critical")
+	case "Cancelled":
+		worsen("Warning")
+	}
+	t.TaskStatus = status
+}
+
+// SetPercentComplete sets the completion percentage, clamped to 0-100.
+// Progress never goes backwards: a lower percentage is ignored, except 0,
+// which resets a task that is starting over.
 func (t *Task) SetPercentComplete(percent int) {
-	if percent >= 0 && percent <= 100 {
-		t.PercentComplete = percent
+	percent = min(max(percent, 0), 100)
+	if percent < t.PercentComplete && percent != 0 {
+		return
+	}
+	t.PercentComplete = percent
+	t.version++
+}
+
+// SetEstimatedDuration records how long the task is expected to run
+func (t *Task) SetEstimatedDuration(d time.Duration) {
+	t.EstimatedDuration = FormatDuration(d)
+	t.version++
+}
+
+// FormatDuration formats d as an ISO 8601 duration, such as PT3S or PT1M30S
+func FormatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if hours := d / time.Hour; hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+		d -= minutes * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+// Version returns the task's version, which changes with every update
+func (t *Task) Version() uint64 {
+	return t.version
+}
+
+// Created returns when the task was created. Unlike StartTime it is precise
+// enough to order tasks created within the same second.
+func (t *Task) Created() time.Time {
+	return t.created
+}
+
+// IsFinished reports whether the task has reached a terminal state
+func (t *Task) IsFinished() bool {
+	switch t.TaskState {
+	case "Completed", "Cancelled", "Exception", "Killed":
+		return true
+	}
+	return false
+}
+
+// Snapshot returns a copy of the task that later updates do not affect
+func (t *Task) Snapshot() *Task {
+	snapshot := *t
+	snapshot.Messages = slices.Clone(t.Messages)
+	snapshot.subTasks = make([]*Task, len(t.subTasks))
+	for i, sub := range t.subTasks {
+		snapshot.subTasks[i] = sub.Snapshot()
+	}
+	return &snapshot
+}
+
+// AddSubTask adds a step of a composite task as a sub-task, served under
+// the task's SubTasks collection, and links the collection from the task
+func (t *Task) AddSubTask(name string) *Task {
+	id := strconv.Itoa(len(t.subTasks))
+	sub := NewTask(id, "", "")
+	sub.ODataID = NewODataID(string(t.ODataID), "SubTasks", id)
+	sub.Name = name
+	sub.TaskMonitor = ""
+	sub.Payload = nil
+
+	t.subTasks = append(t.subTasks, sub)
+	t.SubTasks = &TaskSubTasks{ODataID: string(NewODataID(string(t.ODataID), "SubTasks"))}
+	t.version++
+	return sub
+}
+
+// SubTaskMembers returns the task's sub-tasks, in the order they run
+func (t *Task) SubTaskMembers() []*Task {
+	return t.subTasks
+}
+
+// SubTask returns the sub-task with the given Id
+func (t *Task) SubTask(id string) (*Task, bool) {
+	for _, sub := range t.subTasks {
+		if sub.ID == id {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+// UpdateSubTaskState moves a sub-task to a new state. The task's
+// PercentComplete follows its sub-tasks: it is their average progress.
+func (t *Task) UpdateSubTaskState(id string, newState string) {
+	sub, ok := t.SubTask(id)
+	if !ok {
+		return
+	}
+	sub.UpdateTaskState(newState)
+
+	total := 0
+	for _, sub := range t.subTasks {
+		total += sub.PercentComplete
 	}
+	t.SetPercentComplete(total / len(t.subTasks))
+	t.version++
 }
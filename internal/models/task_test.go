@@ -0,0 +1,135 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/clock"
+)
+
+func TestTaskProgressNeverRegresses(t *testing.T) {
+	task := NewTask("1", "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+
+	for _, step := range []struct {
+		percent, expected int
+	}{
+		{40, 40},
+		{20, 40},   // regressions are ignored
+		{150, 100}, // out of range values are clamped
+		{-5, 0},    // a negative value clamps to 0, which restarts the task
+		{30, 30},
+	} {
+		task.SetPercentComplete(step.percent)
+		if task.PercentComplete != step.expected {
+			t.Errorf("SetPercentComplete(%d): expected %d, got %d", step.percent, step.expected, task.PercentComplete)
+		}
+	}
+}
+
+func TestTaskTerminalStatesKeepProgress(t *testing.T) {
+	for state, expected := range map[string]int{
+		"Completed": 100,
+		"Exception": 60,
+		"Cancelled": 60,
+		"Killed":    60,
+	} {
+		task := NewTask("1", "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+		task.UpdateTaskState("Running")
+		task.SetPercentComplete(60)
+		task.UpdateTaskState(state)
+		if task.PercentComplete != expected {
+			t.Errorf("%s: expected PercentComplete %d, got %d", state, expected, task.PercentComplete)
+		}
+		if task.EndTime == "" {
+			t.Errorf("%s: expected an EndTime", state)
+		}
+	}
+}
+
+func TestTaskStatusFollowsMessagesAndState(t *testing.T) {
+	task := NewTask("1", "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+	task.UpdateTaskState("Running")
+	if task.TaskStatus != "OK" {
+		t.Errorf("Expected a running task to be OK, got %s", task.TaskStatus)
+	}
+
+	task.AddMessage(Message{MessageID: "Base.1.0.GeneralError", Severity: "Warning"})
+	if task.TaskStatus != "Warning" {
+		t.Errorf("Expected a Warning message to make the task Warning, got %s", task.TaskStatus)
+	}
+
+	task.AddMessage(Message{MessageID: "Base.1.0.InternalError", Severity: "Critical"})
+	task.UpdateTaskState("Exception")
+	if task.TaskStatus != "Critical" {
+		t.Errorf("Expected an Exception with a Critical message to be Critical, got %s", task.TaskStatus)
+	}
+
+	// A failure is Critical even when no message says so, and a cancelled
+	// task is at least Warning
+	for state, expected := range map[string]string{"Exception": "Critical", "Killed": "Critical", "Cancelled": "Warning", "Completed": "OK"} {
+		task := NewTask("1", "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+		task.AddMessage(Message{MessageID: "Base.1.0.Success", Severity: "OK"})
+		task.UpdateTaskState(state)
+		if task.TaskStatus != expected {
+			t.Errorf("%s: expected TaskStatus %s, got %s", state, expected, task.TaskStatus)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                           "PT0S",
+		3 * time.Second:             "PT3S",
+		1500 * time.Millisecond:     "PT1.5S",
+		90 * time.Second:            "PT1M30S",
+		2*time.Hour + 5*time.Second: "PT2H5S",
+	} {
+		if got := FormatDuration(d); got != expected {
+			t.Errorf("FormatDuration(%v) = %q, expected %q", d, got, expected)
+		}
+	}
+}
+
+func TestSubTaskProgress(t *testing.T) {
+	task := NewTask("1", "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+	off := task.AddSubTask("Power off")
+	on := task.AddSubTask("Power on")
+	if task.SubTasks == nil || task.SubTasks.ODataID != "/redfish/v1/TaskService/Tasks/1/SubTasks" {
+		t.Fatalf("Expected the task to link its SubTasks, got %+v", task.SubTasks)
+	}
+	if on.ODataID != "/redfish/v1/TaskService/Tasks/1/SubTasks/1" {
+		t.Errorf("Expected the second sub-task under SubTasks, got %s", on.ODataID)
+	}
+
+	task.UpdateSubTaskState(off.ID, "Completed")
+	if task.PercentComplete != 50 {
+		t.Errorf("Expected 50%% with one of two sub-tasks done, got %d", task.PercentComplete)
+	}
+
+	// Cancelling the task ends the sub-tasks it had not finished
+	task.UpdateTaskState("Cancelled")
+	if off.TaskState != "Completed" || on.TaskState != "Cancelled" {
+		t.Errorf("Expected the unfinished sub-task cancelled, got %s and %s", off.TaskState, on.TaskState)
+	}
+	if task.PercentComplete != 50 {
+		t.Errorf("Expected the cancelled task to keep 50%%, got %d", task.PercentComplete)
+	}
+}
+
+func TestTaskTimestampsFollowClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	SetClock(fake)
+	t.Cleanup(func() { SetClock(clock.Real) })
+
+	task := NewTask("1", "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+	task.UpdateTaskState("Running")
+	fake.Advance(90 * time.Second)
+	task.UpdateTaskState("Completed")
+
+	if task.StartTime != "2024-01-01T12:00:00Z" || task.EndTime != "2024-01-01T12:01:30Z" {
+		t.Errorf("Expected the task stamped by the fake clock, got %s to %s", task.StartTime, task.EndTime)
+	}
+	if !task.Created().Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the task created at the fake time, got %v", task.Created())
+	}
+}
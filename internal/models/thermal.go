@@ -0,0 +1,176 @@
+package models
+
+import "strconv"
+
+// Thermal represents the legacy Thermal resource of a chassis, superseded by
+// ThermalSubsystem
+type Thermal struct {
+	Resource
+	Fans         []FanEntry         `json:"Fans"`
+	Temperatures []TemperatureEntry `json:"Temperatures"`
+}
+
+// FanEntry is a fan listed in the legacy Thermal resource
+type FanEntry struct {
+	ODataID      string `json:"@odata.id"`
+	MemberID     string `json:"MemberId"`
+	Name         string `json:"Name"`
+	Reading      int    `json:"Reading"`
+	ReadingUnits string `json:"ReadingUnits"`
+	Status       Status `json:"Status"`
+}
+
+// TemperatureEntry is a temperature sensor listed in the legacy Thermal
+// resource
+type TemperatureEntry struct {
+	ODataID                string  `json:"@odata.id"`
+	MemberID               string  `json:"MemberId"`
+	Name                   string  `json:"Name"`
+	ReadingCelsius         float64 `json:"ReadingCelsius"`
+	UpperThresholdCritical float64 `json:"UpperThresholdCritical"`
+	Status                 Status  `json:"Status"`
+}
+
+// NewThermal creates the legacy Thermal resource of a chassis from its
+// environment
+func NewThermal(chassisID string, environment *ChassisEnvironment) *Thermal {
+	id := NewODataID("/redfish/v1/Chassis", chassisID, "Thermal")
+	thermal := &Thermal{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Thermal.Thermal",
+			ODataID:      id,
+			ODataType:    "#Thermal.v1_7_1.Thermal",
+			ID:           "Thermal",
+			Name:         "Thermal",
+		},
+		Fans:         make([]FanEntry, 0, len(environment.Fans)),
+		Temperatures: make([]TemperatureEntry, 0, len(environment.Temperatures)),
+	}
+	for i, fan := range environment.Fans {
+		thermal.Fans = append(thermal.Fans, FanEntry{
+			ODataID:      string(id) + "#/Fans/" + strconv.Itoa(i),
+			MemberID:     fan.ID,
+			Name:         fan.Name,
+			Reading:      fan.SpeedRPM,
+			ReadingUnits: "RPM",
+			Status:       Status{State: "Enabled", Health: "OK"},
+		})
+	}
+	for i, temperature := range environment.Temperatures {
+		thermal.Temperatures = append(thermal.Temperatures, TemperatureEntry{
+			ODataID:                string(id) + "#/Temperatures/" + strconv.Itoa(i),
+			MemberID:               temperature.ID,
+			Name:                   temperature.Name,
+			ReadingCelsius:         temperature.Celsius,
+			UpperThresholdCritical: temperature.UpperCritical,
+			Status:                 Status{State: "Enabled", Health: "OK"},
+		})
+	}
+	return thermal
+}
+
+// ThermalSubsystem represents the cooling of a chassis
+type ThermalSubsystem struct {
+	Resource
+	Status         Status `json:"Status"`
+	Fans           Link   `json:"Fans"`
+	ThermalMetrics Link   `json:"ThermalMetrics"`
+}
+
+// NewThermalSubsystem creates the ThermalSubsystem of a chassis
+func NewThermalSubsystem(chassisID string) *ThermalSubsystem {
+	return &ThermalSubsystem{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#ThermalSubsystem.ThermalSubsystem",
+			ODataID:      NewODataID("/redfish/v1/Chassis", chassisID, "ThermalSubsystem"),
+			ODataType:    "#ThermalSubsystem.v1_0_0.ThermalSubsystem",
+			ID:           "ThermalSubsystem",
+			Name:         "Thermal Subsystem",
+		},
+		Status:         Status{State: "Enabled", Health: "OK"},
+		Fans:           Link{ODataID: NewODataID("/redfish/v1/Chassis", chassisID, "ThermalSubsystem", "Fans")},
+		ThermalMetrics: Link{ODataID: NewODataID("/redfish/v1/Chassis", chassisID, "ThermalSubsystem", "ThermalMetrics")},
+	}
+}
+
+// Fan represents a fan in a ThermalSubsystem
+type Fan struct {
+	Resource
+	SpeedPercent SensorSpeedExcerpt `json:"SpeedPercent"`
+	Status       Status             `json:"Status"`
+}
+
+// SensorSpeedExcerpt is a fan speed reading, in percent of the maximum
+// speed, with the speed in RPM
+type SensorSpeedExcerpt struct {
+	Reading  float64 `json:"Reading"`
+	SpeedRPM int     `json:"SpeedRPM"`
+}
+
+// NewFan creates a Fan of a chassis from its reading
+func NewFan(chassisID string, reading FanReading) *Fan {
+	return &Fan{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#Fan.Fan",
+			ODataID:      NewODataID("/redfish/v1/Chassis", chassisID, "ThermalSubsystem", "Fans", reading.ID),
+			ODataType:    "#Fan.v1_3_0.Fan",
+			ID:           reading.ID,
+			Name:         reading.Name,
+		},
+		SpeedPercent: SensorSpeedExcerpt{Reading: reading.SpeedPercent, SpeedRPM: reading.SpeedRPM},
+		Status:       Status{State: "Enabled", Health: "OK"},
+	}
+}
+
+// FanCollection represents the Fans of a chassis ThermalSubsystem
+type FanCollection struct {
+	Collection
+}
+
+// NewFanCollection creates a FanCollection with the given members
+func NewFanCollection(chassisID string, members []Link) *FanCollection {
+	return &FanCollection{
+		Collection: Collection{
+			ODataContext:      "/redfish/v1/$metadata#FanCollection.FanCollection",
+			ODataID:           NewODataID("/redfish/v1/Chassis", chassisID, "ThermalSubsystem", "Fans"),
+			ODataType:         "#FanCollection.FanCollection",
+			Name:              "Fan Collection",
+			Members:           CollectionMembers(members),
+			MembersODataCount: len(members),
+		},
+	}
+}
+
+// ThermalMetrics represents the temperature readings of a chassis
+type ThermalMetrics struct {
+	Resource
+	TemperatureReadingsCelsius []TemperatureReadingExcerpt `json:"TemperatureReadingsCelsius"`
+}
+
+// TemperatureReadingExcerpt is a temperature reading in ThermalMetrics
+type TemperatureReadingExcerpt struct {
+	DeviceName string  `json:"DeviceName"`
+	Reading    float64 `json:"Reading"`
+}
+
+// NewThermalMetrics creates the ThermalMetrics of a chassis from its
+// environment
+func NewThermalMetrics(chassisID string, environment *ChassisEnvironment) *ThermalMetrics {
+	metrics := &ThermalMetrics{
+		Resource: Resource{
+			ODataContext: "/redfish/v1/$metadata#ThermalMetrics.ThermalMetrics",
+			ODataID:      NewODataID("/redfish/v1/Chassis", chassisID, "ThermalSubsystem", "ThermalMetrics"),
+			ODataType:    "#ThermalMetrics.v1_0_0.ThermalMetrics",
+			ID:           "ThermalMetrics",
+			Name:         "Chassis Thermal Metrics",
+		},
+		TemperatureReadingsCelsius: make([]TemperatureReadingExcerpt, 0, len(environment.Temperatures)),
+	}
+	for _, temperature := range environment.Temperatures {
+		metrics.TemperatureReadingsCelsius = append(metrics.TemperatureReadingsCelsius, TemperatureReadingExcerpt{
+			DeviceName: temperature.Name,
+			Reading:    temperature.Celsius,
+		})
+	}
+	return metrics
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// systemResetActionInfo is the ActionInfo of a system's Reset action, which
+// both advertises and validates its parameters
+func systemResetActionInfo(systemID string) *models.ActionInfo {
+	return models.NewActionInfo(
+		models.ODataID(fmt.Sprintf("/redfish/v1/Systems/%s/Actions/ComputerSystem.Reset", systemID)),
+		"ComputerSystem.Reset", "Computer System Reset",
+		models.ActionParameter{Name: "ResetType", DataType: "String", AllowableValues: systemResetTypes(systemID)},
+	)
+}
+
+// managerResetActionInfo is the ActionInfo of a manager's Reset action
+func managerResetActionInfo(managerID string) *models.ActionInfo {
+	return models.NewActionInfo(
+		models.ODataID(fmt.Sprintf("/redfish/v1/Managers/%s/Actions/Manager.Reset", managerID)),
+		"Manager.Reset", "Manager Reset",
+		models.ActionParameter{Name: "ResetType", DataType: "String", AllowableValues: managerResetTypes(managerID)},
+	)
+}
+
+// decodeActionRequest decodes an action's request body into v and checks
+// its parameters against info. An empty body carries no parameters. It
+// reports false, having sent the error, when the body is malformed or the
+// parameters are not acceptable.
+func decodeActionRequest(w http.ResponseWriter, r *http.Request, info *models.ActionInfo, v interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		sendRedfishError(w, "MalformedJSON", "Failed to read request body", http.StatusBadRequest)
+		return false
+	}
+	parameters := map[string]interface{}{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if json.Unmarshal(body, &parameters) != nil || json.Unmarshal(body, v) != nil {
+			sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+			return false
+		}
+	}
+
+	var missing *models.MissingParameterError
+	var enumErr *models.EnumError
+	switch err := info.Validate(parameters); {
+	case errors.As(err, &missing):
+		sendRedfishError(w, "ActionParameterMissing", err.Error(), http.StatusBadRequest)
+		return false
+	case errors.As(err, &enumErr):
+		sendRedfishError(w, "ActionParameterValueNotInList", err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// operationApplyTimes are the @Redfish.OperationApplyTime values actions accept
+var operationApplyTimes = []string{"Immediate", "OnReset", "AtMaintenanceWindowStart"}
+
+// Operations requested with an OnReset apply time, keyed by the @odata.id of
+// the resource whose next reset releases them, and the timers of operations
+// waiting for a maintenance window, keyed by task Id
+var (
+	deferredMutex      sync.Mutex
+	deferredOperations = make(map[string][]func())
+	scheduledWindows   = make(map[string]*time.Timer)
+)
+
+// operationSchedule is when an action's operation should run
+type operationSchedule struct {
+	applyTime   string
+	windowStart time.Time // AtMaintenanceWindowStart only
+}
+
+// parseOperationSchedule validates a requested @Redfish.OperationApplyTime
+// and, for AtMaintenanceWindowStart, the @Redfish.MaintenanceWindow it runs
+// in. An absent apply time means Immediate. A window that has already closed
+// is rejected.
+func parseOperationSchedule(applyTime string, window *models.MaintenanceWindow) (operationSchedule, error) {
+	if applyTime == "" {
+		applyTime = "Immediate"
+	}
+	if !slices.Contains(operationApplyTimes, applyTime) {
+		return operationSchedule{}, fmt.Errorf("@Redfish.OperationApplyTime %s is not supported; allowed values: %s", applyTime, strings.Join(operationApplyTimes, ", "))
+	}
+
+	schedule := operationSchedule{applyTime: applyTime}
+	if applyTime != "AtMaintenanceWindowStart" {
+		return schedule, nil
+	}
+	if window == nil {
+		return operationSchedule{}, fmt.Errorf("@Redfish.MaintenanceWindow is required with the AtMaintenanceWindowStart apply time")
+	}
+	start, err := time.Parse(time.RFC3339, window.MaintenanceWindowStartTime)
+	if err != nil {
+		return operationSchedule{}, fmt.Errorf("MaintenanceWindowStartTime %q is not an RFC 3339 date-time", window.MaintenanceWindowStartTime)
+	}
+	if window.MaintenanceWindowDurationInSeconds <= 0 {
+		return operationSchedule{}, fmt.Errorf("MaintenanceWindowDurationInSeconds must be positive")
+	}
+	end := start.Add(time.Duration(window.MaintenanceWindowDurationInSeconds) * time.Second)
+	if !end.After(time.Now()) {
+		return operationSchedule{}, fmt.Errorf("the maintenance window ended at %s", end.Format(time.RFC3339))
+	}
+	schedule.windowStart = start
+	return schedule, nil
+}
+
+// scheduleOperation starts an action's task now, or parks it in the Pending
+// state until resourceURI is next reset (OnReset) or until its maintenance
+// window opens (AtMaintenanceWindowStart). start must not block.
+func scheduleOperation(task *models.Task, resourceURI string, schedule operationSchedule, start func()) {
+	var message string
+	switch schedule.applyTime {
+	case "OnReset":
+		message = fmt.Sprintf("The operation is deferred until %s is reset", resourceURI)
+	case "AtMaintenanceWindowStart":
+		message = fmt.Sprintf("The operation is scheduled for the maintenance window starting at %s", schedule.windowStart.Format(time.RFC3339))
+	default:
+		start()
+		return
+	}
+
+	tasksMutex.Lock()
+	task.UpdateTaskState("Pending")
+	task.AddMessage(models.Message{
+		MessageID:  "Base.1.0.Success",
+		Message:    message,
+		Severity:   "OK",
+		Resolution: "None",
+	})
+	tasksMutex.Unlock()
+
+	deferredMutex.Lock()
+	defer deferredMutex.Unlock()
+	if schedule.applyTime == "OnReset" {
+		deferredOperations[resourceURI] = append(deferredOperations[resourceURI], start)
+		return
+	}
+	scheduledWindows[task.ID] = time.AfterFunc(time.Until(schedule.windowStart), func() {
+		deferredMutex.Lock()
+		delete(scheduledWindows, task.ID)
+		deferredMutex.Unlock()
+		start()
+	})
+}
+
+// releaseDeferredOperations starts the operations waiting on a reset of
+// resourceURI. Operations deferred while these run wait for the next reset.
+func releaseDeferredOperations(resourceURI string) {
+	deferredMutex.Lock()
+	pending := deferredOperations[resourceURI]
+	delete(deferredOperations, resourceURI)
+	deferredMutex.Unlock()
+
+	for _, start := range pending {
+		start()
+	}
+}
+
+// cancelScheduledOperation stops the operation of a task still waiting for
+// its maintenance window, reporting whether there was one
+func cancelScheduledOperation(taskID string) bool {
+	deferredMutex.Lock()
+	defer deferredMutex.Unlock()
+
+	timer, ok := scheduledWindows[taskID]
+	if ok {
+		timer.Stop()
+		delete(scheduledWindows, taskID)
+	}
+	return ok
+}
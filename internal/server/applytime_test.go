@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// taskState returns the current state of a task
+func taskState(id string) string {
+	tasksMutex.RLock()
+	defer tasksMutex.RUnlock()
+	return tasks[id].TaskState
+}
+
+func TestOnResetApplyTimeDefersReset(t *testing.T) {
+	previous := systemResetDuration
+	systemResetDuration = 10 * time.Millisecond
+	defer func() { systemResetDuration = previous }()
+	systemStore.Put("applytime-test", models.NewComputerSystem("applytime-test"))
+	t.Cleanup(func() { systemStore.Delete("applytime-test") })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	reset := func(body string) (int, string) {
+		req := httptest.NewRequest("POST", "/redfish/v1/Systems/applytime-test/Actions/ComputerSystem.Reset", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var task struct{ Id string }
+		json.Unmarshal(w.Body.Bytes(), &task)
+		return w.Code, task.Id
+	}
+
+	if code, _ := reset(`{"ResetType": "ForceOff", "@Redfish.OperationApplyTime": "AtSomePoint"}`); code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400 for an unsupported apply time, got %d", code)
+	}
+
+	code, deferred := reset(`{"ResetType": "ForceOff", "@Redfish.OperationApplyTime": "OnReset"}`)
+	if code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", code)
+	}
+
+	// Nothing runs until the system is reset
+	time.Sleep(5 * systemResetDuration)
+	if state := taskState(deferred); state != "Pending" {
+		t.Fatalf("Expected the deferred reset to stay Pending, got %s", state)
+	}
+
+	if code, _ := reset(`{"ResetType": "ForceRestart"}`); code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", code)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for taskState(deferred) != "Completed" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the deferred reset to run after the system reset, still %s", taskState(deferred))
+		}
+		time.Sleep(systemResetDuration)
+	}
+}
+
+func TestMaintenanceWindowSchedulesReset(t *testing.T) {
+	previous := managerResetDuration
+	managerResetDuration = 10 * time.Millisecond
+	defer func() { managerResetDuration = previous }()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	reset := func(start time.Time, duration int) (int, string) {
+		body := fmt.Sprintf(`{"ResetType": "GracefulRestart", "@Redfish.OperationApplyTime": "AtMaintenanceWindowStart",
+			"@Redfish.MaintenanceWindow": {"MaintenanceWindowStartTime": %q, "MaintenanceWindowDurationInSeconds": %d}}`,
+			start.Format(time.RFC3339Nano), duration)
+		req := httptest.NewRequest("POST", "/redfish/v1/Managers/1/Actions/Manager.Reset", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var task struct{ Id string }
+		json.Unmarshal(w.Body.Bytes(), &task)
+		return w.Code, task.Id
+	}
+
+	if code, _ := reset(time.Now().Add(-time.Hour), 60); code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400 for a window that has closed, got %d", code)
+	}
+
+	opens := time.Now().Add(300 * time.Millisecond)
+	code, scheduled := reset(opens, 60)
+	if code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", code)
+	}
+
+	// The operation waits for the window
+	time.Sleep(100 * time.Millisecond)
+	if state := taskState(scheduled); state != "Pending" {
+		t.Fatalf("Expected the reset to be Pending before the window opens, got %s", state)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for taskState(scheduled) != "Completed" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the reset to run once the window opened, still %s", taskState(scheduled))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tasksMutex.RLock()
+	finished := tasks[scheduled].EndTime
+	tasksMutex.RUnlock()
+	if ended, _ := time.Parse(time.RFC3339, finished); ended.Before(opens.Truncate(time.Second)) {
+		t.Errorf("Expected the reset to finish after the window opened at %s, finished at %s", opens, finished)
+	}
+}
+
+func TestCancelScheduledOperation(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	body := fmt.Sprintf(`{"@Redfish.OperationApplyTime": "AtMaintenanceWindowStart",
+		"@Redfish.MaintenanceWindow": {"MaintenanceWindowStartTime": %q, "MaintenanceWindowDurationInSeconds": 3600}}`,
+		time.Now().Add(time.Hour).Format(time.RFC3339))
+	req := httptest.NewRequest("POST", "/redfish/v1/Managers/1/Actions/Manager.Reset", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	location := w.Header().Get("Location")
+
+	req = httptest.NewRequest("DELETE", location, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if cancelScheduledOperation(strings.TrimPrefix(location, "/redfish/v1/TaskService/Tasks/")) {
+		t.Error("Expected deleting the task to cancel its scheduled operation")
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// basePathMiddleware publishes the service under a sub-path such as /bmc1 for
+// reverse-proxy deployments. The prefix is stripped from incoming request
+// paths before routing, and prepended to the /redfish links handlers generate
+// in response bodies and in the Location and Link headers. Requests without
+// the prefix are routed unchanged, so a proxy that strips it itself still works.
+func basePathMiddleware(prefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path, ok := strings.CutPrefix(r.URL.Path, prefix); ok && (path == "" || path[0] == '/') {
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = path
+			r2.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, prefix)
+			r = r2
+		}
+		next.ServeHTTP(&basePathWriter{ResponseWriter: w, prefix: prefix}, r)
+	})
+}
+
+// basePathWriter rewrites the links in a response to carry the base path
+type basePathWriter struct {
+	http.ResponseWriter
+	prefix      string
+	wroteHeader bool
+}
+
+func (bw *basePathWriter) WriteHeader(code int) {
+	if !bw.wroteHeader {
+		bw.wroteHeader = true
+		header := bw.Header()
+		// Prefixing the links changes the body's length
+		header.Del("Content-Length")
+		if location := header.Get("Location"); location != "" {
+			header.Set("Location", strings.Replace(location, "/redfish", bw.prefix+"/redfish", 1))
+		}
+		if link := header.Get("Link"); link != "" {
+			header.Set("Link", strings.ReplaceAll(link, "</redfish", "<"+bw.prefix+"/redfish"))
+		}
+	}
+	bw.ResponseWriter.WriteHeader(code)
+}
+
+// Write prefixes the /redfish links in the body. Handlers encode each
+// resource with a single write, so a link is never split across calls.
+func (bw *basePathWriter) Write(b []byte) (int, error) {
+	if !bw.wroteHeader {
+		bw.WriteHeader(http.StatusOK)
+	}
+	if _, err := bw.ResponseWriter.Write(prefixLinks(b, bw.prefix)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// linkProperties are the properties, besides those isLinkProperty matches
+// by name, whose values are links to other resources: the annotations and
+// action targets, and the properties the models declare as ODataID
+var linkProperties = map[string]bool{
+	"@odata.id":           true,
+	"@odata.context":      true,
+	"@Redfish.ActionInfo": true,
+	"target":              true,
+	"TaskMonitor":         true,
+	"Location":            true,
+	"url":                 true,
+
+	"ComputerSystems":    true,
+	"ContainedBy":        true,
+	"Contains":           true,
+	"Controllers":        true,
+	"CooledBy":           true,
+	"CreatedResources":   true,
+	"Drives":             true,
+	"EthernetInterfaces": true,
+	"LogServices":        true,
+	"ManagedBy":          true,
+	"Memory":             true,
+	"NetworkAdapters":    true,
+	"NetworkInterfaces":  true,
+	"OriginOfCondition":  true,
+	"OriginResources":    true,
+	"PCIeDevices":        true,
+	"Power":              true,
+	"PoweredBy":          true,
+	"Processors":         true,
+	"Role":               true,
+	"Services":           true,
+	"StorageControllers": true,
+	"Subscriptions":      true,
+	"Thermal":            true,
+}
+
+// isLinkProperty reports whether the JSON property key holds a link, or an
+// array of links
+func isLinkProperty(key string) bool {
+	return linkProperties[key] || strings.HasSuffix(key, "@odata.nextLink") || strings.HasSuffix(key, "Uri")
+}
+
+// prefixLinks returns b with prefix inserted before the /redfish paths held
+// by link properties. Other strings, such as an AssetTag a client set to
+// "/redfish/x", are left as they are. b need not be a single JSON document:
+// text around the JSON, like the field names of a server-sent event, is
+// copied unchanged.
+func prefixLinks(b []byte, prefix string) []byte {
+	type container struct {
+		object    bool
+		key       string // the property being read, in an object
+		expectKey bool   // a string here is a property name, in an object
+		links     bool   // the array is the value of a link property
+	}
+	var stack []container
+	out := make([]byte, 0, len(b))
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		var top *container
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+		switch {
+		case c == '{':
+			stack = append(stack, container{object: true, expectKey: true})
+		case c == '[':
+			stack = append(stack, container{links: top != nil && top.object && isLinkProperty(top.key)})
+		case c == '}' || c == ']':
+			if top != nil {
+				stack = stack[:len(stack)-1]
+			}
+		case c == ',' && top != nil && top.object:
+			top.expectKey = true
+		case c == ':' && top != nil && top.object:
+			top.expectKey = false
+		case c == '"':
+			end := i + 1
+			for end < len(b) && b[end] != '"' {
+				if b[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(b) {
+				break
+			}
+			value := b[i+1 : end]
+			switch {
+			case top == nil:
+			case top.object && top.expectKey:
+				top.key = string(value)
+			case bytes.HasPrefix(value, []byte("/redfish")) && (top.object && isLinkProperty(top.key) || top.links):
+				out = append(out, '"')
+				out = append(out, prefix...)
+				out = append(out, b[i+1:end+1]...)
+				i = end
+				continue
+			}
+			out = append(out, b[i:end+1]...)
+			i = end
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// Flush forwards to the underlying writer so streaming handlers keep working
+func (bw *basePathWriter) Flush() {
+	if flusher, ok := bw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (bw *basePathWriter) Unwrap() http.ResponseWriter {
+	return bw.ResponseWriter
+}
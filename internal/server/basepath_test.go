@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestBasePath(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0", BasePath: "/bmc1"},
+	}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/bmc1/redfish/v1/", nil)
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	// The links grow by the prefix, so the handler's Content-Length is dropped
+	if length := w.Header().Get("Content-Length"); length != "" && length != strconv.Itoa(w.Body.Len()) {
+		t.Errorf("Expected Content-Length to match the rewritten body of %d bytes, got %s", w.Body.Len(), length)
+	}
+
+	var root models.ServiceRoot
+	if err := json.NewDecoder(w.Body).Decode(&root); err != nil {
+		t.Fatalf("Failed to decode service root: %v", err)
+	}
+	if root.ODataID != "/bmc1/redfish/v1/" || root.Systems.ODataID != "/bmc1/redfish/v1/Systems" || root.Links.Sessions.ODataID != "/bmc1/redfish/v1/SessionService/Sessions" {
+		t.Errorf("Expected links to carry the base path, got %+v", root)
+	}
+	if !strings.HasPrefix(string(root.ODataContext), "/bmc1/redfish/v1/$metadata") {
+		t.Errorf("Expected @odata.context to carry the base path, got %s", root.ODataContext)
+	}
+	if link := w.Header().Get("Link"); link != "</bmc1/redfish/v1/$metadata>; rel=describedby" {
+		t.Errorf("Expected Link header to carry the base path, got %s", link)
+	}
+
+	// A login through the prefixed path resolves and points at the prefixed session
+	req = httptest.NewRequest("POST", "/bmc1/redfish/v1/SessionService/Sessions", strings.NewReader(`{"UserName": "admin", "Password": "password"}`))
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	token := w.Header().Get("X-Auth-Token")
+	if location := w.Header().Get("Location"); !strings.HasSuffix(location, "/bmc1"+sessionURIOf(t, token)) {
+		t.Errorf("Expected Location to carry the base path, got %s", location)
+	}
+
+	req = httptest.NewRequest("GET", "/bmc1/redfish/v1/Systems/1", nil)
+	req.Header.Set("X-Auth-Token", token)
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var system models.ComputerSystem
+	if err := json.NewDecoder(w.Body).Decode(&system); err != nil {
+		t.Fatalf("Failed to decode system: %v", err)
+	}
+	if system.ODataID != "/bmc1/redfish/v1/Systems/1" {
+		t.Errorf("Expected system @odata.id to carry the base path, got %s", system.ODataID)
+	}
+}
+
+func TestInvalidBasePath(t *testing.T) {
+	for _, basePath := range []string{"bmc1", "/bmc1/"} {
+		cfg := &config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0", BasePath: basePath}}
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected base path %q to be rejected", basePath)
+		}
+	}
+}
+
+func TestBasePathPrefixesEveryLink(t *testing.T) {
+	cfg := &config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0", BasePath: "/bmc1"}}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	// Walk the service from the root, following @odata.id links; a /redfish
+	// path left without the prefix is a link property the rewrite misses
+	visited := map[string]bool{"/bmc1/redfish/v1/": true}
+	queue := []string{"/bmc1/redfish/v1/"}
+	var check func(uri, key string, value interface{})
+	check = func(uri, key string, value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for k, v := range value {
+				check(uri, k, v)
+			}
+		case []interface{}:
+			for _, v := range value {
+				check(uri, key, v)
+			}
+		case string:
+			if strings.HasPrefix(value, "/redfish") {
+				t.Errorf("%s: expected %s to carry the base path, got %s", uri, key, value)
+			}
+			if key == "@odata.id" && strings.HasPrefix(value, "/bmc1/") && !visited[value] {
+				visited[value] = true
+				queue = append(queue, value)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		uri := queue[0]
+		queue = queue[1:]
+		req := httptest.NewRequest("GET", uri, nil)
+		req.SetBasicAuth("admin", "password")
+		w := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		var document interface{}
+		if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &document) != nil {
+			continue
+		}
+		check(uri, "", document)
+	}
+	if len(visited) < 20 {
+		t.Errorf("Expected to walk the service, reached only %d resources", len(visited))
+	}
+}
+
+func TestBasePathLeavesOtherStrings(t *testing.T) {
+	system := models.NewComputerSystem("basepath-strings")
+	system.AssetTag = "/redfish/x"
+	systemStore.Put(system.ID, system)
+	t.Cleanup(func() { systemStore.Delete(system.ID) })
+
+	cfg := &config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0", BasePath: "/bmc1"}}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/bmc1/redfish/v1/Systems/basepath-strings", nil)
+	req.SetBasicAuth("admin", "password")
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var got models.ComputerSystem
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode system: %v", err)
+	}
+	if got.AssetTag != "/redfish/x" {
+		t.Errorf("Expected the AssetTag to be returned as set, got %s", got.AssetTag)
+	}
+	if got.ODataID != "/bmc1/redfish/v1/Systems/basepath-strings" {
+		t.Errorf("Expected the system's own link to carry the base path, got %s", got.ODataID)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/config"
+)
+
+// newJWTValidator builds the bearer token validator from the auth configuration
+func newJWTValidator(cfg config.AuthConfig) (*auth.JWTValidator, error) {
+	validator := &auth.JWTValidator{
+		RoleClaim: cfg.JWTRoleClaim,
+		RoleMap:   cfg.JWTRoleMap,
+		Issuer:    cfg.JWTIssuer,
+		Audience:  cfg.JWTAudience,
+
+		DefaultRole: cfg.DefaultRoleId,
+	}
+
+	if cfg.JWTJWKSURL != "" {
+		validator.Keys = auth.NewJWKS(cfg.JWTJWKSURL, nil)
+		return validator, nil
+	}
+
+	data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+	}
+	key, err := auth.ParsePublicKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT public key %s: %w", cfg.JWTPublicKeyFile, err)
+	}
+	validator.Keys = auth.StaticKey(key)
+	return validator, nil
+}
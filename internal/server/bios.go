@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// biosSettings holds the current and staged BIOS attributes of every system
+var biosSettings = newDeferredSettings(models.DefaultBiosAttributes)
+
+// biosURI returns the @odata.id of a system's Bios resource
+func biosURI(systemID string) string {
+	return fmt.Sprintf("/redfish/v1/Systems/%s/Bios", systemID)
+}
+
+// biosHandler handles a system's Bios resource and its settings object
+func biosHandler(w http.ResponseWriter, r *http.Request, systemID string, subPath []string) {
+	switch {
+	case len(subPath) == 0:
+		w.Header().Set("Allow", "GET")
+		switch r.Method {
+		case "GET":
+			handleGetBios(w, r, systemID)
+		default:
+			methodNotAllowed(w, r)
+		}
+	case len(subPath) == 1 && subPath[0] == "Settings":
+		w.Header().Set("Allow", "GET, PATCH")
+		switch r.Method {
+		case "GET":
+			handleGetBiosSettings(w, r, systemID)
+		case "PATCH":
+			handleUpdateBiosSettings(w, r, systemID)
+		default:
+			methodNotAllowed(w, r)
+		}
+	default:
+		sendResourceNotFound(w, r)
+	}
+}
+
+// handleGetBios returns the BIOS attributes currently in effect
+func handleGetBios(w http.ResponseWriter, r *http.Request, systemID string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	bios := currentBios(systemID)
+	etag := resourceETag(bios)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, bios)
+}
+
+// handleGetBiosSettings returns the BIOS attributes staged for the next reset
+func handleGetBiosSettings(w http.ResponseWriter, r *http.Request, systemID string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	settings := pendingBiosSettings(systemID)
+	etag := resourceETag(settings)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, settings)
+}
+
+// handleUpdateBiosSettings stages BIOS attribute changes (PATCH). The changes
+// take effect the next time the system is reset.
+func handleUpdateBiosSettings(w http.ResponseWriter, r *http.Request, systemID string) {
+	if !requirePrivilege(w, r, "ConfigureComponents") {
+		return
+	}
+
+	var requestBody struct {
+		Attributes        map[string]interface{}    `json:"Attributes"`
+		SettingsApplyTime *models.SettingsApplyTime `json:"@Redfish.SettingsApplyTime"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.SettingsApplyTime != nil && requestBody.SettingsApplyTime.ApplyTime != "OnReset" {
+		sendRedfishError(w, "PropertyValueNotInList", fmt.Sprintf("ApplyTime %s is not supported; supported values: OnReset", requestBody.SettingsApplyTime.ApplyTime), http.StatusBadRequest)
+		return
+	}
+
+	if len(requestBody.Attributes) == 0 {
+		sendRedfishError(w, "PropertyMissing", "The request must include Attributes", http.StatusBadRequest)
+		return
+	}
+
+	known := models.DefaultBiosAttributes()
+	for name, value := range requestBody.Attributes {
+		def, ok := known[name]
+		if !ok {
+			sendRedfishError(w, "PropertyUnknown", fmt.Sprintf("Unknown BIOS attribute: %s", name), http.StatusBadRequest)
+			return
+		}
+		if !sameAttributeType(def, value) {
+			sendRedfishError(w, "PropertyValueTypeError", fmt.Sprintf("Invalid value %v for BIOS attribute %s", value, name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	biosSettings.Stage(biosURI(systemID), requestBody.Attributes)
+
+	sendUpdatedResource(w, pendingBiosSettings(systemID))
+}
+
+// currentBios builds a system's Bios resource from the attributes in effect
+func currentBios(systemID string) *models.Bios {
+	uri := biosURI(systemID)
+	bios := models.NewBios(systemID, biosSettings.Current(uri))
+	if applied, ok := biosSettings.LastApplied(uri); ok {
+		bios.Settings.Time = applied.Format(time.RFC3339)
+	}
+	return bios
+}
+
+// pendingBiosSettings builds a system's Bios settings object from the staged attributes
+func pendingBiosSettings(systemID string) *models.Bios {
+	return models.NewBiosSettings(systemID, biosSettings.Pending(biosURI(systemID)))
+}
+
+// sameAttributeType reports whether value has the JSON type of the attribute's
+// default value
+func sameAttributeType(def, value interface{}) bool {
+	switch def.(type) {
+	case string:
+		_, ok := value.(string)
+		return ok
+	case bool:
+		_, ok := value.(bool)
+		return ok
+	case int, float64:
+		_, ok := value.(float64)
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func getBiosAttributes(t *testing.T, mux *http.ServeMux, path string) map[string]interface{} {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected status 200, got %d", path, w.Code)
+	}
+
+	var body struct {
+		Attributes map[string]interface{} `json:"Attributes"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("GET %s: failed to decode response: %v", path, err)
+	}
+	return body.Attributes
+}
+
+func TestBiosSettingsAppliedOnReset(t *testing.T) {
+	previous := systemResetDuration
+	systemResetDuration = 10 * time.Millisecond
+	defer func() { systemResetDuration = previous }()
+
+	systemStore.Put("bios-test", models.NewComputerSystem("bios-test"))
+	t.Cleanup(func() { systemStore.Delete("bios-test") })
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	// The Bios resource advertises its settings object
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems/bios-test/Bios", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	var bios map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&bios); err != nil {
+		t.Fatalf("Failed to decode Bios: %v", err)
+	}
+	settings, ok := bios["@Redfish.Settings"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Bios is missing @Redfish.Settings: %v", bios)
+	}
+	settingsObject, _ := settings["SettingsObject"].(map[string]interface{})
+	if settingsObject["@odata.id"] != "/redfish/v1/Systems/bios-test/Bios/Settings" {
+		t.Errorf("Unexpected SettingsObject: %v", settings["SettingsObject"])
+	}
+
+	// Stage a change
+	body := `{"Attributes": {"BootMode": "Legacy"}, "@Redfish.SettingsApplyTime": {"ApplyTime": "OnReset"}}`
+	req = httptest.NewRequest("PATCH", "/redfish/v1/Systems/bios-test/Bios/Settings", strings.NewReader(body))
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 staging settings, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if got := getBiosAttributes(t, mux, "/redfish/v1/Systems/bios-test/Bios/Settings")["BootMode"]; got != "Legacy" {
+		t.Errorf("Expected staged BootMode Legacy, got %v", got)
+	}
+	if got := getBiosAttributes(t, mux, "/redfish/v1/Systems/bios-test/Bios")["BootMode"]; got != "Uefi" {
+		t.Errorf("Expected BootMode to stay Uefi before reset, got %v", got)
+	}
+
+	// Reset the system and wait for the task to finish
+	req = httptest.NewRequest("POST", "/redfish/v1/Systems/bios-test/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "ForceRestart"}`))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202 for reset, got %d", w.Code)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for getBiosAttributes(t, mux, "/redfish/v1/Systems/bios-test/Bios")["BootMode"] != "Legacy" {
+		if time.Now().After(deadline) {
+			t.Fatal("Staged BootMode was not applied after reset")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if pending := getBiosAttributes(t, mux, "/redfish/v1/Systems/bios-test/Bios/Settings"); len(pending) != 0 {
+		t.Errorf("Expected no pending settings after reset, got %v", pending)
+	}
+}
+
+func TestBiosSettingsValidation(t *testing.T) {
+	systemStore.Put("bios-validation", models.NewComputerSystem("bios-validation"))
+	t.Cleanup(func() { systemStore.Delete("bios-validation") })
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown attribute", `{"Attributes": {"NoSuchAttribute": "Enabled"}}`},
+		{"wrong type", `{"Attributes": {"NumaNodesPerSocket": "two"}}`},
+		{"missing attributes", `{}`},
+		{"unsupported apply time", `{"Attributes": {"BootMode": "Legacy"}, "@Redfish.SettingsApplyTime": {"ApplyTime": "Immediate"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PATCH", "/redfish/v1/Systems/bios-validation/Bios/Settings", strings.NewReader(tt.body))
+			req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+		})
+	}
+
+	if pending := getBiosAttributes(t, mux, "/redfish/v1/Systems/bios-validation/Bios/Settings"); len(pending) != 0 {
+		t.Errorf("Rejected requests should not stage settings, got %v", pending)
+	}
+}
+
+func TestBiosSettingsAccess(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	// Staging BIOS changes requires the ConfigureComponents privilege
+	req := httptest.NewRequest("PATCH", "/redfish/v1/Systems/1/Bios/Settings", strings.NewReader(`{"Attributes": {"BootMode": "Legacy"}}`))
+	req = req.WithContext(auth.SetUserContextWithRole(req.Context(), "reader", "Bearer", "ReadOnly"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a ReadOnly user, got %d", w.Code)
+	}
+	if pending := getBiosAttributes(t, mux, "/redfish/v1/Systems/1/Bios/Settings"); len(pending) != 0 {
+		t.Errorf("A rejected request should not stage settings, got %v", pending)
+	}
+
+	// The Bios of a system that does not exist is not served
+	for _, method := range []string{"GET", "PATCH"} {
+		for _, path := range []string{"/redfish/v1/Systems/nope/Bios", "/redfish/v1/Systems/nope/Bios/Settings"} {
+			req := httptest.NewRequest(method, path, strings.NewReader(`{"Attributes": {"BootMode": "Legacy"}}`))
+			req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			if w.Code != http.StatusNotFound {
+				t.Errorf("%s %s: expected status 404, got %d", method, path, w.Code)
+			}
+		}
+	}
+	biosSettings.mutex.Lock()
+	_, staged := biosSettings.pending["/redfish/v1/Systems/nope/Bios"]
+	biosSettings.mutex.Unlock()
+	if staged {
+		t.Error("Expected no settings staged for an unknown system")
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// cachedResponse is the serialized body and ETag of a resource as served to
+// a plain GET
+type cachedResponse struct {
+	body []byte
+	etag string
+}
+
+// responseCache holds the serialized form of frequently polled resources,
+// keyed by @odata.id. Entries are invalidated whenever their resource is
+// stored, updated or deleted, and dropped altogether when configuration that
+// shapes every resource, such as the registered OEM vendors, changes.
+type responseCache struct {
+	mutex   sync.RWMutex
+	entries map[models.ODataID]*cachedResponse
+	// generation changes with every invalidation, so a response built from
+	// data that changed meanwhile is not stored
+	generation uint64
+
+	hits, misses atomic.Int64
+}
+
+var resourceResponses = newResponseCache()
+
+func init() {
+	systemStore.OnChange(func(id string) { resourceResponses.invalidate(models.NewODataID("/redfish/v1/Systems", id)) })
+	chassisStore.OnChange(func(id string) { resourceResponses.invalidate(models.NewODataID("/redfish/v1/Chassis", id)) })
+	managerStore.OnChange(func(id string) { resourceResponses.invalidate(models.NewODataID("/redfish/v1/Managers", id)) })
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[models.ODataID]*cachedResponse)}
+}
+
+// get returns the cached response for uri, building and caching it with
+// build on a miss. build reports false when the resource does not exist,
+// which is not cached.
+func (c *responseCache) get(uri models.ODataID, build func() (interface{}, bool)) (*cachedResponse, bool) {
+	c.mutex.RLock()
+	entry, ok := c.entries[uri]
+	generation := c.generation
+	c.mutex.RUnlock()
+	if ok {
+		c.hits.Add(1)
+		return entry, true
+	}
+
+	c.misses.Add(1)
+	resource, ok := build()
+	if !ok {
+		return nil, false
+	}
+	etag := resourceETag(resource)
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return nil, false
+	}
+	entry = &cachedResponse{body: append(body, '\n'), etag: etag}
+
+	c.mutex.Lock()
+	if c.generation == generation {
+		c.entries[uri] = entry
+	}
+	c.mutex.Unlock()
+	return entry, true
+}
+
+// invalidate drops the cached response of one resource
+func (c *responseCache) invalidate(uri models.ODataID) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, uri)
+	c.generation++
+}
+
+// clear drops every cached response
+func (c *responseCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	clear(c.entries)
+	c.generation++
+}
+
+// sendCachedResource answers a plain GET of a resource from the cache,
+// honoring If-None-Match. It reports false, having written nothing, when
+// the resource does not exist.
+func sendCachedResource(w http.ResponseWriter, r *http.Request, uri models.ODataID, build func() (interface{}, bool)) bool {
+	entry, ok := resourceResponses.get(uri, build)
+	if !ok {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", entry.etag)
+
+	if sendNotModified(w, r, entry.etag) {
+		return true
+	}
+	w.Write(entry.body)
+	return true
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestResourceResponseCache(t *testing.T) {
+	systemStore.Put("cache-test", models.NewComputerSystem("cache-test"))
+	t.Cleanup(func() { systemStore.Delete("cache-test") })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	send := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/redfish/v1/Systems/cache-test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// The second GET is served from the cache with the same ETag and body
+	misses := resourceResponses.misses.Load()
+	first := send("GET", "")
+	second := send("GET", "")
+	if first.Code != http.StatusOK || second.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d and %d", first.Code, second.Code)
+	}
+	if got := resourceResponses.misses.Load() - misses; got != 1 {
+		t.Errorf("Expected the resource to be built once, got %d builds", got)
+	}
+	if first.Header().Get("ETag") == "" || first.Header().Get("ETag") != second.Header().Get("ETag") {
+		t.Errorf("Expected the same ETag, got %q and %q", first.Header().Get("ETag"), second.Header().Get("ETag"))
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Error("Expected the cached body to match the built one")
+	}
+
+	// A PATCH invalidates the entry, so the next GET sees the change
+	if w := send("PATCH", `{"AssetTag": "cached"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected PATCH to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	misses = resourceResponses.misses.Load()
+	third := send("GET", "")
+	if got := resourceResponses.misses.Load() - misses; got != 1 {
+		t.Errorf("Expected the PATCH to invalidate the cached resource, got %d builds", got)
+	}
+	if third.Header().Get("ETag") == first.Header().Get("ETag") || !strings.Contains(third.Body.String(), `"AssetTag":"cached"`) {
+		t.Errorf("Expected the patched resource with a new ETag, got %s", third.Body.String())
+	}
+
+	// Deleted resources are not served from the cache
+	systemStore.Delete("cache-test")
+	if w := send("GET", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after deletion, got %d", w.Code)
+	}
+}
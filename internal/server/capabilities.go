@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+// serviceFeatures are the configurable features that are not visible from
+// the wired routes alone
+type serviceFeatures struct {
+	sse            bool
+	methodOverride bool
+}
+
+var enabledFeatures atomic.Pointer[serviceFeatures]
+
+func init() {
+	enabledFeatures.Store(&serviceFeatures{sse: true})
+}
+
+// setServiceFeatures applies the configured feature switches
+func setServiceFeatures(cfg *config.Config) {
+	enabledFeatures.Store(&serviceFeatures{
+		sse:            !cfg.Events.DisableSSE,
+		methodOverride: cfg.Server.AllowMethodOverride,
+	})
+}
+
+// capabilitiesHandler serves the capabilities document for the routes wired
+// into mux. Services and event features are reported by probing mux, so the
+// document cannot claim a route that is not served.
+func capabilitiesHandler(mux *http.ServeMux) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		setRedfishHeaders(w)
+		w.Header().Set("Allow", "GET")
+
+		switch r.Method {
+		case "GET":
+			sendJSON(w, http.StatusOK, buildCapabilities(mux))
+		default:
+			methodNotAllowed(w, r)
+		}
+	}
+}
+
+// buildCapabilities assembles the capabilities document from the routes
+// wired into mux, the advertised protocol features, the actions of the demo
+// resources and the configured feature switches
+func buildCapabilities(mux *http.ServeMux) *models.Capabilities {
+	capabilities := models.NewCapabilities()
+	features := enabledFeatures.Load()
+
+	for _, uri := range resourceLinks(models.NewServiceRoot("", "", "")) {
+		if routed(mux, uri) {
+			capabilities.Services = append(capabilities.Services, uri)
+		}
+	}
+
+	protocol := models.NewProtocolFeaturesSupported(int(maxExpandLevels.Load()))
+	if protocol.TopSkipQuery {
+		capabilities.QueryParameters = append(capabilities.QueryParameters, "$top", "$skip", "$skiptoken")
+	}
+	if protocol.FilterQuery {
+		capabilities.QueryParameters = append(capabilities.QueryParameters, "$filter", "$orderby")
+	}
+	if protocol.SelectQuery {
+		capabilities.QueryParameters = append(capabilities.QueryParameters, "$select")
+	}
+	if protocol.ExpandQuery.Levels {
+		capabilities.QueryParameters = append(capabilities.QueryParameters, "$expand")
+	}
+	capabilities.QueryParameters = append(capabilities.QueryParameters, "$format")
+	capabilities.MaxExpandLevels = protocol.ExpandQuery.MaxLevels
+
+	for _, resource := range []interface{}{models.NewComputerSystem("1"), models.NewManager("1"), models.NewChassis("1")} {
+		capabilities.Actions = append(capabilities.Actions, resourceActions(resource)...)
+	}
+	slices.Sort(capabilities.Actions)
+
+	capabilities.AsyncOperations = routed(mux, "/redfish/v1/TaskService/Tasks")
+	capabilities.EventSubscriptions = routed(mux, "/redfish/v1/EventService/Subscriptions")
+	capabilities.ServerSentEvents = features.sse && routed(mux, "/redfish/v1/EventService/SSE")
+	capabilities.MethodOverride = features.methodOverride
+
+	capabilities.Authentication = append(capabilities.Authentication, "Basic", "Session")
+	if _, ok := auth.GetAuthenticator().(auth.BearerAuthenticator); ok {
+		capabilities.Authentication = append(capabilities.Authentication, "Bearer")
+	}
+
+	oemActionsMutex.RLock()
+	for vendor := range oemActions {
+		capabilities.OemVendors = append(capabilities.OemVendors, vendor)
+	}
+	oemActionsMutex.RUnlock()
+	slices.Sort(capabilities.OemVendors)
+
+	return capabilities
+}
+
+// routed reports whether mux has a route registered for exactly uri, rather
+// than serving it through a broader fallback pattern
+func routed(mux *http.ServeMux, uri models.ODataID) bool {
+	_, pattern := mux.Handler(&http.Request{Method: "GET", URL: &url.URL{Path: string(uri)}})
+	return pattern == string(uri)
+}
+
+// resourceLinks returns the @odata.id of every link among a resource's
+// top-level properties
+func resourceLinks(resource interface{}) []models.ODataID {
+	var properties map[string]json.RawMessage
+	data, _ := json.Marshal(resource)
+	json.Unmarshal(data, &properties)
+
+	var links []models.ODataID
+	for _, raw := range properties {
+		var link models.Link
+		if json.Unmarshal(raw, &link) == nil && link.ODataID != "" {
+			links = append(links, link.ODataID)
+		}
+	}
+	slices.Sort(links)
+	return links
+}
+
+// resourceActions returns the names of the standard actions a resource
+// advertises, e.g. #ComputerSystem.Reset
+func resourceActions(resource interface{}) []string {
+	var properties struct {
+		Actions map[string]json.RawMessage `json:"Actions"`
+	}
+	data, _ := json.Marshal(resource)
+	json.Unmarshal(data, &properties)
+
+	var actions []string
+	for name := range properties.Actions {
+		if strings.HasPrefix(name, "#") {
+			actions = append(actions, name)
+		}
+	}
+	return actions
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestCapabilitiesReflectConfiguration(t *testing.T) {
+	t.Cleanup(func() { setServiceFeatures(&config.Config{}) })
+
+	get := func(s *Server, uri string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", uri, nil)
+		req.SetBasicAuth("admin", "password")
+		w := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+	capabilities := func(s *Server) models.Capabilities {
+		w := get(s, "/redfish/v1/Oem/Contoso/Capabilities")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var document models.Capabilities
+		if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+			t.Fatalf("Failed to decode capabilities: %v", err)
+		}
+		return document
+	}
+
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	document := capabilities(s)
+	if document.ODataID != "/redfish/v1/Oem/Contoso/Capabilities" {
+		t.Errorf("Expected the document under the Contoso vendor, got %s", document.ODataID)
+	}
+	if !document.ServerSentEvents || !document.EventSubscriptions || !document.AsyncOperations {
+		t.Errorf("Expected SSE, subscriptions and tasks to be reported, got %+v", document)
+	}
+	if !slices.Contains(document.Services, "/redfish/v1/Systems") || slices.Contains(document.Services, "/redfish/v1/JsonSchemas") {
+		t.Errorf("Expected only wired services, got %v", document.Services)
+	}
+	if !slices.Contains(document.Actions, "#ComputerSystem.Reset") || !slices.Contains(document.OemVendors, "Contoso") {
+		t.Errorf("Expected the reset action and Contoso vendor, got %v and %v", document.Actions, document.OemVendors)
+	}
+	if document.MethodOverride || slices.Contains(document.Authentication, "Bearer") {
+		t.Errorf("Expected method override and bearer tokens to be off, got %+v", document)
+	}
+
+	s, err = New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}, Events: config.EventsConfig{DisableSSE: true}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if capabilities(s).ServerSentEvents {
+		t.Error("Expected SSE to be reported as unavailable when disabled")
+	}
+	if w := get(s, "/redfish/v1/EventService/SSE"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected the disabled SSE stream to return 404, got %d", w.Code)
+	}
+	var eventService models.EventService
+	json.Unmarshal(get(s, "/redfish/v1/EventService").Body.Bytes(), &eventService)
+	if eventService.ServerSentEventUri != "" {
+		t.Errorf("Expected no ServerSentEventUri, got %s", eventService.ServerSentEventUri)
+	}
+}
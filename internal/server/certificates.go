@@ -0,0 +1,198 @@
+package server
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+// subscriptionCertificatesHandler handles the certificates pinned for an
+// event subscription's destination
+func subscriptionCertificatesHandler(w http.ResponseWriter, r *http.Request, subscriptionID string, subPath []string) {
+	switch len(subPath) {
+	case 0:
+		w.Header().Set("Allow", "GET, POST")
+		switch r.Method {
+		case "GET":
+			handleGetSubscriptionCertificates(w, r, subscriptionID)
+		case "POST":
+			handlePostSubscriptionCertificate(w, r, subscriptionID)
+		default:
+			methodNotAllowed(w, r)
+		}
+	case 1:
+		w.Header().Set("Allow", "GET, DELETE")
+		switch r.Method {
+		case "GET":
+			handleGetSubscriptionCertificate(w, r, subscriptionID, subPath[0])
+		case "DELETE":
+			handleDeleteSubscriptionCertificate(w, r, subscriptionID, subPath[0])
+		default:
+			methodNotAllowed(w, r)
+		}
+	default:
+		sendResourceNotFound(w, r)
+	}
+}
+
+// subscriptionCertificatesURI is the certificate collection of a subscription
+func subscriptionCertificatesURI(subscriptionID string) models.ODataID {
+	return models.ODataID("/redfish/v1/EventService/Subscriptions/" + subscriptionID + "/Certificates")
+}
+
+// handleGetSubscriptionCertificates returns the certificate collection of a subscription
+func handleGetSubscriptionCertificates(w http.ResponseWriter, r *http.Request, subscriptionID string) {
+	certificates, ok := getSubscriptionCertificates(subscriptionID)
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	members := make([]models.Link, 0, len(certificates))
+	for _, certificate := range certificates {
+		members = append(members, models.Link{ODataID: certificate.ODataID})
+	}
+	collection := models.NewCertificateCollection(subscriptionCertificatesURI(subscriptionID), members)
+
+	etag := generateETag(collection)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, collection)
+}
+
+// handleGetSubscriptionCertificate returns a single certificate pinned for a subscription
+func handleGetSubscriptionCertificate(w http.ResponseWriter, r *http.Request, subscriptionID, id string) {
+	certificates, _ := getSubscriptionCertificates(subscriptionID)
+	var certificate *models.Certificate
+	for _, candidate := range certificates {
+		if candidate.ID == id {
+			certificate = candidate
+			break
+		}
+	}
+	if certificate == nil {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	etag := resourceETag(certificate)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, certificate)
+}
+
+// handlePostSubscriptionCertificate pins a PEM certificate for a
+// subscription's destination. Once a subscription has pinned certificates,
+// event delivery trusts only those.
+func handlePostSubscriptionCertificate(w http.ResponseWriter, r *http.Request, subscriptionID string) {
+	if !requirePrivilege(w, r, "ConfigureManager") {
+		return
+	}
+
+	var requestBody struct {
+		CertificateString string `json:"CertificateString"`
+		CertificateType   string `json:"CertificateType"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.CertificateString == "" {
+		sendRedfishError(w, "PropertyMissing", "CertificateString is required", http.StatusBadRequest)
+		return
+	}
+	if requestBody.CertificateType != "" && requestBody.CertificateType != "PEM" {
+		sendRedfishError(w, "PropertyValueNotInList", fmt.Sprintf("CertificateType %s is not supported; allowed PEM", requestBody.CertificateType), http.StatusBadRequest)
+		return
+	}
+	parsed, err := parsePEMCertificate(requestBody.CertificateString)
+	if err != nil {
+		sendRedfishError(w, "PropertyValueFormatError", fmt.Sprintf("CertificateString is not a valid PEM certificate: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	certificate, err := addSubscriptionCertificate(subscriptionID, func(id string) *models.Certificate {
+		certificate := models.NewCertificate(subscriptionCertificatesURI(subscriptionID), id, requestBody.CertificateString)
+		certificate.Issuer = models.CertificateIdentifier{CommonName: parsed.Issuer.CommonName, Organization: firstOrEmpty(parsed.Issuer.Organization)}
+		certificate.Subject = models.CertificateIdentifier{CommonName: parsed.Subject.CommonName, Organization: firstOrEmpty(parsed.Subject.Organization)}
+		certificate.ValidNotBefore = parsed.NotBefore.UTC().Format(time.RFC3339)
+		certificate.ValidNotAfter = parsed.NotAfter.UTC().Format(time.RFC3339)
+		return certificate
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrResourceMissing) {
+			sendResourceNotFound(w, r)
+			return
+		}
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", string(certificate.ODataID))
+	sendJSON(w, http.StatusCreated, certificate)
+}
+
+// handleDeleteSubscriptionCertificate unpins a certificate of a subscription
+func handleDeleteSubscriptionCertificate(w http.ResponseWriter, r *http.Request, subscriptionID, id string) {
+	if !requirePrivilege(w, r, "ConfigureManager") {
+		return
+	}
+
+	existed, err := deleteSubscriptionCertificate(subscriptionID, id)
+	if !existed {
+		sendResourceNotFound(w, r)
+		return
+	}
+	if err != nil {
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parsePEMCertificate parses the first certificate of a PEM string
+func parsePEMCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no CERTIFICATE block found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// firstOrEmpty returns the first value of an X.509 name attribute, if any
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
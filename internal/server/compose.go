@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+// composeActionURI is the target of the OEM action that composes a new
+// ComputerSystem on the systems collection
+const composeActionURI = "/redfish/v1/Systems/Actions/Oem/Contoso.Compose"
+
+// Limits on the resources a single composed system may request
+const (
+	composeMaxProcessors = 16
+	composeMaxMemoryGiB  = 512
+)
+
+// composeActions advertises the compose action on the systems collection
+func composeActions() *models.Actions {
+	return &models.Actions{
+		Oem: map[string]interface{}{
+			"#Contoso.Compose": map[string]string{
+				"target": composeActionURI,
+				"title":  "Compose a Computer System",
+			},
+		},
+	}
+}
+
+// handleSystemsCollectionAction handles actions on the systems collection
+func handleSystemsCollectionAction(w http.ResponseWriter, r *http.Request, path string) {
+	if path != composeActionURI {
+		sendRedfishError(w, "ActionNotSupported", fmt.Sprintf("Action %s not supported for ComputerSystemCollection", path), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Allow", "POST")
+	switch r.Method {
+	case "POST":
+		handleComposeSystem(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// handleComposeSystem composes a new ComputerSystem from the requested
+// processor and memory resources and adds it to the systems collection
+func handleComposeSystem(w http.ResponseWriter, r *http.Request) {
+	if !requirePrivilege(w, r, "ConfigureComponents") {
+		return
+	}
+
+	var requestBody struct {
+		Name           string   `json:"Name"`
+		ProcessorCount *int     `json:"ProcessorCount"`
+		MemoryGiB      *float64 `json:"MemoryGiB"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.ProcessorCount == nil {
+		sendRedfishError(w, "ActionParameterMissing", "ProcessorCount is required", http.StatusBadRequest)
+		return
+	}
+	if requestBody.MemoryGiB == nil {
+		sendRedfishError(w, "ActionParameterMissing", "MemoryGiB is required", http.StatusBadRequest)
+		return
+	}
+	if count := *requestBody.ProcessorCount; count < 1 || count > composeMaxProcessors {
+		sendRedfishError(w, "PropertyValueOutOfRange", fmt.Sprintf("ProcessorCount %d is out of range; allowed 1 to %d", count, composeMaxProcessors), http.StatusBadRequest)
+		return
+	}
+	if memory := *requestBody.MemoryGiB; memory <= 0 || memory > composeMaxMemoryGiB {
+		sendRedfishError(w, "PropertyValueOutOfRange", fmt.Sprintf("MemoryGiB %g is out of range; allowed up to %d", memory, composeMaxMemoryGiB), http.StatusBadRequest)
+		return
+	}
+
+	id := newResourceID()
+
+	system := models.NewComputerSystem(id)
+	system.SystemType = "Composed"
+	system.Name = "Composed Computer System"
+	if requestBody.Name != "" {
+		system.Name = requestBody.Name
+	}
+	system.ProcessorSummary.Count = *requestBody.ProcessorCount
+	system.MemorySummary.TotalSystemMemoryGiB = *requestBody.MemoryGiB
+
+	if err := systemStore.Create(id, system); err != nil {
+		if errors.Is(err, store.ErrExists) {
+			sendRedfishError(w, "ResourceAlreadyExists", err.Error(), http.StatusConflict)
+			return
+		}
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", string(system.ODataID))
+	sendJSON(w, http.StatusCreated, system)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestComposeSystem(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("POST", composeActionURI, strings.NewReader(`{"Name": "Web tier", "ProcessorCount": 4, "MemoryGiB": 64}`))
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	location := w.Header().Get("Location")
+	id := strings.TrimPrefix(location, "/redfish/v1/Systems/")
+	if id == location || id == "" {
+		t.Fatalf("Expected a system Location, got %q", location)
+	}
+	defer systemStore.Delete(id)
+
+	req = httptest.NewRequest("GET", location, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected composed system to be retrievable, got %d", w.Code)
+	}
+	var system models.ComputerSystem
+	if err := json.NewDecoder(w.Body).Decode(&system); err != nil {
+		t.Fatalf("Failed to decode system: %v", err)
+	}
+	if system.SystemType != "Composed" || system.Name != "Web tier" || system.ProcessorSummary.Count != 4 || system.MemorySummary.TotalSystemMemoryGiB != 64 {
+		t.Errorf("Unexpected composed system: %+v", system)
+	}
+
+	req = httptest.NewRequest("GET", "/redfish/v1/Systems", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var collection models.ComputerSystemCollection
+	if err := json.NewDecoder(w.Body).Decode(&collection); err != nil {
+		t.Fatalf("Failed to decode collection: %v", err)
+	}
+	if !containsLink(collection.Members, location) {
+		t.Errorf("Expected %s in the systems collection, got %v", location, collection.Members)
+	}
+	if collection.Actions == nil || collection.Actions.Oem["#Contoso.Compose"] == nil {
+		t.Errorf("Expected the compose action to be advertised, got %+v", collection.Actions)
+	}
+}
+
+func TestComposeSystemRejected(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	before := len(systemStore.IDs())
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"too many processors", `{"ProcessorCount": 64, "MemoryGiB": 64}`, http.StatusBadRequest},
+		{"too much memory", `{"ProcessorCount": 2, "MemoryGiB": 4096}`, http.StatusBadRequest},
+		{"no processors", `{"ProcessorCount": 0, "MemoryGiB": 8}`, http.StatusBadRequest},
+		{"missing memory", `{"ProcessorCount": 2}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", composeActionURI, strings.NewReader(tt.body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, w.Code)
+		}
+	}
+
+	// Composing requires the ConfigureComponents privilege
+	req := httptest.NewRequest("POST", composeActionURI, strings.NewReader(`{"ProcessorCount": 2, "MemoryGiB": 8}`))
+	req = req.WithContext(auth.SetUserContextWithRole(req.Context(), "reader", "Bearer", "ReadOnly"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a ReadOnly user, got %d", w.Code)
+	}
+
+	if after := len(systemStore.IDs()); after != before {
+		t.Errorf("Rejected requests created systems: %d -> %d", before, after)
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"bytes"
+	"encoding/csv"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// csvMediaType is the representation ops tooling can request for collections
+// that support it
+const csvMediaType = "text/csv"
+
+// systemsCSVColumns are the properties exported per system in a CSV listing
+var systemsCSVColumns = []string{"Id", "Name", "SystemType", "Manufacturer", "Model", "SerialNumber", "PowerState", "Health"}
+
+// negotiateMediaType picks the offered media type the Accept header prefers:
+// the one matched by the media range with the highest quality, the earliest
+// range winning ties. A wildcard selects the first offered type, as does an
+// empty header. offered must not be empty.
+func negotiateMediaType(accept string, offered []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offered[0]
+	}
+
+	best, bestWeight := offered[0], 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		weight := 1.0
+		if q, ok := params["q"]; ok {
+			if weight, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if weight <= bestWeight {
+			continue
+		}
+		for _, candidate := range offered {
+			if mediaRangeMatches(mediaType, candidate) {
+				best, bestWeight = candidate, weight
+				break
+			}
+		}
+	}
+	return best
+}
+
+// sendSystemsCSV writes the given systems collection members as CSV, one row
+// per system with its key properties resolved from the store. Members that
+// disappeared since the listing was built are skipped.
+func sendSystemsCSV(w http.ResponseWriter, members []models.Link) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(systemsCSVColumns)
+	for _, member := range members {
+		system, ok := getSystem(path.Base(string(member.ODataID)))
+		if !ok {
+			continue
+		}
+		writer.Write([]string{
+			system.ID, system.Name, system.SystemType, system.Manufacturer, system.Model,
+			system.SerialNumber, system.PowerState, system.Status.Health,
+		})
+	}
+	writer.Flush()
+
+	w.Header().Set("Content-Type", csvMediaType+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("Failed to write CSV response: %v", err)
+	}
+}
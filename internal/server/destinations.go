@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+// destinationLookupTimeout bounds resolving a subscription destination's host
+const destinationLookupTimeout = 2 * time.Second
+
+// destinationRule matches a subscription destination by host name or by the
+// address it resolves to
+type destinationRule struct {
+	host   string // lower case; empty for address rules
+	prefix netip.Prefix
+}
+
+// destinationPolicy decides which addresses event destinations may use
+type destinationPolicy struct {
+	allow, deny []destinationRule
+}
+
+var currentDestinationPolicy atomic.Pointer[destinationPolicy]
+
+func init() {
+	currentDestinationPolicy.Store(&destinationPolicy{})
+}
+
+// setDestinationPolicy applies the configured destination allow and deny
+// lists. Entries are host names, IP addresses or CIDR ranges.
+func setDestinationPolicy(events config.EventsConfig) error {
+	allow, err := parseDestinationRules(events.DestinationAllow)
+	if err != nil {
+		return fmt.Errorf("events destination allow list: %w", err)
+	}
+	deny, err := parseDestinationRules(events.DestinationDeny)
+	if err != nil {
+		return fmt.Errorf("events destination deny list: %w", err)
+	}
+	currentDestinationPolicy.Store(&destinationPolicy{allow: allow, deny: deny})
+	return nil
+}
+
+// parseDestinationRules parses allow or deny list entries
+func parseDestinationRules(entries []string) ([]destinationRule, error) {
+	var rules []destinationRule
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case strings.Contains(entry, "/"):
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a valid CIDR range", entry)
+			}
+			rules = append(rules, destinationRule{prefix: prefix.Masked()})
+		default:
+			if addr, err := netip.ParseAddr(entry); err == nil {
+				rules = append(rules, destinationRule{prefix: netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())})
+			} else {
+				rules = append(rules, destinationRule{host: strings.ToLower(strings.TrimSuffix(entry, "."))})
+			}
+		}
+	}
+	return rules, nil
+}
+
+// matchesDestination reports whether any rule matches the host name or,
+// when it is valid, the address
+func matchesDestination(rules []destinationRule, host string, addr netip.Addr) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, rule := range rules {
+		if rule.host != "" && rule.host == host {
+			return true
+		}
+		if rule.host == "" && addr.IsValid() && rule.prefix.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// permits reports whether events may be sent to host at addr. Denied entries
+// always win; with an allow list only its entries are permitted, otherwise
+// everything but loopback, link-local and unspecified addresses is.
+func (p *destinationPolicy) permits(host string, addr netip.Addr) bool {
+	if matchesDestination(p.deny, host, addr) {
+		return false
+	}
+	if len(p.allow) > 0 {
+		return matchesDestination(p.allow, host, addr)
+	}
+	addr = addr.Unmap()
+	return !(addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsUnspecified())
+}
+
+// checkDestination checks a subscription destination against the configured
+// policy, resolving its host so a name cannot stand in for a blocked address.
+// A host that does not resolve is judged by its name alone.
+func checkDestination(destination string) error {
+	u, err := url.Parse(destination)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("Destination %s is not an absolute URL", destination)
+	}
+	host := u.Hostname()
+	policy := currentDestinationPolicy.Load()
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), destinationLookupTimeout)
+		addrs, _ = net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		cancel()
+	}
+	if len(addrs) == 0 {
+		if !policy.permits(host, netip.Addr{}) {
+			return fmt.Errorf("Destination host %s is not permitted for event subscriptions", host)
+		}
+		return nil
+	}
+	for _, addr := range addrs {
+		if policy.permits(host, addr) {
+			continue
+		}
+		if addr.String() == host {
+			return fmt.Errorf("Destination address %s is not permitted for event subscriptions", host)
+		}
+		return fmt.Errorf("Destination host %s resolves to %s, which is not permitted for event subscriptions", host, addr.Unmap())
+	}
+	return nil
+}
+
+// destinationDialControl refuses connections to addresses the destination
+// policy does not permit for host, so a name that resolved to a permitted
+// address when the subscription was created cannot later reach a blocked one
+func destinationDialControl(host string) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		if !currentDestinationPolicy.Load().permits(host, addrPort.Addr()) {
+			return fmt.Errorf("event destination address %s is not permitted", addrPort.Addr().Unmap())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+// allowLoopbackDestinations lets a test subscribe httptest servers, which
+// listen on loopback addresses the default destination policy refuses
+func allowLoopbackDestinations(t *testing.T) {
+	t.Helper()
+	if err := setDestinationPolicy(config.EventsConfig{DestinationAllow: []string{"127.0.0.0/8", "::1"}}); err != nil {
+		t.Fatalf("Failed to allow loopback destinations: %v", err)
+	}
+	t.Cleanup(func() { setDestinationPolicy(config.EventsConfig{}) })
+}
+
+func TestSubscriptionDestinationPolicy(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	t.Cleanup(func() { setDestinationPolicy(config.EventsConfig{}) })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	subscribe := func(destination string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(`{"Destination": "`+destination+`"}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	expectRejected := func(destination string) {
+		t.Helper()
+		w := subscribe(destination)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected %s to be rejected with 400, got %d", destination, w.Code)
+		}
+		var errorResponse models.RedfishError
+		json.NewDecoder(w.Body).Decode(&errorResponse)
+		if errorResponse.Error.Code != baseRegistry.MessageID("PropertyValueNotInList") {
+			t.Errorf("Expected PropertyValueNotInList for %s, got %s", destination, errorResponse.Error.Code)
+		}
+	}
+
+	// Loopback and link-local addresses, such as the cloud metadata service,
+	// are refused by default
+	expectRejected("http://169.254.169.254/latest/meta-data")
+	expectRejected("http://127.0.0.1:8080/events")
+	expectRejected("http://[::1]/events")
+	if w := subscribe("http://203.0.113.10/events"); w.Code != http.StatusCreated {
+		t.Fatalf("Expected a public destination to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// An allow list admits its entries and nothing else
+	if err := setDestinationPolicy(config.EventsConfig{DestinationAllow: []string{"169.254.10.0/24", "listener.example.com"}}); err != nil {
+		t.Fatalf("Failed to set the destination policy: %v", err)
+	}
+	if w := subscribe("http://169.254.10.5/events"); w.Code != http.StatusCreated {
+		t.Errorf("Expected an allowed link-local destination to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := subscribe("http://listener.example.com/events"); w.Code != http.StatusCreated {
+		t.Errorf("Expected an allowed host name to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	expectRejected("http://169.254.169.254/latest/meta-data")
+	expectRejected("http://203.0.113.10/events")
+
+	// Denied entries win over allowed ones
+	if err := setDestinationPolicy(config.EventsConfig{DestinationAllow: []string{"203.0.113.0/24"}, DestinationDeny: []string{"203.0.113.10"}}); err != nil {
+		t.Fatalf("Failed to set the destination policy: %v", err)
+	}
+	expectRejected("http://203.0.113.10/events")
+	if w := subscribe("http://203.0.113.11/events"); w.Code != http.StatusCreated {
+		t.Errorf("Expected an allowed destination outside the deny list to be accepted, got %d", w.Code)
+	}
+
+	if err := setDestinationPolicy(config.EventsConfig{DestinationDeny: []string{"10.0.0.0/33"}}); err == nil {
+		t.Error("Expected an invalid CIDR range to be rejected")
+	}
+}
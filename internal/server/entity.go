@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// entityHandler resolves an entity-id to the resource it names, as in
+// GET /redfish/v1/$entity?$id=/redfish/v1/Systems/1. The resource is served
+// by the handler that owns its URI, so the body, @odata.context and ETag are
+// the same as when it is fetched directly. Other query parameters, such as
+// $select, apply to the resolved resource.
+func entityHandler(mux *http.ServeMux) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		setRedfishHeaders(w)
+		w.Header().Set("Allow", "GET, HEAD")
+		if r.Method != "GET" && r.Method != "HEAD" {
+			methodNotAllowed(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		target, err := entityPath(query.Get("$id"), r.Host)
+		if err != nil {
+			sendRedfishError(w, "QueryParameterValueFormatError", err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.Del("$id")
+
+		resolved := r.Clone(r.Context())
+		resolved.URL.Path = target
+		resolved.URL.RawPath = ""
+		resolved.URL.RawQuery = query.Encode()
+		resolved.RequestURI = resolved.URL.RequestURI()
+		w.Header().Del("Allow")
+		mux.ServeHTTP(w, resolved)
+	}
+}
+
+// entityPath returns the resource path an entity-id names. The id is either
+// a path below /redfish/v1 or an absolute URL of this service.
+func entityPath(id, host string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("The $id query parameter is required")
+	}
+	parsed, err := url.Parse(id)
+	if err != nil || (parsed.Host != "" && parsed.Host != host) {
+		return "", fmt.Errorf("The $id %q is not a resource of this service", id)
+	}
+	target := path.Clean(parsed.Path)
+	if (target != "/redfish/v1" && !strings.HasPrefix(target, "/redfish/v1/")) || target == "/redfish/v1/$entity" {
+		return "", fmt.Errorf("The $id %q is not a resource of this service", id)
+	}
+	return target, nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+)
+
+func TestODataContextFragments(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	get := func(uri string) (*httptest.ResponseRecorder, string) {
+		t.Helper()
+		r := httptest.NewRequest("GET", uri, nil)
+		r = r.WithContext(auth.SetUserContext(r.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var body struct {
+			Context string `json:"@odata.context"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body.Context
+	}
+
+	tests := []struct {
+		uri, context string
+	}{
+		{"/redfish/v1/", "/redfish/v1/$metadata#ServiceRoot.ServiceRoot"},
+		{"/redfish/v1/Systems", "/redfish/v1/$metadata#ComputerSystemCollection.ComputerSystemCollection"},
+		{"/redfish/v1/Chassis/1", "/redfish/v1/$metadata#Chassis.Chassis"},
+		{"/redfish/v1/AccountService/Roles/Operator", "/redfish/v1/$metadata#Role.Role"},
+		{"/redfish/v1/Systems/1?$select=PowerState,Id", "/redfish/v1/$metadata#ComputerSystem.ComputerSystem(PowerState,Id)"},
+	}
+	for _, tt := range tests {
+		if w, context := get(tt.uri); w.Code != http.StatusOK || context != tt.context {
+			t.Errorf("GET %s: expected %q, got %d %q", tt.uri, tt.context, w.Code, context)
+		}
+	}
+
+	// A resource fetched through its entity-id is the resource fetched directly
+	for _, tt := range tests {
+		direct, _ := get(tt.uri)
+		target, _ := url.Parse(tt.uri)
+		query := target.Query()
+		query.Set("$id", target.Path)
+		w, context := get("/redfish/v1/$entity?" + query.Encode())
+		if w.Code != http.StatusOK || context != tt.context {
+			t.Errorf("$entity of %s: expected %q, got %d %q", tt.uri, tt.context, w.Code, context)
+		}
+		if w.Header().Get("ETag") != direct.Header().Get("ETag") {
+			t.Errorf("$entity of %s: expected ETag %q, got %q", tt.uri, direct.Header().Get("ETag"), w.Header().Get("ETag"))
+		}
+	}
+	if w, context := get("/redfish/v1/$entity?$id=" + url.QueryEscape("http://example.com/redfish/v1/Chassis/1")); w.Code != http.StatusOK || context != "/redfish/v1/$metadata#Chassis.Chassis" {
+		t.Errorf("Expected an absolute entity-id of this service to resolve, got %d %q", w.Code, context)
+	}
+
+	for _, id := range []string{"", "/health", "/redfish/v1/$entity", "http://elsewhere.example/redfish/v1/Chassis/1"} {
+		if w, _ := get("/redfish/v1/$entity?$id=" + url.QueryEscape(id)); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected $id %q to be rejected, got %d", id, w.Code)
+		}
+	}
+	if w, _ := get("/redfish/v1/$entity?$id=/redfish/v1/Chassis/missing"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected an unknown entity to be 404, got %d", w.Code)
+	}
+
+	// URIs nothing serves are not answered with the service root
+	if w, context := get("/redfish/v1/JsonSchemas"); w.Code != http.StatusNotFound || context != "" {
+		t.Errorf("Expected an unrouted URI to be 404, got %d %q", w.Code, context)
+	}
+}
@@ -0,0 +1,349 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// Global Ethernet interface storage for demo purposes, keyed by manager ID
+// and then interface ID. Each manager starts with a single interface.
+var (
+	ethernetMutex      sync.Mutex
+	ethernetInterfaces = make(map[string]map[string]*models.EthernetInterface)
+)
+
+// managerEthernetInterfaces returns the Ethernet interfaces of a manager,
+// creating the default interface on first use. The caller must hold
+// ethernetMutex.
+func managerEthernetInterfaces(managerID string) map[string]*models.EthernetInterface {
+	nics, ok := ethernetInterfaces[managerID]
+	if !ok {
+		nics = map[string]*models.EthernetInterface{
+			"eth0": models.NewEthernetInterface(managerID, "eth0"),
+		}
+		ethernetInterfaces[managerID] = nics
+	}
+	return nics
+}
+
+// ethernetInterfacesHandler handles a manager's Ethernet interface collection and items
+func ethernetInterfacesHandler(w http.ResponseWriter, r *http.Request, managerID string, subPath []string) {
+	switch len(subPath) {
+	case 0:
+		w.Header().Set("Allow", "GET")
+		switch r.Method {
+		case "GET":
+			handleGetEthernetInterfaces(w, r, managerID)
+		default:
+			methodNotAllowed(w, r)
+		}
+	case 1:
+		w.Header().Set("Allow", "GET, PATCH")
+		switch r.Method {
+		case "GET":
+			handleGetEthernetInterface(w, r, managerID, subPath[0])
+		case "PATCH":
+			handleUpdateEthernetInterface(w, r, managerID, subPath[0])
+		default:
+			methodNotAllowed(w, r)
+		}
+	default:
+		sendResourceNotFound(w, r)
+	}
+}
+
+// handleGetEthernetInterfaces returns the Ethernet interface collection of a manager
+func handleGetEthernetInterfaces(w http.ResponseWriter, r *http.Request, managerID string) {
+	ethernetMutex.Lock()
+	nics := managerEthernetInterfaces(managerID)
+	ids := make([]string, 0, len(nics))
+	for id := range nics {
+		ids = append(ids, id)
+	}
+	ethernetMutex.Unlock()
+
+	sort.Strings(ids)
+	members := make([]models.Link, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, models.Link{ODataID: models.NewODataID("/redfish/v1/Managers", managerID, "EthernetInterfaces", id)})
+	}
+	collection := models.NewEthernetInterfaceCollection(managerID, members)
+
+	etag := generateETag(collection)
+	w.Header().Set("ETag", etag)
+
+	if sendNotModified(w, r, etag) {
+		return
+	}
+
+	sendJSON(w, http.StatusOK, collection)
+}
+
+// ethernetInterface returns a copy of an Ethernet interface
+func ethernetInterface(managerID, id string) (*models.EthernetInterface, bool) {
+	ethernetMutex.Lock()
+	defer ethernetMutex.Unlock()
+	return ethernetInterfaceLocked(managerID, id)
+}
+
+// ethernetInterfaceLocked returns a copy of an Ethernet interface. The caller
+// must hold ethernetMutex.
+func ethernetInterfaceLocked(managerID, id string) (*models.EthernetInterface, bool) {
+	nic, ok := managerEthernetInterfaces(managerID)[id]
+	if !ok {
+		return nil, false
+	}
+	result := *nic
+	result.IPv4Addresses = slices.Clone(nic.IPv4Addresses)
+	result.IPv4StaticAddresses = slices.Clone(nic.IPv4StaticAddresses)
+	result.IPv6Addresses = slices.Clone(nic.IPv6Addresses)
+	result.IPv6StaticAddresses = slices.Clone(nic.IPv6StaticAddresses)
+	return &result, true
+}
+
+// handleGetEthernetInterface returns a single Ethernet interface
+func handleGetEthernetInterface(w http.ResponseWriter, r *http.Request, managerID, id string) {
+	nic, ok := ethernetInterface(managerID, id)
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	etag := resourceETag(nic)
+	w.Header().Set("ETag", etag)
+
+	if sendNotModified(w, r, etag) {
+		return
+	}
+
+	sendJSON(w, http.StatusOK, nic)
+}
+
+// handleUpdateEthernetInterface updates the address configuration of an
+// Ethernet interface (PATCH)
+func handleUpdateEthernetInterface(w http.ResponseWriter, r *http.Request, managerID, id string) {
+	if !requirePrivilege(w, r, "ConfigureManager") {
+		return
+	}
+	if _, ok := ethernetInterface(managerID, id); !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	var requestBody map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	// The changes are checked against the stored interface and applied under
+	// the lock, so concurrent PATCHes cannot overwrite each other's changes
+	ethernetMutex.Lock()
+	nic, ok := ethernetInterfaceLocked(managerID, id)
+	var rejection *patchRejection
+	if ok {
+		if rejection = applyEthernetChanges(nic, requestBody); rejection == nil {
+			stored := *nic
+			managerEthernetInterfaces(managerID)[id] = &stored
+		}
+	}
+	ethernetMutex.Unlock()
+
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+	if rejection != nil {
+		sendRedfishError(w, rejection.key, rejection.message, rejection.status)
+		return
+	}
+	sendUpdatedResource(w, nic)
+}
+
+// patchRejection is why a PATCH was refused: the Base registry message, its
+// text and the status to answer with
+type patchRejection struct {
+	key     string
+	message string
+	status  int
+}
+
+// applyEthernetChanges validates the properties of a PATCH and applies them
+// to nic, leaving nic unchanged if any is rejected
+func applyEthernetChanges(nic *models.EthernetInterface, requestBody map[string]json.RawMessage) *patchRejection {
+	reject := func(key string, status int, format string, args ...interface{}) *patchRejection {
+		return &patchRejection{key: key, message: fmt.Sprintf(format, args...), status: status}
+	}
+
+	// The IPv4 properties are checked together once all are parsed, as
+	// static addresses only apply with DHCP disabled
+	dhcpEnabled := nic.DHCPv4.DHCPEnabled
+	ipv4Addresses := nic.IPv4StaticAddresses
+	var ipv6Addresses []models.IPv6Address
+	ipv6Changed := false
+	ipv6Gateway := nic.IPv6DefaultGateway
+	for name, raw := range requestBody {
+		switch name {
+		case "DHCPv4":
+			var dhcp struct{ DHCPEnabled *bool }
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&dhcp); err != nil || dhcp.DHCPEnabled == nil {
+				return reject("PropertyValueTypeError", http.StatusBadRequest, "DHCPv4 must be an object with a boolean DHCPEnabled, got %s", raw)
+			}
+			dhcpEnabled = *dhcp.DHCPEnabled
+		case "IPv4StaticAddresses":
+			addresses, err := parseIPv4StaticAddresses(raw)
+			if err != nil {
+				var conflict *addressOriginConflict
+				if errors.As(err, &conflict) {
+					return reject("PropertyValueConflict", http.StatusConflict, "%s", err)
+				}
+				return reject("PropertyValueFormatError", http.StatusBadRequest, "%s", err)
+			}
+			ipv4Addresses = addresses
+		case "IPv6StaticAddresses":
+			addresses, err := parseIPv6StaticAddresses(raw)
+			if err != nil {
+				return reject("PropertyValueFormatError", http.StatusBadRequest, "%s", err)
+			}
+			if len(addresses) > nic.MaxIPv6StaticAddresses {
+				return reject("PropertyValueOutOfRange", http.StatusBadRequest, "At most %d IPv6StaticAddresses are supported, got %d", nic.MaxIPv6StaticAddresses, len(addresses))
+			}
+			ipv6Addresses, ipv6Changed = addresses, true
+		case "IPv6DefaultGateway":
+			var gateway string
+			if err := json.Unmarshal(raw, &gateway); err != nil {
+				return reject("PropertyValueTypeError", http.StatusBadRequest, "IPv6DefaultGateway must be a string, got %s", raw)
+			}
+			if _, err := parseIPv6(gateway); err != nil {
+				return reject("PropertyValueFormatError", http.StatusBadRequest, "IPv6DefaultGateway %s", err)
+			}
+			ipv6Gateway = gateway
+		default:
+			return reject("PropertyNotWritable", http.StatusBadRequest, "The property %s is read only", name)
+		}
+	}
+	if dhcpEnabled && len(ipv4Addresses) > 0 {
+		return reject("PropertyValueConflict", http.StatusConflict, "IPv4StaticAddresses require DHCPv4 DHCPEnabled to be false; disable DHCP or clear the static addresses")
+	}
+
+	nic.SetIPv4Configuration(dhcpEnabled, ipv4Addresses)
+	if ipv6Changed {
+		nic.SetIPv6StaticAddresses(ipv6Addresses)
+	}
+	nic.IPv6DefaultGateway = ipv6Gateway
+	return nil
+}
+
+// addressOriginConflict reports a static address given an AddressOrigin
+// other than Static
+type addressOriginConflict struct {
+	index  int
+	origin string
+}
+
+func (e *addressOriginConflict) Error() string {
+	return fmt.Sprintf("IPv4StaticAddresses[%d] has AddressOrigin %s, but static addresses must be Static", e.index, e.origin)
+}
+
+// parseIPv4StaticAddresses parses a PATCH of IPv4StaticAddresses, each entry
+// holding an Address, a SubnetMask and optionally a Gateway. An entry may
+// repeat AddressOrigin only as Static.
+func parseIPv4StaticAddresses(raw json.RawMessage) ([]models.IPv4Address, error) {
+	var entries []struct {
+		Address       *string
+		SubnetMask    *string
+		Gateway       string
+		AddressOrigin string
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("IPv4StaticAddresses must be an array of objects with Address, SubnetMask and Gateway")
+	}
+
+	addresses := make([]models.IPv4Address, 0, len(entries))
+	for i, entry := range entries {
+		if entry.AddressOrigin != "" && entry.AddressOrigin != "Static" {
+			return nil, &addressOriginConflict{index: i, origin: entry.AddressOrigin}
+		}
+		if entry.Address == nil || entry.SubnetMask == nil {
+			return nil, fmt.Errorf("IPv4StaticAddresses[%d] requires Address and SubnetMask", i)
+		}
+		addr, err := netip.ParseAddr(*entry.Address)
+		if err != nil || !addr.Is4() || addr.IsUnspecified() || addr.IsMulticast() || addr.IsLoopback() {
+			return nil, fmt.Errorf("IPv4StaticAddresses[%d] %q is not a valid unicast IPv4 address", i, *entry.Address)
+		}
+		mask, err := netip.ParseAddr(*entry.SubnetMask)
+		if err != nil || !mask.Is4() || !contiguousMask(mask) {
+			return nil, fmt.Errorf("IPv4StaticAddresses[%d] %q is not a valid subnet mask", i, *entry.SubnetMask)
+		}
+		if entry.Gateway != "" {
+			if gateway, err := netip.ParseAddr(entry.Gateway); err != nil || !gateway.Is4() {
+				return nil, fmt.Errorf("IPv4StaticAddresses[%d] Gateway %q is not a valid IPv4 address", i, entry.Gateway)
+			}
+		}
+		addresses = append(addresses, models.IPv4Address{Address: addr.String(), SubnetMask: mask.String(), Gateway: entry.Gateway})
+	}
+	return addresses, nil
+}
+
+// contiguousMask reports whether an IPv4 subnet mask is a run of one bits
+// followed only by zero bits
+func contiguousMask(mask netip.Addr) bool {
+	b := mask.As4()
+	m := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	return m != 0 && ^m&(^m+1) == 0
+}
+
+// parseIPv6StaticAddresses parses a PATCH of IPv6StaticAddresses, each entry
+// holding an Address and a PrefixLength of 1 to 128
+func parseIPv6StaticAddresses(raw json.RawMessage) ([]models.IPv6Address, error) {
+	var entries []struct {
+		Address      *string
+		PrefixLength *int
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("IPv6StaticAddresses must be an array of objects with Address and PrefixLength")
+	}
+
+	addresses := make([]models.IPv6Address, 0, len(entries))
+	for i, entry := range entries {
+		if entry.Address == nil || entry.PrefixLength == nil {
+			return nil, fmt.Errorf("IPv6StaticAddresses[%d] requires Address and PrefixLength", i)
+		}
+		addr, err := parseIPv6(*entry.Address)
+		if err != nil {
+			return nil, fmt.Errorf("IPv6StaticAddresses[%d] %s", i, err)
+		}
+		if addr.IsUnspecified() || addr.IsMulticast() || addr.IsLoopback() {
+			return nil, fmt.Errorf("IPv6StaticAddresses[%d] %s is not a unicast address", i, *entry.Address)
+		}
+		if *entry.PrefixLength < 1 || *entry.PrefixLength > 128 {
+			return nil, fmt.Errorf("IPv6StaticAddresses[%d] PrefixLength %d is not between 1 and 128", i, *entry.PrefixLength)
+		}
+		addresses = append(addresses, models.IPv6Address{Address: addr.String(), PrefixLength: *entry.PrefixLength})
+	}
+	return addresses, nil
+}
+
+// parseIPv6 parses an IPv6 address, rejecting IPv4 and zoned addresses
+func parseIPv6(s string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is6() || addr.Is4In6() || addr.Zone() != "" {
+		return netip.Addr{}, fmt.Errorf("%q is not a valid IPv6 address", s)
+	}
+	return addr, nil
+}
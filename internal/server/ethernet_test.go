@@ -0,0 +1,212 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestEthernetInterfaceIPv6Patch(t *testing.T) {
+	managerStore.Put("nic-ipv6", models.NewManager("nic-ipv6"))
+	t.Cleanup(func() {
+		managerStore.Delete("nic-ipv6")
+		ethernetMutex.Lock()
+		delete(ethernetInterfaces, "nic-ipv6")
+		ethernetMutex.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	path := "/redfish/v1/Managers/nic-ipv6/EthernetInterfaces/eth0"
+	send := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("PATCH", `{"IPv6StaticAddresses": [{"Address": "2001:DB8::10", "PrefixLength": 64}], "IPv6DefaultGateway": "2001:db8::1"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The assignment persists and shows among the interface's addresses
+	var nic models.EthernetInterface
+	json.NewDecoder(send("GET", "").Body).Decode(&nic)
+	if len(nic.IPv6StaticAddresses) != 1 || nic.IPv6StaticAddresses[0].Address != "2001:db8::10" || nic.IPv6StaticAddresses[0].PrefixLength != 64 {
+		t.Errorf("Expected static address 2001:db8::10/64, got %+v", nic.IPv6StaticAddresses)
+	}
+	if nic.IPv6DefaultGateway != "2001:db8::1" {
+		t.Errorf("Expected IPv6DefaultGateway 2001:db8::1, got %q", nic.IPv6DefaultGateway)
+	}
+	static := 0
+	for _, address := range nic.IPv6Addresses {
+		if address.AddressOrigin == "Static" && address.Address == "2001:db8::10" {
+			static++
+		}
+	}
+	if static != 1 || len(nic.IPv6Addresses) != 2 {
+		t.Errorf("Expected the link-local and static addresses, got %+v", nic.IPv6Addresses)
+	}
+
+	for _, body := range []string{
+		`{"IPv6StaticAddresses": [{"Address": "2001:db8::10", "PrefixLength": 129}]}`,
+		`{"IPv6StaticAddresses": [{"Address": "2001:db8::10", "PrefixLength": 0}]}`,
+		`{"IPv6StaticAddresses": [{"Address": "2001:db8::zz", "PrefixLength": 64}]}`,
+		`{"IPv6StaticAddresses": [{"Address": "192.0.2.1", "PrefixLength": 64}]}`,
+		`{"IPv6StaticAddresses": [{"Address": "2001:db8::10"}]}`,
+		`{"IPv6DefaultGateway": "not-an-address"}`,
+	} {
+		w := send("PATCH", body)
+		var errorResponse models.RedfishError
+		json.NewDecoder(w.Body).Decode(&errorResponse)
+		if w.Code != http.StatusBadRequest || errorResponse.Error.Code != baseRegistry.MessageID("PropertyValueFormatError") {
+			t.Errorf("Expected PropertyValueFormatError for %s, got %d %s", body, w.Code, errorResponse.Error.Code)
+		}
+	}
+
+	// Rejected PATCHes leave the configuration unchanged
+	json.NewDecoder(send("GET", "").Body).Decode(&nic)
+	if len(nic.IPv6StaticAddresses) != 1 || nic.IPv6StaticAddresses[0].PrefixLength != 64 {
+		t.Errorf("Expected the earlier assignment to be kept, got %+v", nic.IPv6StaticAddresses)
+	}
+}
+
+func TestEthernetInterfaceAddressOrigin(t *testing.T) {
+	managerStore.Put("nic-ipv4", models.NewManager("nic-ipv4"))
+	t.Cleanup(func() {
+		managerStore.Delete("nic-ipv4")
+		ethernetMutex.Lock()
+		delete(ethernetInterfaces, "nic-ipv4")
+		ethernetMutex.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	path := "/redfish/v1/Managers/nic-ipv4/EthernetInterfaces/eth0"
+	send := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// Static addresses contradict DHCP, whether it is left enabled, enabled in
+	// the same request, or the address claims a DHCP origin
+	for _, body := range []string{
+		`{"IPv4StaticAddresses": [{"Address": "192.0.2.50", "SubnetMask": "255.255.255.0"}]}`,
+		`{"DHCPv4": {"DHCPEnabled": true}, "IPv4StaticAddresses": [{"Address": "192.0.2.50", "SubnetMask": "255.255.255.0"}]}`,
+		`{"DHCPv4": {"DHCPEnabled": false}, "IPv4StaticAddresses": [{"Address": "192.0.2.50", "SubnetMask": "255.255.255.0", "AddressOrigin": "DHCP"}]}`,
+	} {
+		w := send("PATCH", body)
+		var errorResponse models.RedfishError
+		json.NewDecoder(w.Body).Decode(&errorResponse)
+		if w.Code != http.StatusConflict || errorResponse.Error.Code != baseRegistry.MessageID("PropertyValueConflict") {
+			t.Errorf("Expected PropertyValueConflict for %s, got %d %s", body, w.Code, errorResponse.Error.Code)
+		}
+	}
+
+	// A consistent static configuration is applied
+	w := send("PATCH", `{"DHCPv4": {"DHCPEnabled": false}, "IPv4StaticAddresses": [{"Address": "192.0.2.50", "SubnetMask": "255.255.255.0", "Gateway": "192.0.2.1", "AddressOrigin": "Static"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var nic models.EthernetInterface
+	json.NewDecoder(send("GET", "").Body).Decode(&nic)
+	if nic.DHCPv4.DHCPEnabled || len(nic.IPv4Addresses) != 1 || nic.IPv4Addresses[0].Address != "192.0.2.50" || nic.IPv4Addresses[0].AddressOrigin != "Static" {
+		t.Errorf("Expected the static address in use with DHCP off, got %+v, DHCP %v", nic.IPv4Addresses, nic.DHCPv4.DHCPEnabled)
+	}
+
+	// Enabling DHCP again requires clearing the static addresses
+	if w := send("PATCH", `{"DHCPv4": {"DHCPEnabled": true}}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected enabling DHCP over static addresses to conflict, got %d", w.Code)
+	}
+	if w := send("PATCH", `{"DHCPv4": {"DHCPEnabled": true}, "IPv4StaticAddresses": []}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	json.NewDecoder(send("GET", "").Body).Decode(&nic)
+	if !nic.DHCPv4.DHCPEnabled || len(nic.IPv4Addresses) != 1 || nic.IPv4Addresses[0].AddressOrigin != "DHCP" {
+		t.Errorf("Expected the DHCP lease back, got %+v", nic.IPv4Addresses)
+	}
+
+	if w := send("PATCH", `{"DHCPv4": {"DHCPEnabled": false}, "IPv4StaticAddresses": [{"Address": "192.0.2.50", "SubnetMask": "255.0.255.0"}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected a non-contiguous subnet mask to be rejected, got %d", w.Code)
+	}
+}
+
+func TestEthernetInterfaceConcurrentPatch(t *testing.T) {
+	managerStore.Put("nic-concurrent", models.NewManager("nic-concurrent"))
+	t.Cleanup(func() {
+		managerStore.Delete("nic-concurrent")
+		ethernetMutex.Lock()
+		delete(ethernetInterfaces, "nic-concurrent")
+		ethernetMutex.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	patch := func(body string) {
+		req := httptest.NewRequest("PATCH", "/redfish/v1/Managers/nic-concurrent/EthernetInterfaces/eth0", strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("PATCH %s: expected status 200, got %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	// Concurrent PATCHes of different properties both take effect
+	for i := range 20 {
+		gateway, dhcp := []string{"2001:db8::1", "2001:db8::2"}[i%2], i%2 == 0
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); patch(`{"IPv6DefaultGateway": "` + gateway + `"}`) }()
+		go func() { defer wg.Done(); patch(`{"DHCPv4": {"DHCPEnabled": ` + strconv.FormatBool(dhcp) + `}}`) }()
+		wg.Wait()
+
+		nic, _ := ethernetInterface("nic-concurrent", "eth0")
+		if nic.IPv6DefaultGateway != gateway || nic.DHCPv4.DHCPEnabled != dhcp {
+			t.Fatalf("Round %d: expected gateway %s and DHCP %v, got %s and %v", i, gateway, dhcp, nic.IPv6DefaultGateway, nic.DHCPv4.DHCPEnabled)
+		}
+	}
+}
+
+func TestEthernetInterfaceConditionalGet(t *testing.T) {
+	managerStore.Put("nic-etag", models.NewManager("nic-etag"))
+	t.Cleanup(func() {
+		managerStore.Delete("nic-etag")
+		ethernetMutex.Lock()
+		delete(ethernetInterfaces, "nic-etag")
+		ethernetMutex.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, path := range []string{"/redfish/v1/Managers/nic-etag/EthernetInterfaces", "/redfish/v1/Managers/nic-etag/EthernetInterfaces/eth0"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		etag := w.Header().Get("ETag")
+		if w.Code != http.StatusOK || etag == "" || w.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("Expected a JSON 200 with an ETag for %s, got %d %q", path, w.Code, etag)
+		}
+
+		req = httptest.NewRequest("GET", path, nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("Expected status 304 without a body for %s, got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// Bounds on a subscription's EventBatching settings
+const (
+	maxEventBatchSize       = 1000
+	maxEventFlushIntervalMs = 60000
+)
+
+// eventBatcher collects the event records bound for one subscription until a
+// batch is full or its flush interval ends. Flushed batches are sent in
+// order by a goroutine of the batcher's own rather than on the event delivery
+// pool, so a slow subscriber never holds pool workers that other
+// subscriptions' deliveries need.
+type eventBatcher struct {
+	mutex        sync.Mutex
+	subscription *models.EventSubscription
+	pending      []models.EventRecord
+	timer        *time.Timer
+
+	// ready holds flushed batches waiting to be sent; delivering is set
+	// while the batcher's goroutine is sending them
+	ready      []*models.Event
+	delivering bool
+}
+
+var (
+	eventBatchersMutex sync.Mutex
+	eventBatchers      = make(map[string]*eventBatcher)
+)
+
+// validateEventBatching checks a subscription's requested batching settings
+func validateEventBatching(batching *models.EventBatching) error {
+	if batching == nil {
+		return nil
+	}
+	if batching.MaxEvents < 1 || batching.MaxEvents > maxEventBatchSize {
+		return fmt.Errorf("EventBatching MaxEvents %d is out of range 1-%d", batching.MaxEvents, maxEventBatchSize)
+	}
+	if batching.FlushIntervalMs < 1 || batching.FlushIntervalMs > maxEventFlushIntervalMs {
+		return fmt.Errorf("EventBatching FlushIntervalMs %d is out of range 1-%d", batching.FlushIntervalMs, maxEventFlushIntervalMs)
+	}
+	return nil
+}
+
+// batchEvent queues an event's records for a subscription that batches its
+// events, flushing the batch once it is full
+func batchEvent(subscription *models.EventSubscription, event *models.Event) {
+	eventBatchersMutex.Lock()
+	batcher, ok := eventBatchers[subscription.ID]
+	if !ok {
+		batcher = &eventBatcher{}
+		eventBatchers[subscription.ID] = batcher
+	}
+	eventBatchersMutex.Unlock()
+
+	batching := subscription.Batching()
+
+	batcher.mutex.Lock()
+	defer batcher.mutex.Unlock()
+
+	batcher.subscription = subscription
+	batcher.pending = append(batcher.pending, event.Events...)
+	if len(batcher.pending) >= batching.MaxEvents {
+		batcher.flushLocked()
+		return
+	}
+	if batcher.timer == nil {
+		batcher.timer = time.AfterFunc(time.Duration(batching.FlushIntervalMs)*time.Millisecond, func() {
+			batcher.mutex.Lock()
+			defer batcher.mutex.Unlock()
+			batcher.flushLocked()
+		})
+	}
+}
+
+// flushLocked queues the pending records as one Event payload, starting the
+// batcher's delivery goroutine if it is not running. The caller must hold
+// b.mutex.
+func (b *eventBatcher) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+
+	records := b.pending
+	b.pending = nil
+	for i := range records {
+		records[i].MemberId = strconv.Itoa(i)
+	}
+	b.ready = append(b.ready, models.NewEvent("", records))
+	if !b.delivering {
+		b.delivering = true
+		go b.deliverReady()
+	}
+}
+
+// deliverReady sends the flushed batches one at a time until none are left
+func (b *eventBatcher) deliverReady() {
+	for {
+		b.mutex.Lock()
+		if len(b.ready) == 0 {
+			b.delivering = false
+			b.mutex.Unlock()
+			return
+		}
+		event := b.ready[0]
+		b.ready = b.ready[1:]
+		subscription := b.subscription
+		b.mutex.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), eventDeliveryTimeout)
+		if err := deliverEvent(ctx, subscription, event); err != nil {
+			log.Printf("Event delivery to subscription %s failed: %v", subscription.ID, err)
+		}
+		cancel()
+	}
+}
+
+// discardEventBatch drops the records and batches still waiting for a
+// subscription that was deleted
+func discardEventBatch(subscriptionID string) {
+	eventBatchersMutex.Lock()
+	batcher, ok := eventBatchers[subscriptionID]
+	delete(eventBatchers, subscriptionID)
+	eventBatchersMutex.Unlock()
+	if !ok {
+		return
+	}
+
+	batcher.mutex.Lock()
+	defer batcher.mutex.Unlock()
+	if batcher.timer != nil {
+		batcher.timer.Stop()
+		batcher.timer = nil
+	}
+	batcher.pending = nil
+	batcher.ready = nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+func TestEventBatching(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	allowLoopbackDestinations(t)
+
+	received := make(chan models.Event, 10)
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer destination.Close()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(fmt.Sprintf(`{"Destination": %q, "Oem": {"Contoso": {"EventBatching": {"MaxEvents": 0, "FlushIntervalMs": 100}}}}`, destination.URL)))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400 for an empty batch size, got %d", w.Code)
+	}
+
+	subscription := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q, "Oem": {"Contoso": {"EventBatching": {"MaxEvents": 10, "FlushIntervalMs": 100}}}}`, destination.URL))
+	t.Cleanup(func() { deleteSubscription(subscription.ID, nil) })
+
+	s := &Server{}
+	for i := range 3 {
+		s.SendEvent(models.NewEvent("", []models.EventRecord{{EventId: fmt.Sprint(i), MessageId: "Base.1.0.Success", MemberId: "0"}}))
+	}
+
+	select {
+	case event := <-received:
+		if len(event.Events) != 3 {
+			t.Fatalf("Expected the three events in one payload, got %d", len(event.Events))
+		}
+		for i, record := range event.Events {
+			if record.EventId != fmt.Sprint(i) || record.MemberId != fmt.Sprint(i) {
+				t.Errorf("Unexpected record %d: %+v", i, record)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the batch to be delivered after the flush interval")
+	}
+
+	select {
+	case event := <-received:
+		t.Errorf("Expected a single delivery, also got %+v", event)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSlowBatchingSubscriberDoesNotHoldDeliveryPool(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	allowLoopbackDestinations(t)
+	setEventDeliveryPool(config.EventsConfig{DeliveryWorkers: 2})
+	t.Cleanup(func() { setEventDeliveryPool(config.EventsConfig{}) })
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	received := make(chan models.Event, 10)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer fast.Close()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	// Every event fills a batch, and the destination never answers
+	batching := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q, "Oem": {"Contoso": {"EventBatching": {"MaxEvents": 1, "FlushIntervalMs": 100}}}}`, slow.URL))
+	t.Cleanup(func() { deleteSubscription(batching.ID, nil) })
+
+	s := &Server{}
+	for i := range 3 {
+		s.SendEvent(models.NewEvent("", []models.EventRecord{{EventId: fmt.Sprint(i), MessageId: "Base.1.0.Success", MemberId: "0"}}))
+	}
+
+	other := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q}`, fast.URL))
+	t.Cleanup(func() { deleteSubscription(other.ID, nil) })
+	s.SendEvent(models.NewEvent("", []models.EventRecord{{EventId: "3", MessageId: "Base.1.0.Success", MemberId: "0"}}))
+
+	select {
+	case event := <-received:
+		if len(event.Events) != 1 || event.Events[0].EventId != "3" {
+			t.Errorf("Expected event 3, got %+v", event.Events)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the other subscriber's delivery while the batching subscriber is stalled")
+	}
+}
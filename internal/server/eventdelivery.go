@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// eventDeliveryTimeout bounds a single POST to an event destination
+const eventDeliveryTimeout = 10 * time.Second
+
+// shutdownEventTimeout bounds the deliveries of the ServiceShuttingDown event,
+// so unreachable subscribers cannot stall shutdown
+const shutdownEventTimeout = 2 * time.Second
+
+// reservedDeliveryHeaders are headers a subscription may not set on event
+// delivery: hop-by-hop headers, which only concern a single connection, and
+// those the HTTP client manages itself. Keys are canonical header names.
+var reservedDeliveryHeaders = map[string]bool{
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+	"Host":                true,
+	"Content-Length":      true,
+}
+
+// validateHttpHeaders checks the custom headers a subscriber asked to have
+// sent with each event: names must be HTTP tokens, values must not contain
+// control characters, and reserved headers cannot be set
+func validateHttpHeaders(headers []models.HttpHeader) error {
+	for _, header := range headers {
+		if header.Name == "" || strings.IndexFunc(header.Name, func(r rune) bool { return !isTokenChar(r) }) >= 0 {
+			return fmt.Errorf("HttpHeaders name %q is not a valid header name", header.Name)
+		}
+		if reservedDeliveryHeaders[http.CanonicalHeaderKey(header.Name)] {
+			return fmt.Errorf("HttpHeaders cannot set the %s header", http.CanonicalHeaderKey(header.Name))
+		}
+		if strings.IndexFunc(header.Value, func(r rune) bool { return r < ' ' && r != '\t' || r == 0x7f }) >= 0 {
+			return fmt.Errorf("HttpHeaders value of %s contains control characters", header.Name)
+		}
+	}
+	return nil
+}
+
+// isTokenChar reports whether r may appear in an HTTP token such as a header name
+func isTokenChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
+
+// subscriptionResponse returns a subscription as clients see it. HttpHeaders
+// typically carry credentials for the destination, so, as the Redfish schema
+// requires, they are never returned.
+func subscriptionResponse(subscription *models.EventSubscription) *models.EventSubscription {
+	response := *subscription
+	response.HttpHeaders = nil
+	return &response
+}
+
+// deliveryClient builds the HTTP client used to POST events to a
+// subscription's destination. Certificates are verified unless the subscriber
+// explicitly set VerifyCertificate to false. When certificates are pinned for
+// the subscription, only those are trusted; otherwise the system roots are.
+// Connections only go to addresses the destination policy permits, and
+// redirects are not followed: the policy was checked against the subscribed
+// Destination, and a redirect would resend the event and its HttpHeaders to
+// a host the subscriber never named.
+func deliveryClient(subscription *models.EventSubscription, certificates []*models.Certificate) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if subscription.VerifyCertificate != nil && !*subscription.VerifyCertificate {
+		tlsConfig.InsecureSkipVerify = true
+	} else if len(certificates) > 0 {
+		pool := x509.NewCertPool()
+		for _, certificate := range certificates {
+			if !pool.AppendCertsFromPEM([]byte(certificate.CertificateString)) {
+				return nil, fmt.Errorf("certificate %s of subscription %s is not valid PEM", certificate.ID, subscription.ID)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	var host string
+	if u, err := url.Parse(subscription.Destination); err == nil {
+		host = u.Hostname()
+	}
+	dialer := &net.Dialer{Control: destinationDialControl(host)}
+
+	return &http.Client{
+		Timeout:   eventDeliveryTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// deliverEvent POSTs an event to a subscription's destination, carrying the
+// subscription's Context and custom HTTP headers
+func deliverEvent(ctx context.Context, subscription *models.EventSubscription, event *models.Event) error {
+	certificates, _ := getSubscriptionCertificates(subscription.ID)
+	client, err := deliveryClient(subscription, certificates)
+	if err != nil {
+		return err
+	}
+	defer client.CloseIdleConnections()
+
+	payload := *event
+	payload.Context = subscription.Context
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", subscription.Destination, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid destination %s: %w", subscription.Destination, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range subscription.HttpHeaders {
+		req.Header.Set(header.Name, header.Value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver event to %s: %w", subscription.Destination, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("event destination %s responded with status %d", subscription.Destination, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+// createTestSubscription subscribes destination through the API and returns
+// the stored subscription
+func createTestSubscription(t *testing.T, mux *http.ServeMux, body string) *models.EventSubscription {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created models.EventSubscription
+	json.Unmarshal(w.Body.Bytes(), &created)
+
+	subscription, ok := getSubscription(created.ID)
+	if !ok {
+		t.Fatalf("Subscription %s was not stored", created.ID)
+	}
+	return subscription
+}
+
+func TestEventDeliveryVerifiesCertificate(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	allowLoopbackDestinations(t)
+
+	received := make(chan models.Event, 3)
+	destination := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer destination.Close()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	event := models.NewEvent("", []models.EventRecord{{EventId: "1", MessageId: "Base.1.0.Success", MemberId: "0"}})
+
+	// Verification is on by default, so a self-signed destination is refused
+	subscription := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q, "Context": "pinned"}`, destination.URL))
+	if subscription.VerifyCertificate == nil || !*subscription.VerifyCertificate {
+		t.Fatalf("Expected VerifyCertificate to default to true, got %v", subscription.VerifyCertificate)
+	}
+	if err := deliverEvent(context.Background(), subscription, event); err == nil {
+		t.Fatal("Expected delivery to a self-signed destination to fail")
+	}
+
+	explicit := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q, "VerifyCertificate": true}`, destination.URL))
+	if err := deliverEvent(context.Background(), explicit, event); err == nil {
+		t.Fatal("Expected delivery with VerifyCertificate true to fail")
+	}
+
+	// Pinning the destination's certificate makes it trusted
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: destination.Certificate().Raw}))
+	body, _ := json.Marshal(map[string]string{"CertificateString": certificate, "CertificateType": "PEM"})
+	req := httptest.NewRequest("POST", string(subscription.Certificates.ODataID), strings.NewReader(string(body)))
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var pinned models.Certificate
+	json.Unmarshal(w.Body.Bytes(), &pinned)
+	if w.Header().Get("Location") != string(subscription.Certificates.ODataID)+"/1" || pinned.ValidNotAfter == "" {
+		t.Errorf("Unexpected pinned certificate at %s: %+v", w.Header().Get("Location"), pinned)
+	}
+
+	if err := deliverEvent(context.Background(), subscription, event); err != nil {
+		t.Fatalf("Expected delivery with a pinned certificate to succeed, got %v", err)
+	}
+	if got := <-received; got.Context != "pinned" || len(got.Events) != 1 {
+		t.Errorf("Unexpected delivered event: %+v", got)
+	}
+
+	// Only an explicit false skips verification
+	insecure := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q, "VerifyCertificate": false}`, destination.URL))
+	if err := deliverEvent(context.Background(), insecure, event); err != nil {
+		t.Fatalf("Expected delivery with VerifyCertificate false to succeed, got %v", err)
+	}
+}
+
+func TestSubscriptionCertificateRejectsInvalidPEM(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	subscription := createTestSubscription(t, mux, `{"Destination": "https://listener.example.com/events"}`)
+
+	req := httptest.NewRequest("POST", string(subscription.Certificates.ODataID), strings.NewReader(`{"CertificateString": "not a certificate"}`))
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", string(subscription.Certificates.ODataID), nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var collection models.CertificateCollection
+	json.Unmarshal(w.Body.Bytes(), &collection)
+	if w.Code != http.StatusOK || collection.MembersODataCount != 0 {
+		t.Errorf("Expected an empty certificate collection, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSubscriptionCertificatesRequireConfigureManager(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	subscription := createTestSubscription(t, mux, `{"Destination": "https://listener.example.com/events"}`)
+	certificateURI := string(subscription.Certificates.ODataID) + "/1"
+
+	send := func(method, path, body string, ctx func(*http.Request) *http.Request) *httptest.ResponseRecorder {
+		req := ctx(httptest.NewRequest(method, path, strings.NewReader(body)))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	reader := func(req *http.Request) *http.Request {
+		return req.WithContext(auth.SetUserContextWithRole(req.Context(), "reader", "Bearer", "ReadOnly"))
+	}
+	admin := func(req *http.Request) *http.Request {
+		return req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+	}
+
+	destination := httptest.NewTLSServer(http.NotFoundHandler())
+	defer destination.Close()
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: destination.Certificate().Raw}))
+	body, _ := json.Marshal(map[string]string{"CertificateString": certificate})
+
+	if w := send("POST", string(subscription.Certificates.ODataID), string(body), reader); w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a ReadOnly user pinning a certificate, got %d", w.Code)
+	}
+	if certificates, _ := getSubscriptionCertificates(subscription.ID); len(certificates) != 0 {
+		t.Fatalf("Expected no certificate to be pinned, got %d", len(certificates))
+	}
+
+	for range 2 {
+		if w := send("POST", string(subscription.Certificates.ODataID), string(body), admin); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	if w := send("DELETE", certificateURI, "", reader); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a ReadOnly user unpinning a certificate, got %d", w.Code)
+	}
+	if w := send("GET", certificateURI, "", reader); w.Code != http.StatusOK {
+		t.Errorf("Expected the certificate to remain pinned, got %d", w.Code)
+	}
+
+	if w := send("DELETE", certificateURI, "", admin); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send("GET", certificateURI, "", reader); w.Code != http.StatusNotFound {
+		t.Errorf("Expected the unpinned certificate to be gone, got %d", w.Code)
+	}
+
+	// Ids stay unique after a certificate is unpinned
+	w := send("POST", string(subscription.Certificates.ODataID), string(body), admin)
+	if location := w.Header().Get("Location"); location != string(subscription.Certificates.ODataID)+"/3" {
+		t.Errorf("Expected the next certificate at Id 3, got %q", location)
+	}
+}
+
+func TestEventDeliveryCustomHeaders(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	allowLoopbackDestinations(t)
+
+	received := make(chan http.Header, 1)
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer destination.Close()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, header := range []string{`{"name": "Connection", "value": "close"}`, `{"name": "X Token", "value": "secret"}`} {
+		req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(fmt.Sprintf(`{"Destination": %q, "HttpHeaders": [%s]}`, destination.URL, header)))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for header %s, got %d", header, w.Code)
+		}
+	}
+
+	subscription := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q, "HttpHeaders": [{"name": "X-Auth-Token", "value": "s3cret"}]}`, destination.URL))
+	event := models.NewEvent("", []models.EventRecord{{EventId: "1", MessageId: "Base.1.0.Success", MemberId: "0"}})
+	if err := deliverEvent(context.Background(), subscription, event); err != nil {
+		t.Fatalf("Expected delivery to succeed, got %v", err)
+	}
+	if got := (<-received).Get("X-Auth-Token"); got != "s3cret" {
+		t.Errorf("Expected the destination to receive X-Auth-Token, got %q", got)
+	}
+
+	// The header value is a credential and is never echoed back
+	req := httptest.NewRequest("GET", string(subscription.ODataID), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "s3cret") || strings.Contains(w.Body.String(), "HttpHeaders") {
+		t.Errorf("Expected HttpHeaders to be redacted, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestEventDeliveryDoesNotFollowRedirects(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	allowLoopbackDestinations(t)
+
+	redirected := make(chan http.Header, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected <- r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer destination.Close()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	subscription := createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q, "HttpHeaders": [{"name": "X-Auth-Token", "value": "s3cret"}]}`, destination.URL))
+	event := models.NewEvent("", []models.EventRecord{{EventId: "1", MessageId: "Base.1.0.Success", MemberId: "0"}})
+	if err := deliverEvent(context.Background(), subscription, event); err == nil || !strings.Contains(err.Error(), "307") {
+		t.Errorf("Expected delivery to fail with the redirect status, got %v", err)
+	}
+	select {
+	case header := <-redirected:
+		t.Errorf("Expected the redirect not to be followed, but the target received X-Auth-Token %q", header.Get("X-Auth-Token"))
+	default:
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+// deliveryQueue runs outbound event deliveries on a fixed pool of workers,
+// so a burst of events cannot start a goroutine per delivery. Deliveries
+// wait in a bounded queue; when it is full, the policy decides whether the
+// new delivery is dropped, the longest waiting one is dropped to make room,
+// or the publisher blocks until a worker frees a slot.
+type deliveryQueue struct {
+	// mutex is held for reading while a delivery is queued, so close
+	// cannot close the channel under a blocked sender
+	mutex  sync.RWMutex
+	closed bool
+	jobs   chan func()
+	policy string
+
+	// dropped counts the deliveries discarded because the queue was full
+	dropped atomic.Int64
+}
+
+// eventDeliveries is the pool outbound deliveries run on. New replaces it
+// with one sized from the configuration.
+var eventDeliveries atomic.Pointer[deliveryQueue]
+
+func init() {
+	setEventDeliveryPool(config.EventsConfig{})
+}
+
+// setEventDeliveryPool replaces the delivery pool with one sized from the
+// configuration, with zero sizes and an empty policy selecting the defaults.
+// Deliveries already queued on the old pool still run.
+func setEventDeliveryPool(events config.EventsConfig) {
+	workers := events.DeliveryWorkers
+	if workers == 0 {
+		workers = config.DefaultDeliveryWorkers
+	}
+	size := events.DeliveryQueueSize
+	if size == 0 {
+		size = config.DefaultDeliveryQueueSize
+	}
+	policy := events.DeliveryQueueFullPolicy
+	if policy == "" {
+		policy = "DropNewest"
+	}
+	if old := eventDeliveries.Swap(newDeliveryQueue(workers, size, policy)); old != nil {
+		old.close()
+	}
+}
+
+// newDeliveryQueue starts a pool of workers taking deliveries from a queue
+// of the given size
+func newDeliveryQueue(workers, size int, policy string) *deliveryQueue {
+	q := &deliveryQueue{jobs: make(chan func(), size), policy: policy}
+	for range workers {
+		go func() {
+			for job := range q.jobs {
+				job()
+			}
+		}()
+	}
+	return q
+}
+
+// enqueueDelivery hands a delivery to the current pool
+func enqueueDelivery(job func()) {
+	eventDeliveries.Load().enqueue(job)
+}
+
+// enqueue queues a delivery, applying the queue-full policy when there is
+// no room. It reports false if this delivery was dropped.
+func (q *deliveryQueue) enqueue(job func()) bool {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	if q.closed {
+		// The pool was replaced after the caller picked it up
+		return eventDeliveries.Load().enqueue(job)
+	}
+
+	if q.policy == "Block" {
+		q.jobs <- job
+		return true
+	}
+	for {
+		select {
+		case q.jobs <- job:
+			return true
+		default:
+		}
+		if q.policy != "DropOldest" {
+			q.dropped.Add(1)
+			log.Printf("WARNING: event delivery queue is full (%d waiting); dropped the newest delivery", cap(q.jobs))
+			return false
+		}
+		select {
+		case <-q.jobs:
+			q.dropped.Add(1)
+			log.Printf("WARNING: event delivery queue is full (%d waiting); dropped the oldest delivery", cap(q.jobs))
+		default:
+		}
+	}
+}
+
+// depth returns the number of deliveries waiting for a worker
+func (q *deliveryQueue) depth() int {
+	return len(q.jobs)
+}
+
+// close stops the workers once they have run the deliveries still queued
+func (q *deliveryQueue) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+}
+
+// EventDeliveryQueueDepth reports how many outbound event deliveries are
+// waiting for a delivery worker, for monitoring backpressure
+func (s *Server) EventDeliveryQueueDepth() int {
+	return eventDeliveries.Load().depth()
+}
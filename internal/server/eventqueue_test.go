@@ -0,0 +1,124 @@
+package server
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeliveryQueueBoundsBursts(t *testing.T) {
+	tests := []struct {
+		policy  string
+		dropped int64
+		ran     []int // the jobs expected to run, in the order queued
+	}{
+		// Two jobs occupy the workers and three wait; the rest are dropped
+		{"DropNewest", 95, []int{0, 1, 2, 3, 4}},
+		{"DropOldest", 95, []int{0, 1, 97, 98, 99}},
+	}
+	for _, tt := range tests {
+		gate := make(chan struct{})
+		queue := newDeliveryQueue(2, 3, tt.policy)
+		t.Cleanup(queue.close)
+
+		var mutex sync.Mutex
+		var ran []int
+		var started sync.WaitGroup
+		started.Add(2)
+		before := runtime.NumGoroutine()
+		for i := range 100 {
+			queue.enqueue(func() {
+				if i < 2 {
+					started.Done()
+				}
+				<-gate
+				mutex.Lock()
+				ran = append(ran, i)
+				mutex.Unlock()
+			})
+			if i == 1 {
+				// Let the workers pick up the first two before the burst
+				started.Wait()
+			}
+		}
+
+		if grown := runtime.NumGoroutine() - before; grown > 2 {
+			t.Errorf("%s: expected no goroutine per delivery, %d were started", tt.policy, grown)
+		}
+		if depth := queue.depth(); depth != 3 {
+			t.Errorf("%s: expected 3 deliveries waiting, got %d", tt.policy, depth)
+		}
+		if dropped := queue.dropped.Load(); dropped != tt.dropped {
+			t.Errorf("%s: expected %d deliveries dropped, got %d", tt.policy, tt.dropped, dropped)
+		}
+
+		close(gate)
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			mutex.Lock()
+			done := len(ran) == len(tt.ran)
+			mutex.Unlock()
+			if done || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		mutex.Lock()
+		got := make(map[int]bool)
+		for _, i := range ran {
+			got[i] = true
+		}
+		mutex.Unlock()
+		for _, i := range tt.ran {
+			if !got[i] {
+				t.Errorf("%s: expected delivery %d to run, ran %v", tt.policy, i, ran)
+			}
+		}
+		if len(got) != len(tt.ran) {
+			t.Errorf("%s: expected %d deliveries to run, ran %v", tt.policy, len(tt.ran), ran)
+		}
+	}
+}
+
+func TestDeliveryQueueBlockPolicy(t *testing.T) {
+	gate := make(chan struct{})
+	queue := newDeliveryQueue(1, 1, "Block")
+	t.Cleanup(queue.close)
+
+	var ran atomic.Int32
+	job := func() {
+		<-gate
+		ran.Add(1)
+	}
+	queue.enqueue(job)
+	queue.enqueue(job)
+
+	// With the worker busy and the queue full, the publisher waits for room
+	// instead of dropping the delivery
+	queued := make(chan bool)
+	go func() { queued <- queue.enqueue(job) }()
+	select {
+	case <-queued:
+		t.Fatal("Expected enqueue to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate)
+	select {
+	case ok := <-queued:
+		if !ok {
+			t.Error("Expected the blocked delivery to be queued")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected enqueue to finish once the queue drained")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for ran.Load() != 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if ran.Load() != 3 || queue.dropped.Load() != 0 {
+		t.Errorf("Expected all 3 deliveries to run and none dropped, got %d run and %d dropped", ran.Load(), queue.dropped.Load())
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+// maxExpandLevels is the deepest $expand a client may request. New sets it
+// from the configuration; the ServiceRoot advertises the same value, so the
+// enforced and advertised limits cannot drift.
+var maxExpandLevels atomic.Int64
+
+func init() {
+	maxExpandLevels.Store(config.DefaultMaxExpandLevels)
+}
+
+// setMaxExpandLevels applies the configured $expand limit, with zero
+// selecting the default
+func setMaxExpandLevels(levels int) {
+	if levels == 0 {
+		levels = config.DefaultMaxExpandLevels
+	}
+	maxExpandLevels.Store(int64(levels))
+}
+
+// parseExpand parses an $expand value such as "*($levels=2)" or
+// "Chassis,ManagedBy" into the expanded items and the requested depth, which
+// defaults to one level. A depth beyond the configured maximum is an error.
+func parseExpand(expand string) ([]string, int, error) {
+	expand = strings.ReplaceAll(expand, " ", "")
+	levels := 1
+
+	if open := strings.Index(expand, "("); open >= 0 {
+		options, ok := strings.CutSuffix(expand[open+1:], ")")
+		if !ok {
+			return nil, 0, queryValueFormatError("invalid $expand parameter: %s", expand)
+		}
+		for _, option := range strings.Split(options, ";") {
+			value, ok := strings.CutPrefix(option, "$levels=")
+			if !ok {
+				return nil, 0, queryValueFormatError("unsupported $expand option: %s", option)
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, 0, queryValueFormatError("invalid $levels in $expand: %s", value)
+			}
+			if n < 1 {
+				return nil, 0, queryOutOfRangeError("$levels in $expand must be at least 1: %s", value)
+			}
+			levels = n
+		}
+		expand = expand[:open]
+	}
+
+	if limit := maxExpandLevels.Load(); int64(levels) > limit {
+		return nil, 0, queryOutOfRangeError("$levels=%d in $expand exceeds the maximum of %d", levels, limit)
+	}
+	return strings.Split(expand, ","), levels, nil
+}
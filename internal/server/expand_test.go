@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+func TestExpandLevelsLimit(t *testing.T) {
+	defer setMaxExpandLevels(0)
+
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0", MaxExpandLevels: 3},
+	}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	root := getServiceRoot(t, s)
+	if root.ProtocolFeaturesSupported == nil || root.ProtocolFeaturesSupported.ExpandQuery.MaxLevels != 3 {
+		t.Fatalf("Expected advertised MaxLevels 3, got %+v", root.ProtocolFeaturesSupported)
+	}
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	tests := []struct {
+		expand string
+		want   int
+	}{
+		{"Chassis", http.StatusOK},
+		{"*($levels=3)", http.StatusOK},
+		{"*($levels=4)", http.StatusBadRequest},
+		{"*($levels=0)", http.StatusBadRequest},
+		{"*($top=1)", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/redfish/v1/Systems/1?$expand="+tt.expand, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("$expand=%s: expected status %d, got %d", tt.expand, tt.want, w.Code)
+		}
+	}
+}
+
+func TestDefaultExpandLevels(t *testing.T) {
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if got := getServiceRoot(t, s).ProtocolFeaturesSupported.ExpandQuery.MaxLevels; got != config.DefaultMaxExpandLevels {
+		t.Errorf("Expected advertised MaxLevels %d, got %d", config.DefaultMaxExpandLevels, got)
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// filterComparison is a single "Property op 'value'" term of a $filter
+type filterComparison struct {
+	property string
+	operator string
+	value    string
+}
+
+// filterExpression is a parsed $filter: comparisons joined by "and", with
+// "or" separating alternatives. Parentheses are not supported.
+type filterExpression struct {
+	alternatives [][]filterComparison
+}
+
+// filterOperators are the comparison operators supported in $filter
+var filterOperators = map[string]bool{"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true}
+
+// parseFilter parses a $filter expression such as
+// "TaskState eq 'Running' or TaskState eq 'New'"
+func parseFilter(filter string) (*filterExpression, error) {
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty $filter expression")
+	}
+
+	expr := &filterExpression{}
+	var current []filterComparison
+	for i := 0; i < len(tokens); {
+		if len(tokens)-i < 3 {
+			return nil, fmt.Errorf("incomplete comparison in $filter: %s", filter)
+		}
+		property, operator, value := tokens[i], strings.ToLower(tokens[i+1]), tokens[i+2]
+		if !filterOperators[operator] {
+			return nil, fmt.Errorf("unsupported $filter operator: %s", tokens[i+1])
+		}
+		current = append(current, filterComparison{property: property, operator: operator, value: strings.Trim(value, "'")})
+		i += 3
+
+		if i == len(tokens) {
+			break
+		}
+		switch strings.ToLower(tokens[i]) {
+		case "and":
+		case "or":
+			expr.alternatives = append(expr.alternatives, current)
+			current = nil
+		default:
+			return nil, fmt.Errorf("expected 'and' or 'or' in $filter, got %s", tokens[i])
+		}
+		i++
+		if i == len(tokens) {
+			return nil, fmt.Errorf("$filter ends with a logical operator: %s", filter)
+		}
+	}
+	expr.alternatives = append(expr.alternatives, current)
+	return expr, nil
+}
+
+// tokenizeFilter splits a $filter on spaces, keeping quoted strings whole
+func tokenizeFilter(filter string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	inQuotes := false
+
+	for _, r := range filter {
+		switch {
+		case r == '\'':
+			inQuotes = !inQuotes
+			token.WriteRune(r)
+		case r == ' ' && !inQuotes:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated string in $filter: %s", filter)
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
+
+// properties returns the property names the expression refers to
+func (f *filterExpression) properties() []string {
+	var names []string
+	for _, alternative := range f.alternatives {
+		for _, comparison := range alternative {
+			names = append(names, comparison.property)
+		}
+	}
+	return names
+}
+
+// matches evaluates the expression, using lookup to read a member's property
+// values. Unknown properties never match.
+func (f *filterExpression) matches(lookup func(property string) (string, bool)) bool {
+	for _, alternative := range f.alternatives {
+		matched := true
+		for _, comparison := range alternative {
+			value, ok := lookup(comparison.property)
+			if !ok || !comparison.matches(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matches applies the comparison to a property value. Ordering comparisons
+// are lexical, which also orders RFC 3339 timestamps in the same zone.
+func (c filterComparison) matches(value string) bool {
+	switch c.operator {
+	case "eq":
+		return value == c.value
+	case "ne":
+		return value != c.value
+	case "gt":
+		return value > c.value
+	case "ge":
+		return value >= c.value
+	case "lt":
+		return value < c.value
+	case "le":
+		return value <= c.value
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package server
+
+import "testing"
+
+func TestParseFilter(t *testing.T) {
+	values := map[string]string{"TaskState": "Running", "Name": "Nightly backup", "StartTime": "2025-01-02T00:00:00Z"}
+	lookup := func(property string) (string, bool) {
+		value, ok := values[property]
+		return value, ok
+	}
+
+	tests := []struct {
+		filter  string
+		want    bool
+		wantErr bool
+	}{
+		{"TaskState eq 'Running'", true, false},
+		{"TaskState eq 'Completed'", false, false},
+		{"TaskState ne 'Completed'", true, false},
+		{"Name eq 'Nightly backup'", true, false},
+		{"TaskState eq 'Completed' or TaskState eq 'Running'", true, false},
+		{"TaskState eq 'Running' and Name eq 'Other'", false, false},
+		{"StartTime gt '2025-01-01T00:00:00Z'", true, false},
+		{"Unknown eq 'x'", false, false},
+		{"", false, true},
+		{"TaskState eq", false, true},
+		{"TaskState like 'Running'", false, true},
+		{"TaskState eq 'Running' and", false, true},
+		{"TaskState eq 'Running", false, true},
+		{"TaskState eq 'Running' xor Name eq 'x'", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := parseFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilter(%q) error = %v, wantErr %v", tt.filter, err, tt.wantErr)
+			}
+			if err == nil && expr.matches(lookup) != tt.want {
+				t.Errorf("matches() = %v, want %v", !tt.want, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+// serviceIdentity is what the ServiceRoot reports about this instance
+type serviceIdentity struct {
+	Name           string
+	UUID           string
+	RedfishVersion string
+
+	// Identification is served in the ServiceRoot's Oem block; nil when none
+	// is configured
+	Identification *models.ContosoServiceRootOem
+}
+
+// currentIdentity holds the identity served by the ServiceRoot. New replaces
+// it from the configuration; the default covers handlers used without a Server.
+var currentIdentity atomic.Pointer[serviceIdentity]
+
+func init() {
+	currentIdentity.Store(&serviceIdentity{
+		Name:           "Root Service",
+		UUID:           newUUID(),
+		RedfishVersion: "1.15.0",
+	})
+}
+
+// newServiceIdentity resolves the configured identity, filling in defaults
+// and generating or loading a persisted UUID when none is configured
+func newServiceIdentity(cfg config.ServiceConfig) (*serviceIdentity, error) {
+	identity := &serviceIdentity{
+		Name:           cfg.Name,
+		UUID:           cfg.UUID,
+		RedfishVersion: cfg.RedfishVersion,
+	}
+	if cfg.Welcome != "" || cfg.Datacenter != "" || cfg.Rack != "" || cfg.Contact != "" {
+		identity.Identification = &models.ContosoServiceRootOem{
+			Welcome:    cfg.Welcome,
+			Datacenter: cfg.Datacenter,
+			Rack:       cfg.Rack,
+			Contact:    cfg.Contact,
+		}
+	}
+	if identity.Name == "" {
+		identity.Name = "Root Service"
+	}
+	if identity.RedfishVersion == "" {
+		identity.RedfishVersion = "1.15.0"
+	}
+
+	if identity.UUID == "" {
+		uuid, err := persistentUUID(cfg.UUIDFile)
+		if err != nil {
+			return nil, err
+		}
+		identity.UUID = uuid
+	}
+	return identity, nil
+}
+
+// persistentUUID reads the service UUID from path, generating and saving a
+// new one if the file does not exist yet. An empty path yields a fresh UUID.
+func persistentUUID(path string) (string, error) {
+	if path == "" {
+		return newUUID(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		uuid := strings.TrimSpace(string(data))
+		if !config.ValidUUID(uuid) {
+			return "", fmt.Errorf("service UUID file %s does not contain a valid UUID", path)
+		}
+		return uuid, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read service UUID file: %w", err)
+	}
+
+	uuid := newUUID()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create service UUID directory: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a partial UUID
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(uuid+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write service UUID file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("failed to write service UUID file: %w", err)
+	}
+	return uuid, nil
+}
+
+// newUUID generates a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
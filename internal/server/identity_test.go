@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func getServiceRoot(t *testing.T, s *Server) models.ServiceRoot {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", "/redfish/v1/", nil)
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var root models.ServiceRoot
+	if err := json.NewDecoder(w.Body).Decode(&root); err != nil {
+		t.Fatalf("Failed to decode service root: %v", err)
+	}
+	return root
+}
+
+func TestConfiguredServiceIdentity(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		Service: config.ServiceConfig{
+			Name:           "Rack 12 BMC",
+			UUID:           "92384634-2938-2342-8820-489239905423",
+			RedfishVersion: "1.17.0",
+		},
+	}
+
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	root := getServiceRoot(t, s)
+	if root.Name != "Rack 12 BMC" || root.UUID != "92384634-2938-2342-8820-489239905423" || root.RedfishVersion != "1.17.0" {
+		t.Errorf("Unexpected identity: Name=%q UUID=%q RedfishVersion=%q", root.Name, root.UUID, root.RedfishVersion)
+	}
+}
+
+func TestServiceRootIdentification(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		Service: config.ServiceConfig{
+			Welcome:    "Lab BMC, ask before power cycling",
+			Datacenter: "FRA-2",
+			Rack:       "R12",
+			Contact:    "ops@example.com",
+		},
+	}
+
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	root := getServiceRoot(t, s)
+	if root.Oem == nil || root.Oem.Contoso == nil || *root.Oem.Contoso != (models.ContosoServiceRootOem{
+		Welcome:    "Lab BMC, ask before power cycling",
+		Datacenter: "FRA-2",
+		Rack:       "R12",
+		Contact:    "ops@example.com",
+	}) {
+		t.Errorf("Expected the configured identification in the Oem block, got %+v", root.Oem)
+	}
+
+	// Without any identification configured there is no Oem block at all
+	s, err = New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/redfish/v1/", nil))
+	var properties map[string]json.RawMessage
+	json.NewDecoder(w.Body).Decode(&properties)
+	if oem, present := properties["Oem"]; present {
+		t.Errorf("Expected no Oem block when unconfigured, got %s", oem)
+	}
+}
+
+func TestGeneratedServiceUUIDIsPersisted(t *testing.T) {
+	uuidFile := filepath.Join(t.TempDir(), "state", "service_uuid")
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		Service: config.ServiceConfig{UUIDFile: uuidFile},
+	}
+
+	first, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	uuid := getServiceRoot(t, first).UUID
+	if !config.ValidUUID(uuid) || uuid == "00000000-0000-0000-0000-000000000000" {
+		t.Fatalf("Expected a generated UUID, got %q", uuid)
+	}
+
+	data, err := os.ReadFile(uuidFile)
+	if err != nil || strings.TrimSpace(string(data)) != uuid {
+		t.Fatalf("Expected UUID %s to be persisted, got %q (%v)", uuid, data, err)
+	}
+
+	second, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if again := getServiceRoot(t, second).UUID; again != uuid {
+		t.Errorf("Expected the persisted UUID %s after restart, got %s", uuid, again)
+	}
+}
+
+func TestInvalidServiceIdentity(t *testing.T) {
+	for _, service := range []config.ServiceConfig{
+		{UUID: "not-a-uuid"},
+		{RedfishVersion: "1.15"},
+	} {
+		cfg := &config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}, Service: service}
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected %+v to be rejected", service)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+)
+
+// lastResourceID numbers the tasks, subscriptions and composed systems the
+// service creates
+var lastResourceID atomic.Uint64
+
+// newResourceID returns the Id of a new task, subscription or composed
+// system. The sequence number makes it unique within this run and the random
+// suffix keeps it apart from Ids persisted by earlier runs.
+func newResourceID() string {
+	var suffix [4]byte
+	rand.Read(suffix[:])
+	return fmt.Sprintf("%d-%x", lastResourceID.Add(1), suffix)
+}
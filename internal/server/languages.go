@@ -0,0 +1,160 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// defaultLanguage is the language of the built-in registries, served when a
+// request's Accept-Language matches none of a registry's translations
+const defaultLanguage = "en"
+
+var (
+	messageRegistriesMutex sync.RWMutex
+	// messageRegistries holds the translations of each message registry,
+	// keyed by registry ID and then by language
+	messageRegistries = map[string]map[string]*models.MessageRegistry{
+		baseRegistry.ID: {defaultLanguage: baseRegistry},
+	}
+)
+
+// RegisterMessageRegistry adds a translation of a message registry, named by
+// its Language. Registering the same registry and language again replaces the
+// earlier entry. Messages the translation lacks are resolved in English.
+func RegisterMessageRegistry(registry *models.MessageRegistry) {
+	messageRegistriesMutex.Lock()
+	defer messageRegistriesMutex.Unlock()
+
+	if messageRegistries[registry.ID] == nil {
+		messageRegistries[registry.ID] = make(map[string]*models.MessageRegistry)
+	}
+	messageRegistries[registry.ID][registry.Language] = registry
+}
+
+// UnregisterMessageRegistry removes a translation of a message registry. The
+// built-in English registries cannot be removed.
+func UnregisterMessageRegistry(id, language string) {
+	if language == defaultLanguage {
+		return
+	}
+
+	messageRegistriesMutex.Lock()
+	defer messageRegistriesMutex.Unlock()
+	delete(messageRegistries[id], language)
+}
+
+// registryLanguages returns the languages a registry is available in, the
+// default first
+func registryLanguages(id string) []string {
+	messageRegistriesMutex.RLock()
+	defer messageRegistriesMutex.RUnlock()
+
+	languages := make([]string, 0, len(messageRegistries[id]))
+	for language := range messageRegistries[id] {
+		if language != defaultLanguage {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+	return append([]string{defaultLanguage}, languages...)
+}
+
+// messageRegistry returns a registry in the given language
+func messageRegistry(id, language string) (*models.MessageRegistry, bool) {
+	messageRegistriesMutex.RLock()
+	defer messageRegistriesMutex.RUnlock()
+
+	registry, ok := messageRegistries[id][language]
+	return registry, ok
+}
+
+// negotiateLanguage picks the offered language the Accept-Language header
+// prefers. A range matches a language equal to it or to one of its prefixes,
+// so de-CH accepts de, and de accepts de-DE. Ranges with q=0 are refused, and
+// the default language is returned when nothing else matches.
+func negotiateLanguage(acceptLanguage string, offered []string) string {
+	type languageRange struct {
+		tag string
+		q   float64
+	}
+	var ranges []languageRange
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			ranges = append(ranges, languageRange{strings.ToLower(tag), q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, r := range ranges {
+		if r.tag == "*" {
+			return defaultLanguage
+		}
+		for tag := r.tag; tag != ""; {
+			if i := slices.IndexFunc(offered, func(language string) bool { return strings.EqualFold(language, tag) }); i >= 0 {
+				return offered[i]
+			}
+			cut := strings.LastIndex(tag, "-")
+			if cut < 0 {
+				break
+			}
+			tag = tag[:cut]
+		}
+		for _, language := range offered {
+			if strings.HasPrefix(strings.ToLower(language), r.tag+"-") {
+				return language
+			}
+		}
+	}
+	return defaultLanguage
+}
+
+// requestRegistry returns the translation of a registry that the request's
+// Accept-Language prefers, and notes on the response which language it is in
+func requestRegistry(w http.ResponseWriter, r *http.Request, id string) *models.MessageRegistry {
+	language := negotiateLanguage(r.Header.Get("Accept-Language"), registryLanguages(id))
+	registry, ok := messageRegistry(id, language)
+	if !ok {
+		registry, _ = messageRegistry(id, defaultLanguage)
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", registry.Language)
+	return registry
+}
+
+// localizedMessage builds the extended-info message for a Base registry key
+// with its text resolved from args in the request's language
+func localizedMessage(w http.ResponseWriter, r *http.Request, key string, args ...string) models.Message {
+	registry := requestRegistry(w, r, baseRegistry.ID)
+	message, ok := registry.Resolve(key, args...)
+	if !ok {
+		// The translation lacks this message
+		w.Header().Set("Content-Language", defaultLanguage)
+		message, _ = baseRegistry.Resolve(key, args...)
+		registry = baseRegistry
+	}
+
+	details := registryMessage(key, message)
+	details.MessageArgs = args
+	if entry, ok := registry.Messages[key]; ok && entry.Resolution != "" {
+		details.Resolution = entry.Resolution
+	}
+	return details
+}
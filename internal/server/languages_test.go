@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	offered := []string{"en", "de", "fr-CA"}
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-CH", "de"},
+		{"fr", "fr-CA"},
+		{"ja, de;q=0.5", "de"},
+		{"de;q=0.2, fr-CA;q=0.8", "fr-CA"},
+		{"de;q=0", "en"},
+		{"ja, *;q=0.1", "en"},
+		{"ja", "en"},
+	}
+	for _, tt := range tests {
+		if got := negotiateLanguage(tt.acceptLanguage, offered); got != tt.want {
+			t.Errorf("negotiateLanguage(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizedRegistry(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Language", "de")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without a German translation the English registry is served
+	w := get("/redfish/v1/Registries/Base.1.0.0.json")
+	var registry models.MessageRegistry
+	json.NewDecoder(w.Body).Decode(&registry)
+	if w.Code != http.StatusOK || registry.Language != "en" || w.Header().Get("Content-Language") != "en" {
+		t.Errorf("Expected the English registry, got %d %q (Content-Language %q)", w.Code, registry.Language, w.Header().Get("Content-Language"))
+	}
+
+	german := models.NewMessageRegistry("de")
+	notFound := german.Messages["ResourceNotFound"]
+	notFound.Message = "Die angeforderte Ressource %1 wurde nicht gefunden"
+	german.Messages["ResourceNotFound"] = notFound
+	RegisterMessageRegistry(german)
+	t.Cleanup(func() { UnregisterMessageRegistry(german.ID, "de") })
+
+	w = get("/redfish/v1/Registries/Base.1.0.0.json")
+	json.NewDecoder(w.Body).Decode(&registry)
+	if registry.Language != "de" || w.Header().Get("Content-Language") != "de" {
+		t.Errorf("Expected the German registry, got %q (Content-Language %q)", registry.Language, w.Header().Get("Content-Language"))
+	}
+
+	// The registry file locates both translations
+	var file models.MessageRegistryFile
+	json.NewDecoder(get("/redfish/v1/Registries/Base.1.0.0").Body).Decode(&file)
+	if !slices.Equal(file.Languages, []string{"en", "de"}) || len(file.Location) != 2 {
+		t.Fatalf("Expected English and German locations, got %+v", file.Location)
+	}
+	w = get(file.Location[1].Uri)
+	json.NewDecoder(w.Body).Decode(&registry)
+	if w.Code != http.StatusOK || registry.Language != "de" {
+		t.Errorf("Expected %s to serve the German registry, got %d %q", file.Location[1].Uri, w.Code, registry.Language)
+	}
+
+	// Messages resolve in the request's language
+	w = get("/redfish/v1/Systems/no-such-system")
+	var errorResponse models.RedfishError
+	json.NewDecoder(w.Body).Decode(&errorResponse)
+	if want := "Die angeforderte Ressource /redfish/v1/Systems/no-such-system wurde nicht gefunden"; errorResponse.Error.Message != want {
+		t.Errorf("Expected %q, got %q", want, errorResponse.Error.Message)
+	}
+	if errorResponse.Error.Code != baseRegistry.MessageID("ResourceNotFound") {
+		t.Errorf("Expected the MessageId to be independent of language, got %s", errorResponse.Error.Code)
+	}
+}
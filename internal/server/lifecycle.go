@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// requestTracker counts in-flight requests and holds the cancel functions of
+// long-lived handlers (SSE streams, task monitors) so shutdown can end them
+// promptly instead of waiting for the drain timeout. It also carries the
+// server's lifecycle context, which background work such as task workers runs
+// under because it outlives the request that started it.
+type requestTracker struct {
+	active atomic.Int64
+
+	background     context.Context
+	stopBackground context.CancelFunc
+
+	mutex    sync.Mutex
+	closing  bool
+	nextID   int
+	longLive map[int]context.CancelFunc
+}
+
+type trackerKey struct{}
+
+// newRequestTracker creates an empty request tracker
+func newRequestTracker() *requestTracker {
+	background, stopBackground := context.WithCancel(context.Background())
+	return &requestTracker{
+		background:     background,
+		stopBackground: stopBackground,
+		longLive:       make(map[int]context.CancelFunc),
+	}
+}
+
+// middleware counts each request and makes the tracker reachable from handlers
+func (t *requestTracker) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.active.Add(1)
+		defer t.active.Add(-1)
+
+		ctx := context.WithValue(r.Context(), trackerKey{}, t)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Active returns the number of requests currently being served
+func (t *requestTracker) Active() int64 {
+	return t.active.Load()
+}
+
+// cancelLongLived cancels every registered long-lived handler and refuses new
+// ones. It returns the number of handlers that were forcibly ended.
+func (t *requestTracker) cancelLongLived() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.closing = true
+	forced := len(t.longLive)
+	for id, cancel := range t.longLive {
+		cancel()
+		delete(t.longLive, id)
+	}
+	return forced
+}
+
+// beginLongLived registers a handler that holds its connection open. The
+// returned context is cancelled when the client goes away or the server shuts
+// down; done must be called when the handler returns. Requests served outside
+// a tracked server (e.g. directly through a mux in tests) get the request
+// context unchanged.
+func beginLongLived(r *http.Request) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(r.Context())
+
+	t, ok := r.Context().Value(trackerKey{}).(*requestTracker)
+	if !ok {
+		return ctx, cancel
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.closing {
+		cancel()
+		return ctx, cancel
+	}
+
+	id := t.nextID
+	t.nextID++
+	t.longLive[id] = cancel
+
+	return ctx, func() {
+		cancel()
+		t.mutex.Lock()
+		delete(t.longLive, id)
+		t.mutex.Unlock()
+	}
+}
+
+// backgroundContext returns the context for work a request starts that
+// outlives it. The context is cancelled when the server shuts down. Requests
+// served outside a tracked server get a context that is never cancelled.
+func backgroundContext(r *http.Request) context.Context {
+	if t, ok := r.Context().Value(trackerKey{}).(*requestTracker); ok {
+		return t.background
+	}
+	return context.Background()
+}
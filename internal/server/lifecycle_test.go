@@ -0,0 +1,200 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+func TestShutdownClosesSSEPromptly(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server: config.ServerConfig{
+			Address: "127.0.0.1:0",
+		},
+	}
+	server, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.httpServer.Serve(ln)
+
+	req, _ := http.NewRequest("GET", "http://"+ln.Addr().String()+"/redfish/v1/EventService/SSE", nil)
+	req.SetBasicAuth("admin", "password")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("SSE request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	// Wait for the initial heartbeat so the stream is known to be open
+	reader := bufio.NewReader(resp.Body)
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("Failed to read heartbeat: %v", err)
+	}
+	if active := server.tracker.Active(); active != 1 {
+		t.Errorf("Expected 1 active request, got %d", active)
+	}
+
+	shutdownDone := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		shutdownDone <- server.Shutdown()
+	}()
+
+	streamClosed := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, reader)
+		close(streamClosed)
+	}()
+
+	select {
+	case <-streamClosed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("SSE stream was not closed promptly on shutdown")
+	}
+
+	select {
+	case err := <-shutdownDone:
+		if err != nil {
+			t.Errorf("Shutdown returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown did not complete promptly")
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Shutdown took %v", elapsed)
+	}
+}
+
+func TestLongLivedRefusedAfterShutdown(t *testing.T) {
+	tracker := newRequestTracker()
+	if forced := tracker.cancelLongLived(); forced != 0 {
+		t.Errorf("Expected no long-lived handlers, got %d", forced)
+	}
+
+	var cancelled bool
+	handler := tracker.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, done := beginLongLived(r)
+		defer done()
+		select {
+		case <-ctx.Done():
+			cancelled = true
+		default:
+		}
+	}))
+
+	req, _ := http.NewRequest("GET", "/redfish/v1/EventService/SSE", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !cancelled {
+		t.Error("Long-lived handlers started during shutdown should be cancelled immediately")
+	}
+}
+
+func TestShutdownCancelsBackgroundTasks(t *testing.T) {
+	previous := systemResetDuration
+	systemResetDuration = time.Hour
+	defer func() { systemResetDuration = previous }()
+
+	server, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: "127.0.0.1:0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "ForceRestart"}`))
+	req.SetBasicAuth("admin", "password")
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", w.Code)
+	}
+	var task struct{ Id string }
+	json.Unmarshal(w.Body.Bytes(), &task)
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		delete(tasks, task.Id)
+		tasksMutex.Unlock()
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for taskState(task.Id) != "Running" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the reset to start, still %s", taskState(task.Id))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := server.Shutdown(); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	// The worker would otherwise sleep for an hour
+	deadline = time.Now().Add(2 * time.Second)
+	for taskState(task.Id) != "Cancelled" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected shutdown to cancel the running reset, still %s", taskState(task.Id))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestShutdownNotifiesSubscribers(t *testing.T) {
+	server, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: "127.0.0.1:0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	allowLoopbackDestinations(t)
+
+	received := make(chan models.Event, 2)
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer destination.Close()
+
+	// A batching subscriber is told at once rather than with its next batch
+	subscription := models.NewEventSubscription("shutdown", destination.URL, "Redfish")
+	subscription.Oem = &models.EventSubscriptionOem{Contoso: &models.ContosoSubscriptionOem{
+		EventBatching: &models.EventBatching{MaxEvents: 100, FlushIntervalMs: 60000},
+	}}
+	if err := addSubscription(subscription); err != nil {
+		t.Fatalf("Failed to add subscription: %v", err)
+	}
+
+	if err := server.Shutdown(); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	// The event was delivered before Shutdown returned
+	select {
+	case event := <-received:
+		if len(event.Events) != 1 || event.Events[0].MessageId != baseRegistry.MessageID("ServiceShuttingDown") {
+			t.Errorf("Expected one ServiceShuttingDown record, got %+v", event.Events)
+		}
+	default:
+		t.Fatal("Expected the subscriber to receive the shutdown event during Shutdown")
+	}
+}
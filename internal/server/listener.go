@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// connectionRefusal is written to a plain HTTP connection refused for being
+// over the connection limit
+const connectionRefusal = "HTTP/1.1 503 Service Unavailable\r\nRetry-After: 5\r\nContent-Length: 0\r\nConnection: close\r\n\r\n"
+
+// limitListener accepts at most a fixed number of concurrent connections.
+// A connection beyond the limit is refused as soon as it is accepted, rather
+// than left waiting in the backlog, so clients fail fast under load.
+type limitListener struct {
+	net.Listener
+	slots     chan struct{}
+	plainHTTP bool
+}
+
+// newLimitListener limits l to limit concurrent connections. With plainHTTP,
+// refused connections get a 503 response; a TLS connection cannot be
+// answered before its handshake and is closed instead.
+func newLimitListener(l net.Listener, limit int, plainHTTP bool) net.Listener {
+	return &limitListener{Listener: l, slots: make(chan struct{}, limit), plainHTTP: plainHTTP}
+}
+
+// Accept returns the next connection that fits within the limit, refusing
+// any that do not
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case l.slots <- struct{}{}:
+			return &limitConn{Conn: conn, release: func() { <-l.slots }}, nil
+		default:
+			go l.refuse(conn)
+		}
+	}
+}
+
+// refuse closes a connection over the limit, answering it first if it
+// carries plain HTTP
+func (l *limitListener) refuse(conn net.Conn) {
+	defer conn.Close()
+	if l.plainHTTP {
+		conn.SetWriteDeadline(time.Now().Add(time.Second))
+		conn.Write([]byte(connectionRefusal))
+	}
+}
+
+// limitConn frees its listener slot when it is closed
+type limitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+func TestMaxHeaderBytesApplied(t *testing.T) {
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0", MaxHeaderBytes: 4096}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if s.httpServer.MaxHeaderBytes != 4096 {
+		t.Errorf("Expected MaxHeaderBytes 4096, got %d", s.httpServer.MaxHeaderBytes)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go s.httpServer.Serve(ln)
+	defer s.httpServer.Close()
+
+	req, _ := http.NewRequest("GET", "http://"+ln.Addr().String()+"/health", nil)
+	req.Header.Set("X-Padding", strings.Repeat("a", 16<<10))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("Expected status 431 for oversized headers, got %d", resp.StatusCode)
+	}
+}
+
+func TestLimitListenerCapsConnections(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}
+	go server.Serve(newLimitListener(inner, 1, true))
+	defer server.Close()
+	addr := inner.Addr().String()
+
+	status := func(conn net.Conn) (int, error) {
+		conn.SetDeadline(time.Now().Add(2 * time.Second))
+		if _, err := conn.Write([]byte("GET /health HTTP/1.1\r\nHost: test\r\n\r\n")); err != nil {
+			return 0, err
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	// The first connection holds the only slot while it stays open
+	held, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if code, err := status(held); err != nil || code != http.StatusOK {
+		t.Fatalf("Expected the first connection to be served, got %d, %v", code, err)
+	}
+
+	refused, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer refused.Close()
+	if code, err := status(refused); err != nil || code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a connection over the limit to get 503, got %d, %v", code, err)
+	}
+
+	// Closing the first connection frees its slot
+	held.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		code, err := status(conn)
+		conn.Close()
+		if err == nil && code == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a new connection once the slot was freed, got %d, %v", code, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
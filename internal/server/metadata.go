@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+// metadataDocument is the OData CSDL metadata document of the service
+const metadataDocument = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Service" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="ServiceRoot">
+        <Key>
+          <PropertyRef Name="Id" />
+        </Key>
+        <Property Name="Id" Type="Edm.String" Nullable="false" />
+        <Property Name="Name" Type="Edm.String" Nullable="false" />
+        <Property Name="RedfishVersion" Type="Edm.String" Nullable="false" />
+      </EntityType>
+      <EntityContainer Name="Service">
+        <EntitySet Name="ServiceRoot" EntityType="Service.ServiceRoot" />
+        <EntitySet Name="Systems" EntityType="ComputerSystemCollection.ComputerSystemCollection" />
+        <EntitySet Name="Chassis" EntityType="ChassisCollection.ChassisCollection" />
+        <EntitySet Name="Managers" EntityType="ManagerCollection.ManagerCollection" />
+        <EntitySet Name="TaskService" EntityType="TaskService.TaskService" />
+        <EntitySet Name="SessionService" EntityType="SessionService.SessionService" />
+        <EntitySet Name="AccountService" EntityType="AccountService.AccountService" />
+        <EntitySet Name="EventService" EntityType="EventService.EventService" />
+        <EntitySet Name="Registries" EntityType="MessageRegistryFileCollection.MessageRegistryFileCollection" />
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// csdlEdmx is the subset of CSDL XML the metadata document uses
+type csdlEdmx struct {
+	Version string       `xml:"Version,attr"`
+	Schemas []csdlSchema `xml:"DataServices>Schema"`
+}
+
+type csdlSchema struct {
+	Namespace   string `xml:"Namespace,attr"`
+	EntityTypes []struct {
+		Name string `xml:"Name,attr"`
+		Keys []struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Key>PropertyRef"`
+		Properties []struct {
+			Name     string `xml:"Name,attr"`
+			Type     string `xml:"Type,attr"`
+			Nullable string `xml:"Nullable,attr"`
+		} `xml:"Property"`
+	} `xml:"EntityType"`
+	EntityContainer *struct {
+		Name       string `xml:"Name,attr"`
+		EntitySets []struct {
+			Name       string `xml:"Name,attr"`
+			EntityType string `xml:"EntityType,attr"`
+		} `xml:"EntitySet"`
+	} `xml:"EntityContainer"`
+}
+
+// metadataJSON converts a CSDL XML metadata document to its CSDL JSON
+// representation. Facets at their CSDL JSON default, such as an Edm.String
+// type or a property that is not nullable, are left out as that format
+// specifies.
+func metadataJSON(document string) ([]byte, error) {
+	var edmx csdlEdmx
+	if err := xml.Unmarshal([]byte(document), &edmx); err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{"$Version": edmx.Version}
+	for _, schema := range edmx.Schemas {
+		members := map[string]interface{}{}
+		for _, entityType := range schema.EntityTypes {
+			member := map[string]interface{}{"$Kind": "EntityType"}
+			if len(entityType.Keys) > 0 {
+				var keys []string
+				for _, key := range entityType.Keys {
+					keys = append(keys, key.Name)
+				}
+				member["$Key"] = keys
+			}
+			for _, property := range entityType.Properties {
+				facets := map[string]interface{}{}
+				if property.Type != "" && property.Type != "Edm.String" {
+					facets["$Type"] = property.Type
+				}
+				if !strings.EqualFold(property.Nullable, "false") {
+					facets["$Nullable"] = true
+				}
+				member[property.Name] = facets
+			}
+			members[entityType.Name] = member
+		}
+		if container := schema.EntityContainer; container != nil {
+			member := map[string]interface{}{"$Kind": "EntityContainer"}
+			for _, set := range container.EntitySets {
+				member[set.Name] = map[string]interface{}{"$Collection": true, "$Type": set.EntityType}
+			}
+			members[container.Name] = member
+			result["$EntityContainer"] = schema.Namespace + "." + container.Name
+		}
+		result[schema.Namespace] = members
+	}
+	return json.Marshal(result)
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// methodOverrideHeader lets clients that can only send GET and POST issue
+// other methods
+const methodOverrideHeader = "X-HTTP-Method-Override"
+
+// overridableMethods are the methods a POST may be overridden to
+var overridableMethods = []string{"PATCH", "PUT", "DELETE"}
+
+// methodOverrideMiddleware treats a POST carrying X-HTTP-Method-Override as
+// the method it names, for clients behind proxies that only pass GET and
+// POST. It must run before authentication so the overridden method is the one
+// that is authorized.
+func methodOverrideMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		override := r.Header.Get(methodOverrideHeader)
+		if override == "" || r.Method != "POST" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		method := strings.ToUpper(strings.TrimSpace(override))
+		if !slices.Contains(overridableMethods, method) {
+			sendRedfishError(w, "HeaderInvalid", fmt.Sprintf("%s %q is not supported; allowed values: %s", methodOverrideHeader, override, strings.Join(overridableMethods, ", ")), http.StatusBadRequest)
+			return
+		}
+
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.Method = method
+		r2.Header = r.Header.Clone()
+		r2.Header.Del(methodOverrideHeader)
+		next.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+// postWithOverride sends a POST to the system asking for it to be treated as a PATCH
+func postWithOverride(s *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/redfish/v1/Systems/1", strings.NewReader(body))
+	req.Header.Set("X-HTTP-Method-Override", "PATCH")
+	req.SetBasicAuth("admin", "password")
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestMethodOverride(t *testing.T) {
+	defer systemStore.Put("1", models.NewComputerSystem("1"))
+
+	cfg := &config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0", AllowMethodOverride: true}}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	w := postWithOverride(s, `{"AssetTag": "overridden"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the overridden PATCH to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if system, _ := getSystem("1"); system.AssetTag != "overridden" {
+		t.Errorf("Expected AssetTag to be patched, got %q", system.AssetTag)
+	}
+
+	// Only methods that change a resource may be requested
+	req := httptest.NewRequest("POST", "/redfish/v1/Systems/1", strings.NewReader(`{}`))
+	req.Header.Set("X-HTTP-Method-Override", "CONNECT")
+	req.SetBasicAuth("admin", "password")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unsupported override, got %d", w.Code)
+	}
+}
+
+func TestMethodOverrideDisabled(t *testing.T) {
+	defer systemStore.Put("1", models.NewComputerSystem("1"))
+
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	w := postWithOverride(s, `{"AssetTag": "overridden"}`)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected the header to be ignored and POST rejected, got %d", w.Code)
+	}
+	if system, _ := getSystem("1"); system.AssetTag == "overridden" {
+		t.Error("Expected AssetTag to be unchanged")
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// producedMediaTypes returns the representations a route can produce, the
+// default first. Everything except the metadata document, the SSE stream, and
+// the OpenAPI document is JSON; the systems collection can also be exported
+// as CSV.
+func producedMediaTypes(path string) []string {
+	switch path {
+	case "/redfish/v1/Systems":
+		return []string{"application/json", csvMediaType}
+	case "/redfish/v1/$metadata":
+		return []string{"application/xml"}
+	case "/redfish/v1/EventService/SSE":
+		return []string{"text/event-stream"}
+	case "/redfish/v1/openapi.yaml":
+		return []string{"application/yaml"}
+	default:
+		return []string{"application/json"}
+	}
+}
+
+// formatMediaTypes are the media types the $format query parameter selects
+// by name; a media type such as application/json may also be given directly
+var formatMediaTypes = map[string]string{
+	"json": "application/json",
+	"xml":  "application/xml",
+	"csv":  csvMediaType,
+}
+
+// formatMediaTypesFor returns the representations $format can select on a
+// route: those it produces and, for the metadata document, its JSON form,
+// which is only served on request so clients that send Accept:
+// application/json everywhere still get 406 rather than an unexpected CSDL
+// dialect
+func formatMediaTypesFor(path string) []string {
+	offered := producedMediaTypes(path)
+	if path == "/redfish/v1/$metadata" {
+		offered = append(offered, "application/json")
+	}
+	return offered
+}
+
+// acceptableMediaType reports whether any offered media type satisfies the
+// Accept header. An empty header accepts everything.
+func acceptableMediaType(accept string, offered []string) bool {
+	if strings.TrimSpace(accept) == "" {
+		return true
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if weight, err := strconv.ParseFloat(q, 64); err != nil || weight <= 0 {
+				continue
+			}
+		}
+		for _, candidate := range offered {
+			if mediaRangeMatches(mediaType, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mediaRangeMatches reports whether a media range such as application/* covers mediaType
+func mediaRangeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return false
+}
+
+// acceptableRequestBody reports whether the body of a request can be decoded
+// as JSON: it is empty, unlabeled, or labeled application/json, with a UTF-8
+// charset if any
+func acceptableRequestBody(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" || r.ContentLength == 0 {
+		return true
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/json" {
+		return false
+	}
+	charset, ok := params["charset"]
+	return !ok || strings.EqualFold(charset, "utf-8")
+}
+
+// contentNegotiationMiddleware rejects requests whose Accept header cannot be
+// satisfied by the route with 406 Not Acceptable, and POST, PUT and PATCH
+// bodies that are not JSON with 415 Unsupported Media Type. A $format query
+// parameter, for clients behind proxies that rewrite Accept, replaces the
+// header; a format the route cannot produce is rejected with 400.
+func contentNegotiationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST", "PUT", "PATCH":
+			if !acceptableRequestBody(r) {
+				setRedfishHeaders(w)
+				sendRedfishError(w, "HeaderInvalid", "The Content-Type "+r.Header.Get("Content-Type")+" is not supported; request bodies must be application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+		}
+
+		if r.URL.Query().Has("$format") {
+			format := r.URL.Query().Get("$format")
+			mediaType, ok := formatMediaTypes[strings.ToLower(format)]
+			if !ok {
+				mediaType = strings.ToLower(format)
+			}
+			if !slices.Contains(formatMediaTypesFor(r.URL.Path), mediaType) {
+				setRedfishHeaders(w)
+				sendRedfishError(w, "QueryParameterValueFormatError", fmt.Sprintf("The $format %q is not supported by this resource", format), http.StatusBadRequest)
+				return
+			}
+			r = r.Clone(r.Context())
+			r.Header.Set("Accept", mediaType)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		accept := r.Header.Get("Accept")
+		if !acceptableMediaType(accept, producedMediaTypes(r.URL.Path)) {
+			setRedfishHeaders(w)
+			sendRedfishError(w, "HeaderInvalid", "The Accept header "+accept+" cannot be satisfied by this resource", http.StatusNotAcceptable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// prefersRepresentation reports whether the client asked for a response body
+// with the RFC 7240 "Prefer: return=representation" preference
+func prefersRepresentation(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			name, _, _ := strings.Cut(preference, ";")
+			if strings.EqualFold(strings.ReplaceAll(strings.TrimSpace(name), " ", ""), "return=representation") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	handler := contentNegotiationMiddleware(mux)
+
+	tests := []struct {
+		name     string
+		path     string
+		accept   string
+		expected int
+	}{
+		{"no Accept header", "/redfish/v1/", "", http.StatusOK},
+		{"JSON", "/redfish/v1/", "application/json", http.StatusOK},
+		{"JSON with charset", "/redfish/v1/", "application/json;charset=utf-8", http.StatusOK},
+		{"wildcard", "/redfish/v1/", "*/*", http.StatusOK},
+		{"application wildcard", "/redfish/v1/", "application/*", http.StatusOK},
+		{"CSV", "/redfish/v1/Systems/1", "text/csv", http.StatusNotAcceptable},
+		{"XML on JSON resource", "/redfish/v1/", "application/xml", http.StatusNotAcceptable},
+		{"JSON refused by q=0", "/redfish/v1/", "application/json;q=0", http.StatusNotAcceptable},
+		{"browser style list", "/redfish/v1/", "text/html,application/xml;q=0.9,*/*;q=0.8", http.StatusOK},
+		{"metadata as XML", "/redfish/v1/$metadata", "application/xml", http.StatusOK},
+		{"metadata as JSON", "/redfish/v1/$metadata", "application/json", http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+			if w.Code == http.StatusNotAcceptable {
+				if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Expected Redfish JSON error body, got Content-Type %s", ct)
+				}
+			}
+		})
+	}
+}
+
+func TestSystemsCollectionAsCSV(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	handler := contentNegotiationMiddleware(mux)
+
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Expected a CSV Content-Type, got %s", ct)
+	}
+
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Response is not valid CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected a header row and one system, got %v", rows)
+	}
+	if !slices.Equal(rows[0], systemsCSVColumns) {
+		t.Errorf("Unexpected header row %v", rows[0])
+	}
+	system, _ := getSystem("1")
+	if rows[1][0] != "1" || rows[1][1] != system.Name || rows[1][6] != system.PowerState {
+		t.Errorf("Unexpected data row %v", rows[1])
+	}
+
+	// JSON stays the default
+	for _, accept := range []string{"", "*/*", "application/json, text/csv"} {
+		req = httptest.NewRequest("GET", "/redfish/v1/Systems", nil)
+		req.Header.Set("Accept", accept)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected JSON for Accept %q, got %s", accept, ct)
+		}
+	}
+}
+
+func TestFormatQueryOverridesAccept(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	handler := contentNegotiationMiddleware(mux)
+
+	get := func(uri, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", uri, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// $format=json wins over an Accept header that would otherwise get 406
+	// or select CSV
+	if w := get("/redfish/v1/?$format=json", "application/xml"); w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("Expected JSON despite Accept: application/xml, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if w := get("/redfish/v1/Systems?$format=json", "text/csv"); w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("Expected JSON despite Accept: text/csv, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if w := get("/redfish/v1/Systems?$format=csv", "application/json"); !strings.HasPrefix(w.Header().Get("Content-Type"), csvMediaType) {
+		t.Errorf("Expected CSV with $format=csv, got %s", w.Header().Get("Content-Type"))
+	}
+
+	// The metadata document is available as CSDL JSON on request only
+	w := get("/redfish/v1/$metadata?$format=json", "application/xml")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("Expected JSON metadata, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &metadata); err != nil {
+		t.Fatalf("Invalid JSON metadata: %v", err)
+	}
+	if metadata["$Version"] != "4.0" || metadata["$EntityContainer"] != "Service.Service" {
+		t.Errorf("Expected a CSDL JSON document, got %v", metadata)
+	}
+	if w := get("/redfish/v1/$metadata?$format=xml", "application/json"); w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/xml") {
+		t.Errorf("Expected XML metadata, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	// Unknown formats and formats the resource cannot produce are rejected
+	for _, uri := range []string{"/redfish/v1/?$format=yaml", "/redfish/v1/?$format=xml", "/redfish/v1/Systems/1?$format=csv"} {
+		w := get(uri, "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", uri, w.Code)
+			continue
+		}
+		var errorResponse models.RedfishError
+		json.Unmarshal(w.Body.Bytes(), &errorResponse)
+		if errorResponse.Error.Code != baseRegistry.MessageID("QueryParameterValueFormatError") {
+			t.Errorf("Expected QueryParameterValueFormatError for %s, got %s", uri, errorResponse.Error.Code)
+		}
+	}
+}
+
+func TestRequestContentType(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	handler := contentNegotiationMiddleware(mux)
+
+	reset := func(contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "ForceRestart"}`))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, contentType := range []string{"text/plain", "application/xml", "application/x-www-form-urlencoded", "application/json; charset=latin1", "not a media type"} {
+		w := reset(contentType)
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("Expected status 415 for %s, got %d", contentType, w.Code)
+			continue
+		}
+		var errorResponse models.RedfishError
+		json.Unmarshal(w.Body.Bytes(), &errorResponse)
+		if errorResponse.Error.Code != baseRegistry.MessageID("HeaderInvalid") {
+			t.Errorf("Expected HeaderInvalid for %s, got %s", contentType, errorResponse.Error.Code)
+		}
+	}
+
+	for _, contentType := range []string{"application/json", "application/json; charset=utf-8", ""} {
+		if w := reset(contentType); w.Code != http.StatusAccepted {
+			t.Errorf("Expected the reset to be accepted with Content-Type %q, got %d: %s", contentType, w.Code, w.Body.String())
+		}
+	}
+
+	// Requests without a body are not checked
+	req := httptest.NewRequest("GET", "/redfish/v1/", nil)
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected a GET to ignore Content-Type, got %d", w.Code)
+	}
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// OemVendor describes an OEM extension: the Oem payloads it contributes to
+// resources and the custom actions served under /redfish/v1/Oem/{Name}/
+type OemVendor struct {
+	Name    string
+	Payload models.OemFactory
+	Actions map[string]http.HandlerFunc
+}
+
+// OemResourceAction is an OEM action offered on every resource of a type. It
+// is advertised in the resource's Actions.Oem block as #{Vendor}.{Name} and
+// invoked by a POST to {resource}/Actions/Oem/{Vendor}.{Name}.
+type OemResourceAction struct {
+	Vendor string
+	Name   string
+	Title  string
+	Invoke OemActionFunc
+}
+
+// OemActionFunc carries out an OEM action on the resource with the given Id,
+// with the parameters posted to it. It returns the resource to answer with,
+// a *models.Task the action runs as, which is answered with 202 Accepted, or
+// nil for 204 No Content. A task is refused with 503 while the TaskService
+// is disabled, so the action should leave its work to the task. An *OemActionError or *models.EnumError rejects
+// the request with 400; any other error is a 500.
+type OemActionFunc func(r *http.Request, id string, parameters map[string]interface{}) (interface{}, error)
+
+// OemActionError rejects an OEM action request, naming the Base registry
+// message that describes why, e.g. ActionParameterMissing
+type OemActionError struct {
+	Key     string
+	Message string
+}
+
+func (e *OemActionError) Error() string {
+	return e.Message
+}
+
+var (
+	oemActionsMutex sync.RWMutex
+	oemActions      = make(map[string]map[string]http.HandlerFunc)
+
+	// oemResourceActions holds the OEM actions of each resource type, keyed
+	// by resource type and then by Vendor.Name
+	oemResourceActions = make(map[string]map[string]OemResourceAction)
+)
+
+func init() {
+	RegisterOemVendor(OemVendor{
+		Name: "Contoso",
+		Payload: func(resourceType, id string) interface{} {
+			if resourceType == "ComputerSystem" {
+				return models.NewContosoOEM()
+			}
+			return nil
+		},
+		Actions: map[string]http.HandlerFunc{
+			"CustomAction": handleOemCustomAction,
+		},
+	})
+}
+
+// RegisterOemVendor plugs an OEM vendor into resource construction and action
+// dispatch. Registering the same vendor again replaces the earlier entry.
+func RegisterOemVendor(vendor OemVendor) {
+	if vendor.Payload != nil {
+		models.RegisterOem(vendor.Name, vendor.Payload)
+	} else {
+		models.UnregisterOem(vendor.Name)
+	}
+
+	actions := make(map[string]http.HandlerFunc, len(vendor.Actions))
+	for name, handler := range vendor.Actions {
+		actions[name] = handler
+	}
+
+	oemActionsMutex.Lock()
+	oemActions[vendor.Name] = actions
+	oemActionsMutex.Unlock()
+	resourceResponses.clear()
+}
+
+// UnregisterOemVendor removes an OEM vendor's payloads and actions
+func UnregisterOemVendor(name string) {
+	models.UnregisterOem(name)
+
+	oemActionsMutex.Lock()
+	delete(oemActions, name)
+	oemActionsMutex.Unlock()
+	resourceResponses.clear()
+}
+
+// RegisterOemResourceAction offers an OEM action on every resource of
+// resourceType, e.g. ComputerSystem. Registering the same vendor action again
+// replaces the earlier entry.
+func RegisterOemResourceAction(resourceType string, action OemResourceAction) {
+	oemActionsMutex.Lock()
+	defer oemActionsMutex.Unlock()
+
+	if oemResourceActions[resourceType] == nil {
+		oemResourceActions[resourceType] = make(map[string]OemResourceAction)
+	}
+	oemResourceActions[resourceType][action.Vendor+"."+action.Name] = action
+	resourceResponses.clear()
+}
+
+// UnregisterOemResourceAction removes a vendor action from a resource type
+func UnregisterOemResourceAction(resourceType, vendor, name string) {
+	oemActionsMutex.Lock()
+	defer oemActionsMutex.Unlock()
+
+	delete(oemResourceActions[resourceType], vendor+"."+name)
+	resourceResponses.clear()
+}
+
+// oemActionTargets builds the Actions.Oem block advertising the OEM actions
+// registered for resourceType on the resource at uri. It returns nil when
+// there are none, so the block is omitted.
+func oemActionTargets(resourceType string, uri models.ODataID) map[string]interface{} {
+	oemActionsMutex.RLock()
+	defer oemActionsMutex.RUnlock()
+
+	if len(oemResourceActions[resourceType]) == 0 {
+		return nil
+	}
+	targets := make(map[string]interface{}, len(oemResourceActions[resourceType]))
+	for name, action := range oemResourceActions[resourceType] {
+		targets["#"+name] = &models.ActionTarget{
+			Target: string(uri) + "/Actions/Oem/" + name,
+			Title:  action.Title,
+		}
+	}
+	return targets
+}
+
+// lookupOemResourceAction returns the OEM action registered for a resource
+// type under Vendor.Name
+func lookupOemResourceAction(resourceType, name string) (OemResourceAction, bool) {
+	oemActionsMutex.RLock()
+	defer oemActionsMutex.RUnlock()
+
+	action, ok := oemResourceActions[resourceType][name]
+	return action, ok
+}
+
+// handleOemResourceAction invokes the OEM action named Vendor.Name on the
+// resource of resourceType with the given Id, which the caller has found
+func handleOemResourceAction(w http.ResponseWriter, r *http.Request, resourceType, id, name string) {
+	action, ok := lookupOemResourceAction(resourceType, name)
+	if !ok {
+		sendRedfishError(w, "ActionNotSupported", fmt.Sprintf("Action %s not supported for %s", name, resourceType), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Allow", "POST")
+	if r.Method != "POST" {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	var parameters map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&parameters); err != nil && !errors.Is(err, io.EOF) {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+	}
+
+	result, err := action.Invoke(r, id, parameters)
+	var actionErr *OemActionError
+	var enumErr *models.EnumError
+	switch {
+	case errors.As(err, &actionErr):
+		sendRedfishError(w, actionErr.Key, actionErr.Message, http.StatusBadRequest)
+		return
+	case errors.As(err, &enumErr):
+		sendRedfishError(w, "ActionParameterValueNotInList", enumErr.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch result := result.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case *models.Task:
+		if !requireServiceEnabled(w, "/redfish/v1/TaskService") {
+			return
+		}
+		if err := addTask(result); err != nil {
+			sendAddTaskError(w, err)
+			return
+		}
+		w.Header().Set("Location", string(result.ODataID))
+		sendJSON(w, http.StatusAccepted, map[string]interface{}{
+			"@odata.id":   result.ODataID,
+			"@odata.type": result.ODataType,
+			"Id":          result.ID,
+			"Name":        result.Name,
+		})
+	default:
+		sendJSON(w, http.StatusOK, result)
+	}
+}
+
+// lookupOemAction returns the handler registered for a vendor action
+func lookupOemAction(vendor, action string) (http.HandlerFunc, bool) {
+	oemActionsMutex.RLock()
+	defer oemActionsMutex.RUnlock()
+
+	handler, ok := oemActions[vendor][action]
+	return handler, ok
+}
+
+// oemActionHandler dispatches /redfish/v1/Oem/{vendor}/{action} to the
+// registered vendor action
+func oemActionHandler(w http.ResponseWriter, r *http.Request) {
+	setRedfishHeaders(w)
+	w.Header().Set("Allow", "POST")
+
+	path := strings.TrimPrefix(r.URL.Path, "/redfish/v1/Oem/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	handler, ok := lookupOemAction(parts[0], parts[1])
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case "POST":
+		handler(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestRegisterOemVendor(t *testing.T) {
+	RegisterOemVendor(OemVendor{
+		Name: "Fabrikam",
+		Payload: func(resourceType, id string) interface{} {
+			if resourceType != "ComputerSystem" {
+				return nil
+			}
+			return map[string]interface{}{"RackSlot": "U" + id}
+		},
+		Actions: map[string]http.HandlerFunc{
+			"Blink": func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		},
+	})
+	defer UnregisterOemVendor("Fabrikam")
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems/1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var system struct {
+		Oem map[string]map[string]interface{} `json:"Oem"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &system); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if system.Oem["Fabrikam"]["RackSlot"] != "U1" {
+		t.Errorf("Expected Fabrikam Oem block, got %v", system.Oem)
+	}
+	if _, ok := system.Oem["Contoso"]; !ok {
+		t.Error("Expected Contoso Oem block to remain alongside Fabrikam")
+	}
+
+	// Vendors that return nil are left out of other resources
+	req = httptest.NewRequest("GET", "/redfish/v1/Chassis/1", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var chassis struct {
+		Oem map[string]interface{} `json:"Oem"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &chassis)
+	if _, ok := chassis.Oem["Fabrikam"]; ok {
+		t.Error("Fabrikam should not contribute to Chassis")
+	}
+
+	// The vendor's action is dispatched
+	req = httptest.NewRequest("POST", "/redfish/v1/Oem/Fabrikam/Blink", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 from Fabrikam action, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/redfish/v1/Oem/Fabrikam/Unknown", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown action, got %d", w.Code)
+	}
+
+	// The built-in Contoso action keeps working
+	req = httptest.NewRequest("POST", "/redfish/v1/Oem/Contoso/CustomAction", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 from Contoso action, got %d", w.Code)
+	}
+}
+
+func TestOemResourceActionDispatch(t *testing.T) {
+	RegisterOemResourceAction("ComputerSystem", OemResourceAction{
+		Vendor: "Fabrikam",
+		Name:   "SetRackSlot",
+		Title:  "Set the rack slot",
+		Invoke: func(r *http.Request, id string, parameters map[string]interface{}) (interface{}, error) {
+			slot, ok := parameters["Slot"].(string)
+			if !ok {
+				return nil, &OemActionError{Key: "ActionParameterMissing", Message: "Slot is required"}
+			}
+			if err := models.ValidateEnum("Slot", slot, []string{"U1", "U2"}); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"Id": id, "Slot": slot}, nil
+		},
+	})
+	defer UnregisterOemResourceAction("ComputerSystem", "Fabrikam", "SetRackSlot")
+	RegisterOemResourceAction("ComputerSystem", OemResourceAction{
+		Vendor: "Fabrikam",
+		Name:   "Diagnose",
+		Invoke: func(r *http.Request, id string, parameters map[string]interface{}) (interface{}, error) {
+			return models.NewTask(newResourceID(), "POST", "/redfish/v1/Systems/"+id+"/Actions/Oem/Fabrikam.Diagnose"), nil
+		},
+	})
+	defer UnregisterOemResourceAction("ComputerSystem", "Fabrikam", "Diagnose")
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	post := func(uri, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", uri, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var errorResponse models.RedfishError
+		json.Unmarshal(w.Body.Bytes(), &errorResponse)
+		return errorResponse.Error.Code
+	}
+
+	// The action is advertised in the system's Actions.Oem block
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems/1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var system models.ComputerSystem
+	json.Unmarshal(w.Body.Bytes(), &system)
+	advertised, _ := system.Actions.Oem["#Fabrikam.SetRackSlot"].(map[string]interface{})
+	if advertised["target"] != "/redfish/v1/Systems/1/Actions/Oem/Fabrikam.SetRackSlot" {
+		t.Fatalf("Expected the OEM action to be advertised, got %v", system.Actions.Oem)
+	}
+
+	// Parameters reach the handler and its result is returned
+	w = post("/redfish/v1/Systems/1/Actions/Oem/Fabrikam.SetRackSlot", `{"Slot": "U2"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if result["Id"] != "1" || result["Slot"] != "U2" {
+		t.Errorf("Expected the handler's result, got %v", result)
+	}
+
+	// Handler errors name their registry message
+	if w := post("/redfish/v1/Systems/1/Actions/Oem/Fabrikam.SetRackSlot", `{}`); errorCode(w) != baseRegistry.MessageID("ActionParameterMissing") {
+		t.Errorf("Expected ActionParameterMissing, got %d %s", w.Code, errorCode(w))
+	}
+	if w := post("/redfish/v1/Systems/1/Actions/Oem/Fabrikam.SetRackSlot", `{"Slot": "U9"}`); errorCode(w) != baseRegistry.MessageID("ActionParameterValueNotInList") {
+		t.Errorf("Expected ActionParameterValueNotInList, got %d %s", w.Code, errorCode(w))
+	}
+
+	// A task result is stored and answered with 202
+	w = post("/redfish/v1/Systems/1/Actions/Oem/Fabrikam.Diagnose", "")
+	if w.Code != http.StatusAccepted || !strings.HasPrefix(w.Header().Get("Location"), "/redfish/v1/TaskService/Tasks/") {
+		t.Errorf("Expected 202 with a task Location, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	// No task is created while the TaskService is disabled
+	toggleableServices["/redfish/v1/TaskService"].Store(false)
+	w = post("/redfish/v1/Systems/1/Actions/Oem/Fabrikam.Diagnose", "")
+	toggleableServices["/redfish/v1/TaskService"].Store(true)
+	if w.Code != http.StatusServiceUnavailable || errorCode(w) != baseRegistry.MessageID("ServiceDisabled") {
+		t.Errorf("Expected 503 ServiceDisabled with the TaskService disabled, got %d %s", w.Code, errorCode(w))
+	}
+
+	// Unregistered actions, other resource types and unknown systems are refused
+	if w := post("/redfish/v1/Systems/1/Actions/Oem/Fabrikam.Unknown", "{}"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unregistered action, got %d", w.Code)
+	}
+	if w := post("/redfish/v1/Managers/1/Actions/Oem/Fabrikam.SetRackSlot", `{"Slot": "U1"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an action of another resource type, got %d", w.Code)
+	}
+	if w := post("/redfish/v1/Systems/missing/Actions/Oem/Fabrikam.SetRackSlot", `{"Slot": "U1"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown system, got %d", w.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// paginate applies $skip or $skiptoken, and $top, to a collection's members.
+// An absent $top returns every member from the start of the page on, while
+// $top=0 returns an empty page. When members remain after a non-empty page,
+// nextLink is the query string that requests the next page, carrying over
+// $filter and $orderby; otherwise it is empty.
+//
+// The next page is identified by a $skiptoken naming the last member shown
+// rather than by an offset, so members added or removed in between do not
+// shift later pages. A page requested with $skiptoken starts after that
+// member, or, if it has since been removed, at the first member whose
+// @odata.id sorts after it; $skip is used when no $skiptoken is given.
+func paginate(members []models.Link, params *QueryParameters) (page []models.Link, nextLink string) {
+	if params == nil {
+		return members, ""
+	}
+
+	// Offsets are clamped to the members and $top is compared with the
+	// members remaining rather than added to start, which could overflow
+	total := len(members)
+	start := max(min(params.Skip, total), 0)
+	if params.SkipToken != "" {
+		start = resumeAfter(members, params.SkipToken)
+	}
+	end := total
+	if params.Top != nil {
+		if top := max(*params.Top, 0); top < total-start {
+			end = start + top
+		}
+	}
+
+	page = members[start:end]
+	if end > start && end < total {
+		nextLink = nextPageQuery(page[len(page)-1].ODataID, params)
+	}
+	return page, nextLink
+}
+
+// resumeAfter returns the index of the first member following the one with
+// the given @odata.id
+func resumeAfter(members []models.Link, last models.ODataID) int {
+	for i, member := range members {
+		if member.ODataID == last {
+			return i + 1
+		}
+	}
+	for i, member := range members {
+		if member.ODataID > last {
+			return i
+		}
+	}
+	return len(members)
+}
+
+// encodeSkipToken and decodeSkipToken convert between the @odata.id of the
+// last member on a page and the opaque $skiptoken that resumes after it
+func encodeSkipToken(last models.ODataID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(last))
+}
+
+func decodeSkipToken(token string) (models.ODataID, error) {
+	last, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(last) == 0 {
+		return "", queryValueFormatError("invalid $skiptoken parameter: %s", token)
+	}
+	return models.ODataID(last), nil
+}
+
+// nextPageQuery builds the query string of the page following last
+func nextPageQuery(last models.ODataID, params *QueryParameters) string {
+	query := []string{"$skiptoken=" + encodeSkipToken(last), "$top=" + strconv.Itoa(*params.Top)}
+	if params.Filter != "" {
+		query = append(query, "$filter="+url.QueryEscape(params.Filter))
+	}
+	if params.OrderBy != "" {
+		query = append(query, "$orderby="+url.QueryEscape(params.OrderBy))
+	}
+	return "?" + strings.Join(query, "&")
+}
+
+// maxEmbeddedMembers caps the members of a collection that $expand embeds in
+// another resource. The remaining members are reached through
+// Members@odata.nextLink, which points back at the collection itself.
+const maxEmbeddedMembers = 20
+
+// paginateEmbeddedCollection limits a collection embedded by $expand to its
+// first page of maxEmbeddedMembers
+func paginateEmbeddedCollection(collection *models.Collection) {
+	top := maxEmbeddedMembers
+	paginateCollection(collection, &QueryParameters{Top: &top})
+}
+
+// paginateCollection replaces a collection's members with the requested page
+// and links the next page, if any, from Members@odata.nextLink.
+// Members@odata.count keeps the size of the whole collection, as OData requires,
+// and an empty page is still sent as Members: [].
+func paginateCollection(collection *models.Collection, params *QueryParameters) {
+	page, nextLink := paginate(collection.Members, params)
+	collection.MembersODataCount = len(collection.Members)
+	collection.Members = models.CollectionMembers(page)
+	if nextLink != "" {
+		collection.MembersNextLink = collection.ODataID + models.ODataID(nextLink)
+	}
+}
@@ -0,0 +1,530 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+func memberLinks(n int) []models.Link {
+	members := make([]models.Link, n)
+	for i := range members {
+		members[i] = models.Link{ODataID: models.ODataID(fmt.Sprintf("/redfish/v1/Systems/%d", i+1))}
+	}
+	return members
+}
+
+func top(n int) *int {
+	return &n
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name      string
+		members   int
+		params    QueryParameters
+		wantFirst int // 1-based index of the first member on the page, 0 for an empty page
+		wantLen   int
+		wantNext  string
+	}{
+		{"empty", 0, QueryParameters{Top: top(2)}, 0, 0, ""},
+		{"single page", 3, QueryParameters{}, 1, 3, ""},
+		{"top covers all", 3, QueryParameters{Top: top(5)}, 1, 3, ""},
+		{"first of many", 5, QueryParameters{Top: top(2)}, 1, 2, "?$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/2") + "&$top=2"},
+		{"middle page", 5, QueryParameters{Top: top(2), Skip: 2}, 3, 2, "?$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/4") + "&$top=2"},
+		{"last page", 5, QueryParameters{Top: top(2), Skip: 4}, 5, 1, ""},
+		{"skip without top", 5, QueryParameters{Skip: 3}, 4, 2, ""},
+		{"skip at end", 5, QueryParameters{Skip: 5}, 0, 0, ""},
+		{"skip beyond end", 5, QueryParameters{Top: top(2), Skip: 9}, 0, 0, ""},
+		{"zero top", 5, QueryParameters{Top: top(0)}, 0, 0, ""},
+		{"zero top after skip", 5, QueryParameters{Top: top(0), Skip: 2}, 0, 0, ""},
+		{"next keeps filter", 3, QueryParameters{Top: top(1), Filter: "TaskState eq 'New'"}, 1, 1, "?$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/1") + "&$top=1&$filter=TaskState+eq+%27New%27"},
+		{"skiptoken", 5, QueryParameters{Top: top(2), SkipToken: "/redfish/v1/Systems/2"}, 3, 2, "?$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/4") + "&$top=2"},
+		{"skiptoken overrides skip", 5, QueryParameters{Top: top(2), Skip: 4, SkipToken: "/redfish/v1/Systems/1"}, 2, 2, "?$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/3") + "&$top=2"},
+		{"skiptoken of removed member", 5, QueryParameters{Top: top(2), SkipToken: "/redfish/v1/Systems/2a"}, 3, 2, "?$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/4") + "&$top=2"},
+		{"skiptoken at end", 5, QueryParameters{Top: top(2), SkipToken: "/redfish/v1/Systems/5"}, 0, 0, ""},
+		{"skip equals total with top", 5, QueryParameters{Top: top(2), Skip: 5}, 0, 0, ""},
+		{"top equals remaining", 5, QueryParameters{Top: top(3), Skip: 2}, 3, 3, ""},
+		{"huge top", 5, QueryParameters{Top: top(math.MaxInt)}, 1, 5, ""},
+		{"huge top after skip", 5, QueryParameters{Top: top(math.MaxInt), Skip: 3}, 4, 2, ""},
+		{"huge skip", 5, QueryParameters{Top: top(2), Skip: math.MaxInt}, 0, 0, ""},
+		{"huge top after skiptoken", 5, QueryParameters{Top: top(math.MaxInt), SkipToken: "/redfish/v1/Systems/1"}, 2, 4, ""},
+		{"negative skip", 5, QueryParameters{Top: top(2), Skip: -3}, 1, 2, "?$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/2") + "&$top=2"},
+		{"negative top", 5, QueryParameters{Top: top(-1)}, 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, next := paginate(memberLinks(tt.members), &tt.params)
+			if len(page) != tt.wantLen {
+				t.Fatalf("Expected %d members, got %d", tt.wantLen, len(page))
+			}
+			if tt.wantLen > 0 && page[0].ODataID != models.ODataID(fmt.Sprintf("/redfish/v1/Systems/%d", tt.wantFirst)) {
+				t.Errorf("Expected page to start at member %d, got %s", tt.wantFirst, page[0].ODataID)
+			}
+			if next != tt.wantNext {
+				t.Errorf("Expected nextLink %q, got %q", tt.wantNext, next)
+			}
+		})
+	}
+}
+
+func FuzzPaginate(f *testing.F) {
+	for _, seed := range [][3]int{{0, 0, 0}, {5, 0, 2}, {5, 5, 2}, {5, 4, 1}, {5, 9, 2}, {5, 0, math.MaxInt}, {5, 3, math.MaxInt}, {5, math.MaxInt, 1}, {5, -1, -1}} {
+		f.Add(seed[0], seed[1], seed[2])
+	}
+	f.Fuzz(func(t *testing.T, members, skip, n int) {
+		members = min(max(members, 0), 100)
+		all := memberLinks(members)
+		page, next := paginate(all, &QueryParameters{Skip: skip, Top: top(n)})
+
+		// The page is a run of consecutive members starting at the clamped $skip
+		start := min(max(skip, 0), members)
+		if len(page) > max(n, 0) || len(page) > members-start {
+			t.Fatalf("Page of %d members for %d members, $skip=%d, $top=%d", len(page), members, skip, n)
+		}
+		for i, member := range page {
+			if member != all[start+i] {
+				t.Fatalf("Member %d of the page is %s, expected %s", i, member.ODataID, all[start+i].ODataID)
+			}
+		}
+		if remaining := members - start - len(page); (next != "") != (len(page) > 0 && remaining > 0) {
+			t.Fatalf("nextLink %q with %d members remaining after a page of %d", next, remaining, len(page))
+		}
+	})
+}
+
+func TestPaginateCollectionNextLink(t *testing.T) {
+	collection := models.Collection{ODataID: "/redfish/v1/Systems", Members: memberLinks(3)}
+	paginateCollection(&collection, &QueryParameters{Top: top(2)})
+	if collection.MembersNextLink != models.ODataID("/redfish/v1/Systems?$skiptoken="+encodeSkipToken("/redfish/v1/Systems/2")+"&$top=2") {
+		t.Errorf("Unexpected Members@odata.nextLink %q", collection.MembersNextLink)
+	}
+}
+
+func TestSystemsTopZeroAndUnset(t *testing.T) {
+	for _, id := range []string{"2", "3"} {
+		systemStore.Put(id, models.NewComputerSystem(id))
+		defer systemStore.Delete(id)
+	}
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	tests := []struct {
+		query     string
+		wantPage  int
+		wantCount int
+	}{
+		{"", 3, 3},
+		{"?$top=0", 0, 3},
+		{"?$skip=1&$top=0", 0, 3},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/redfish/v1/Systems"+tt.query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d", tt.query, w.Code)
+		}
+
+		var collection models.Collection
+		if err := json.NewDecoder(w.Body).Decode(&collection); err != nil {
+			t.Fatalf("%q: failed to decode collection: %v", tt.query, err)
+		}
+		if collection.Members == nil || len(collection.Members) != tt.wantPage {
+			t.Errorf("%q: expected a page of %d members, got %v", tt.query, tt.wantPage, collection.Members)
+		}
+		if collection.MembersODataCount != tt.wantCount {
+			t.Errorf("%q: expected Members@odata.count %d, got %d", tt.query, tt.wantCount, collection.MembersODataCount)
+		}
+		if collection.MembersNextLink != "" {
+			t.Errorf("%q: expected no nextLink, got %s", tt.query, collection.MembersNextLink)
+		}
+	}
+
+	// A $top near the integer limit must not overflow the page bounds
+	req := httptest.NewRequest("GET", fmt.Sprintf("/redfish/v1/Systems?$skip=1&$top=%d", math.MaxInt), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var collection models.Collection
+	json.Unmarshal(w.Body.Bytes(), &collection)
+	if w.Code != http.StatusOK || len(collection.Members) != 2 {
+		t.Errorf("Expected the last 2 systems for a huge $top, got status %d and %d members", w.Code, len(collection.Members))
+	}
+
+	req = httptest.NewRequest("GET", "/redfish/v1/Systems?$top=-1", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected negative $top to be rejected with 400, got %d", w.Code)
+	}
+}
+
+// collectPages follows Members@odata.nextLink from uri and returns the
+// members of every page and the number of pages
+func collectPages(t *testing.T, mux *http.ServeMux, uri string) ([]models.Link, int) {
+	t.Helper()
+
+	var members []models.Link
+	pages := 0
+	for uri != "" {
+		req := httptest.NewRequest("GET", uri, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d", uri, w.Code)
+		}
+		var collection models.Collection
+		json.Unmarshal(w.Body.Bytes(), &collection)
+		members = append(members, collection.Members...)
+		uri = string(collection.MembersNextLink)
+		pages++
+	}
+	return members, pages
+}
+
+func TestPaginateTasks(t *testing.T) {
+	tasksMutex.Lock()
+	for i := range 25 {
+		id := fmt.Sprintf("page-%02d", i)
+		tasks[id] = models.NewTask(id, "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+	}
+	total := len(tasks)
+	tasksMutex.Unlock()
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		defer tasksMutex.Unlock()
+		for i := range 25 {
+			delete(tasks, fmt.Sprintf("page-%02d", i))
+		}
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	members, pages := collectPages(t, mux, "/redfish/v1/TaskService/Tasks?$top=10")
+	if len(members) != total || pages != (total+9)/10 {
+		t.Fatalf("Expected %d tasks over %d pages, got %d over %d", total, (total+9)/10, len(members), pages)
+	}
+	seen := make(map[models.ODataID]bool)
+	for _, member := range members {
+		if seen[member.ODataID] {
+			t.Errorf("Task %s listed twice", member.ODataID)
+		}
+		seen[member.ODataID] = true
+	}
+}
+
+func TestExpandTaskMembers(t *testing.T) {
+	tasksMutex.Lock()
+	for i := range 5 {
+		id := fmt.Sprintf("expand-%02d", i)
+		tasks[id] = models.NewTask(id, "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset")
+	}
+	tasks["expand-01"].SetPercentComplete(40)
+	tasksMutex.Unlock()
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		defer tasksMutex.Unlock()
+		for i := range 5 {
+			delete(tasks, fmt.Sprintf("expand-%02d", i))
+		}
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/redfish/v1/TaskService/Tasks?$expand=Members&$filter=Id%20ge%20'expand-'%20and%20Id%20lt%20'expand-99'&$top=3&$skip=1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var collection struct {
+		Count    int                      `json:"Members@odata.count"`
+		NextLink string                   `json:"Members@odata.nextLink"`
+		Members  []map[string]interface{} `json:"Members"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &collection)
+	if collection.Count != 5 || len(collection.Members) != 3 || collection.NextLink == "" {
+		t.Fatalf("Expected 3 of 5 expanded tasks with a nextLink, got %d of %d", len(collection.Members), collection.Count)
+	}
+	for i, member := range collection.Members {
+		if want := fmt.Sprintf("expand-%02d", i+1); member["Id"] != want {
+			t.Errorf("Expected member %d to be %s, got %v", i, want, member["Id"])
+		}
+		if _, ok := member["TaskState"]; !ok {
+			t.Errorf("Expected member %d to include TaskState", i)
+		}
+		if _, ok := member["PercentComplete"]; !ok {
+			t.Errorf("Expected member %d to include PercentComplete", i)
+		}
+	}
+	if collection.Members[0]["PercentComplete"] != float64(40) {
+		t.Errorf("Expected expand-01 at 40 percent, got %v", collection.Members[0]["PercentComplete"])
+	}
+}
+
+func TestExpandedSubCollectionIsPaginated(t *testing.T) {
+	tasksMutex.Lock()
+	saved := tasks
+	tasks = make(map[string]*models.Task)
+	for i := range 45 {
+		id := fmt.Sprintf("embedded-%02d", i)
+		tasks[id] = models.NewTask(id, "POST", "/redfish/v1/TaskService/Tasks")
+	}
+	tasksMutex.Unlock()
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		tasks = saved
+		tasksMutex.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	get := func(path string, v interface{}) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d: %s", path, w.Code, w.Body.String())
+		}
+		json.Unmarshal(w.Body.Bytes(), v)
+	}
+
+	var taskService struct {
+		Tasks struct {
+			ODataID  string                   `json:"@odata.id"`
+			Count    int                      `json:"Members@odata.count"`
+			NextLink string                   `json:"Members@odata.nextLink"`
+			Members  []map[string]interface{} `json:"Members"`
+		} `json:"Tasks"`
+	}
+	get("/redfish/v1/TaskService?$expand=*", &taskService)
+	embedded := taskService.Tasks
+	if embedded.ODataID != "/redfish/v1/TaskService/Tasks" || embedded.Count != 45 || len(embedded.Members) != maxEmbeddedMembers {
+		t.Fatalf("Expected the first %d of 45 tasks embedded, got %d of %d", maxEmbeddedMembers, len(embedded.Members), embedded.Count)
+	}
+	if !strings.HasPrefix(embedded.NextLink, "/redfish/v1/TaskService/Tasks?") {
+		t.Fatalf("Expected a nextLink to the Tasks collection, got %q", embedded.NextLink)
+	}
+
+	// The nextLink continues where the embedded page stopped
+	var next models.Collection
+	get(embedded.NextLink, &next)
+	if len(next.Members) != maxEmbeddedMembers || next.Members[0].ODataID != "/redfish/v1/TaskService/Tasks/embedded-20" {
+		t.Errorf("Expected the next page to start at embedded-20, got %+v", next.Members)
+	}
+
+	// A second level inlines the embedded tasks themselves, still capped
+	get("/redfish/v1/TaskService?$expand=*($levels=2)", &taskService)
+	embedded = taskService.Tasks
+	if len(embedded.Members) != maxEmbeddedMembers || embedded.NextLink == "" || embedded.Members[0]["TaskState"] != "New" {
+		t.Errorf("Expected %d inline tasks with a nextLink, got %d (%q)", maxEmbeddedMembers, len(embedded.Members), embedded.NextLink)
+	}
+
+	// Without $expand the TaskService only links its Tasks
+	var plain map[string]interface{}
+	get("/redfish/v1/TaskService", &plain)
+	if tasksLink, _ := plain["Tasks"].(map[string]interface{}); len(tasksLink) != 1 {
+		t.Errorf("Expected a bare link to Tasks, got %v", plain["Tasks"])
+	}
+}
+
+func TestPaginateSubscriptions(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	for i := range 25 {
+		id := fmt.Sprintf("sub-%02d", i)
+		if err := addSubscription(models.NewEventSubscription(id, "https://listener.example.com/events", "Redfish")); err != nil {
+			t.Fatalf("Failed to add subscription: %v", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/redfish/v1/EventService/Subscriptions?$skip=20&$top=10", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var collection models.Collection
+	json.Unmarshal(w.Body.Bytes(), &collection)
+	if collection.MembersODataCount != 25 || len(collection.Members) != 5 || collection.MembersNextLink != "" {
+		t.Errorf("Expected the last 5 of 25 subscriptions and no nextLink, got %d of %d with nextLink %q", len(collection.Members), collection.MembersODataCount, collection.MembersNextLink)
+	}
+
+	members, pages := collectPages(t, mux, "/redfish/v1/EventService/Subscriptions?$top=10")
+	if len(members) != 25 || pages != 3 {
+		t.Fatalf("Expected 25 subscriptions over 3 pages, got %d over %d", len(members), pages)
+	}
+	for i, member := range members {
+		if want := models.ODataID(fmt.Sprintf("/redfish/v1/EventService/Subscriptions/sub-%02d", i)); member.ODataID != want {
+			t.Errorf("Expected member %d to be %s, got %s", i, want, member.ODataID)
+		}
+	}
+}
+
+func TestEmptyCollectionsListNoMembers(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	tasksMutex.Lock()
+	saved := tasks
+	tasks = make(map[string]*models.Task)
+	tasksMutex.Unlock()
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		tasks = saved
+		tasksMutex.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	// Every collection, whether empty or paged past its end, sends an empty
+	// Members array and a zero-based count rather than null
+	for _, path := range []string{
+		"/redfish/v1/EventService/Subscriptions",
+		"/redfish/v1/TaskService/Tasks",
+		"/redfish/v1/SessionService/Sessions",
+		"/redfish/v1/Systems?$top=0",
+		"/redfish/v1/Systems?$skip=10",
+		"/redfish/v1/Systems?$filter=PowerState%20eq%20'Off'",
+		"/redfish/v1/Chassis?$skip=10",
+		"/redfish/v1/Managers?$top=0",
+		"/redfish/v1/EventService/Subscriptions?$skip=10",
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: failed to decode %d response: %v", path, w.Code, err)
+			continue
+		}
+		if members := string(body["Members"]); members != "[]" {
+			t.Errorf("%s: expected Members [], got %s", path, members)
+		}
+		if _, ok := body["Members@odata.count"]; !ok {
+			t.Errorf("%s: expected Members@odata.count", path)
+		}
+	}
+}
+
+func TestSkipTokenStableAcrossInsertion(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	for i := range 25 {
+		if err := addSubscription(models.NewEventSubscription(fmt.Sprintf("sub-%02d", i), "https://listener.example.com/events", "Redfish")); err != nil {
+			t.Fatalf("Failed to add subscription: %v", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/redfish/v1/EventService/Subscriptions?$top=10", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var first models.Collection
+	json.Unmarshal(w.Body.Bytes(), &first)
+	if len(first.Members) != 10 || first.MembersNextLink == "" {
+		t.Fatalf("Expected a first page of 10 with a nextLink, got %d, %q", len(first.Members), first.MembersNextLink)
+	}
+
+	// A member inserted ahead of the cursor would shift an offset-based page
+	if err := addSubscription(models.NewEventSubscription("sub-04a", "https://listener.example.com/events", "Redfish")); err != nil {
+		t.Fatalf("Failed to add subscription: %v", err)
+	}
+
+	rest, _ := collectPages(t, mux, string(first.MembersNextLink))
+	members := append(first.Members, rest...)
+	if len(members) != 25 {
+		t.Fatalf("Expected the 25 original subscriptions, got %d", len(members))
+	}
+	for i, member := range members {
+		if want := models.ODataID(fmt.Sprintf("/redfish/v1/EventService/Subscriptions/sub-%02d", i)); member.ODataID != want {
+			t.Errorf("Expected member %d to be %s, got %s", i, want, member.ODataID)
+		}
+	}
+}
+
+func TestQueryParameterErrorMessageIDs(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"$top=abc", "QueryParameterValueFormatError"},
+		{"$top=-1", "QueryParameterOutOfRange"},
+		{"$skip=1.5", "QueryParameterValueFormatError"},
+		{"$skip=-3", "QueryParameterOutOfRange"},
+		{"$skiptoken=!!!", "QueryParameterValueFormatError"},
+		{"$skip=1&$skiptoken=" + encodeSkipToken("/redfish/v1/Systems/1"), "QueryCombinationInvalid"},
+		{"$expand=*($levels=x)", "QueryParameterValueFormatError"},
+		{"$expand=*($levels=0)", "QueryParameterOutOfRange"},
+		{"$expand=*($levels=99)", "QueryParameterOutOfRange"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/redfish/v1/Systems?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", tt.query, w.Code)
+			continue
+		}
+
+		var errorResponse models.RedfishError
+		if err := json.NewDecoder(w.Body).Decode(&errorResponse); err != nil {
+			t.Fatalf("%s: failed to decode error: %v", tt.query, err)
+		}
+		if got := errorResponse.Error.Code; got != baseRegistry.MessageID(tt.want) {
+			t.Errorf("%s: expected %s, got %s", tt.query, baseRegistry.MessageID(tt.want), got)
+		}
+	}
+}
+
+func TestConflictingQueryCombinations(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"only", http.StatusOK},
+		{"excerpt", http.StatusOK},
+		{"$top=1", http.StatusOK},
+		{"$expand=Members", http.StatusOK},
+		{"only&$top=1", http.StatusBadRequest},
+		{"$skip=1&only", http.StatusBadRequest},
+		{"$expand=Members&excerpt", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/redfish/v1/Systems?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.query, tt.want, w.Code)
+			continue
+		}
+		if tt.want != http.StatusBadRequest {
+			continue
+		}
+
+		var errorResponse models.RedfishError
+		json.NewDecoder(w.Body).Decode(&errorResponse)
+		if errorResponse.Error.Code != baseRegistry.MessageID("QueryCombinationInvalid") {
+			t.Errorf("%s: expected QueryCombinationInvalid, got %s", tt.query, errorResponse.Error.Code)
+		}
+	}
+}
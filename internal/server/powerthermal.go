@@ -0,0 +1,145 @@
+package server
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+// powerThermalModel selects which power and thermal resources chassis
+// expose: Legacy, Subsystems or Both. New sets it from the configuration.
+var powerThermalModel atomic.Pointer[string]
+
+func init() {
+	setPowerThermalModel("")
+}
+
+// setPowerThermalModel applies the configured power and thermal resources,
+// with an empty model selecting Both
+func setPowerThermalModel(model string) {
+	if model == "" {
+		model = config.DefaultPowerThermalModel
+	}
+	powerThermalModel.Store(&model)
+	resourceResponses.clear()
+}
+
+// servesLegacyPowerThermal reports whether chassis expose Power and Thermal
+func servesLegacyPowerThermal() bool {
+	return *powerThermalModel.Load() != "Subsystems"
+}
+
+// servesPowerThermalSubsystems reports whether chassis expose
+// PowerSubsystem and ThermalSubsystem
+func servesPowerThermalSubsystems() bool {
+	return *powerThermalModel.Load() != "Legacy"
+}
+
+// applyPowerThermalLinks links a chassis to the power and thermal resources
+// it exposes
+func applyPowerThermalLinks(chassis *models.Chassis) {
+	if !servesLegacyPowerThermal() {
+		chassis.Power = ""
+		chassis.Thermal = ""
+	}
+	if !servesPowerThermalSubsystems() {
+		chassis.PowerSubsystem = nil
+		chassis.ThermalSubsystem = nil
+	}
+}
+
+// chassisPowerThermalHandler serves the power and thermal resources of a
+// chassis, all of which are read only
+func chassisPowerThermalHandler(w http.ResponseWriter, r *http.Request, chassisID string, subPath []string) {
+	build, ok := powerThermalResource(chassisID, subPath)
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Allow", "GET")
+	if r.Method != "GET" {
+		methodNotAllowed(w, r)
+		return
+	}
+	if !sendCachedResource(w, r, models.NewODataID(append([]string{"/redfish/v1/Chassis", chassisID}, subPath...)...), build) {
+		sendResourceNotFound(w, r)
+	}
+}
+
+// powerThermalResource returns the builder of the power or thermal resource
+// at subPath below a chassis, reporting false if there is none or the
+// configured model does not expose it. Every resource is built from the same
+// simulated environment, so legacy and subsystem readings agree.
+func powerThermalResource(chassisID string, subPath []string) (func() (interface{}, bool), bool) {
+	environment := models.NewChassisEnvironment(chassisID)
+	found := func(resource interface{}) (func() (interface{}, bool), bool) {
+		return func() (interface{}, bool) { return resource, true }, true
+	}
+
+	if servesLegacyPowerThermal() && len(subPath) == 1 {
+		switch subPath[0] {
+		case "Power":
+			return found(models.NewPower(chassisID, environment))
+		case "Thermal":
+			return found(models.NewThermal(chassisID, environment))
+		}
+	}
+	if !servesPowerThermalSubsystems() {
+		return nil, false
+	}
+
+	switch {
+	case len(subPath) == 1 && subPath[0] == "PowerSubsystem":
+		return found(models.NewPowerSubsystem(chassisID, environment))
+	case len(subPath) == 1 && subPath[0] == "ThermalSubsystem":
+		return found(models.NewThermalSubsystem(chassisID))
+	case len(subPath) == 2 && subPath[0] == "ThermalSubsystem" && subPath[1] == "ThermalMetrics":
+		return found(models.NewThermalMetrics(chassisID, environment))
+	}
+	if len(subPath) < 2 || len(subPath) > 3 {
+		return nil, false
+	}
+
+	collection := models.NewODataID("/redfish/v1/Chassis", chassisID, subPath[0], subPath[1])
+	var members []models.Link
+	var item interface{}
+	switch subPath[0] + "/" + subPath[1] {
+	case "PowerSubsystem/PowerSupplies":
+		for _, supply := range environment.PowerSupplies {
+			members = append(members, models.Link{ODataID: models.NewODataID(string(collection), supply.ID)})
+			if len(subPath) == 3 && supply.ID == subPath[2] {
+				item = models.NewPowerSupply(chassisID, supply)
+			}
+		}
+		if len(subPath) == 2 {
+			return found(models.NewPowerSupplyCollection(chassisID, members))
+		}
+	case "PowerSubsystem/Batteries":
+		for _, battery := range environment.Batteries {
+			members = append(members, models.Link{ODataID: models.NewODataID(string(collection), battery.ID)})
+			if len(subPath) == 3 && battery.ID == subPath[2] {
+				item = models.NewBattery(chassisID, battery)
+			}
+		}
+		if len(subPath) == 2 {
+			return found(models.NewBatteryCollection(chassisID, members))
+		}
+	case "ThermalSubsystem/Fans":
+		for _, fan := range environment.Fans {
+			members = append(members, models.Link{ODataID: models.NewODataID(string(collection), fan.ID)})
+			if len(subPath) == 3 && fan.ID == subPath[2] {
+				item = models.NewFan(chassisID, fan)
+			}
+		}
+		if len(subPath) == 2 {
+			return found(models.NewFanCollection(chassisID, members))
+		}
+	}
+	if item == nil {
+		return nil, false
+	}
+	return found(item)
+}
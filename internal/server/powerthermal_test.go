@@ -0,0 +1,117 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestPowerThermalSubsystems(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	get := func(path string, v interface{}) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code == http.StatusOK && v != nil {
+			if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+				t.Fatalf("Failed to decode %s: %v", path, err)
+			}
+		}
+		return w.Code
+	}
+
+	var chassis models.Chassis
+	get("/redfish/v1/Chassis/1", &chassis)
+	if chassis.PowerSubsystem == nil || chassis.ThermalSubsystem == nil {
+		t.Fatalf("Expected the chassis to link its subsystems, got %+v, %+v", chassis.PowerSubsystem, chassis.ThermalSubsystem)
+	}
+
+	var power models.PowerSubsystem
+	if code := get(string(chassis.PowerSubsystem.ODataID), &power); code != http.StatusOK {
+		t.Fatalf("Expected the PowerSubsystem, got %d", code)
+	}
+	var thermal models.ThermalSubsystem
+	if code := get(string(chassis.ThermalSubsystem.ODataID), &thermal); code != http.StatusOK {
+		t.Fatalf("Expected the ThermalSubsystem, got %d", code)
+	}
+
+	// Every sub-collection lists members that resolve
+	for _, collection := range []models.ODataID{power.PowerSupplies.ODataID, power.Batteries.ODataID, thermal.Fans.ODataID} {
+		var members models.Collection
+		if code := get(string(collection), &members); code != http.StatusOK || members.MembersODataCount == 0 || members.MembersODataCount != len(members.Members) {
+			t.Errorf("Expected members in %s, got %d %+v", collection, code, members)
+			continue
+		}
+		for _, member := range members.Members {
+			var item models.Resource
+			if code := get(string(member.ODataID), &item); code != http.StatusOK || item.ODataID != member.ODataID {
+				t.Errorf("Expected %s to resolve, got %d %q", member.ODataID, code, item.ODataID)
+			}
+		}
+	}
+	if code := get(string(power.PowerSupplies.ODataID)+"/99", nil); code != http.StatusNotFound {
+		t.Errorf("Expected an unknown power supply to be 404, got %d", code)
+	}
+
+	// The legacy resources report the same simulated readings
+	var metrics models.ThermalMetrics
+	get(string(thermal.ThermalMetrics.ODataID), &metrics)
+	var legacyThermal models.Thermal
+	get("/redfish/v1/Chassis/1/Thermal", &legacyThermal)
+	if len(metrics.TemperatureReadingsCelsius) == 0 || len(metrics.TemperatureReadingsCelsius) != len(legacyThermal.Temperatures) {
+		t.Fatalf("Expected matching temperature readings, got %+v and %+v", metrics.TemperatureReadingsCelsius, legacyThermal.Temperatures)
+	}
+	for i, reading := range metrics.TemperatureReadingsCelsius {
+		if reading.Reading != legacyThermal.Temperatures[i].ReadingCelsius {
+			t.Errorf("Expected %s to read the same in both models, got %v and %v", reading.DeviceName, reading.Reading, legacyThermal.Temperatures[i].ReadingCelsius)
+		}
+	}
+	var fan models.Fan
+	get(string(thermal.Fans.ODataID)+"/0", &fan)
+	if fan.SpeedPercent.SpeedRPM != legacyThermal.Fans[0].Reading {
+		t.Errorf("Expected fan 0 at %d RPM in both models, got %d", legacyThermal.Fans[0].Reading, fan.SpeedPercent.SpeedRPM)
+	}
+
+	if code := get("/redfish/v1/Chassis/missing/PowerSubsystem", nil); code != http.StatusNotFound {
+		t.Errorf("Expected the subsystem of an unknown chassis to be 404, got %d", code)
+	}
+}
+
+func TestPowerThermalModel(t *testing.T) {
+	t.Cleanup(func() { setPowerThermalModel("") })
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	tests := []struct {
+		model             string
+		legacy, subsystem bool
+	}{
+		{"Legacy", true, false},
+		{"Subsystems", false, true},
+		{"Both", true, true},
+	}
+	for _, tt := range tests {
+		setPowerThermalModel(tt.model)
+
+		var chassis models.Chassis
+		json.NewDecoder(get("/redfish/v1/Chassis/1").Body).Decode(&chassis)
+		if (chassis.Power != "") != tt.legacy || (chassis.PowerSubsystem != nil) != tt.subsystem {
+			t.Errorf("%s: expected Power linked %v and PowerSubsystem linked %v, got %q and %v", tt.model, tt.legacy, tt.subsystem, chassis.Power, chassis.PowerSubsystem)
+		}
+		if served := get("/redfish/v1/Chassis/1/Thermal").Code == http.StatusOK; served != tt.legacy {
+			t.Errorf("%s: expected Thermal served %v", tt.model, tt.legacy)
+		}
+		if served := get("/redfish/v1/Chassis/1/ThermalSubsystem/Fans").Code == http.StatusOK; served != tt.subsystem {
+			t.Errorf("%s: expected the Fans collection served %v", tt.model, tt.subsystem)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// requestIDHeader carries the id a request is logged under. A client or
+// proxy may supply one; otherwise it is generated, and it is always echoed
+// in the response so a failure can be matched with the log.
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds a supplied request id, so it cannot flood the log
+const maxRequestIDLength = 128
+
+// requestID returns the id of a request: the supplied X-Request-Id if it is
+// short printable ASCII, or a new random id
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(requestIDHeader); id != "" && len(id) <= maxRequestIDLength && isPrintableASCII(id) {
+		return id
+	}
+	var id [8]byte
+	rand.Read(id[:])
+	return fmt.Sprintf("%x", id)
+}
+
+// isPrintableASCII reports whether s holds only printable ASCII characters
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// recoveryMiddleware turns a panicking handler into a logged Redfish
+// InternalError 500, rather than a connection dropped without a response. A
+// handler that already started its response can only have it cut short.
+// http.ErrAbortHandler, which handlers panic with to abort on purpose, is
+// passed on to net/http.
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestID(r)
+		w.Header().Set(requestIDHeader, id)
+		recorder := &recoveryWriter{ResponseWriter: w}
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+			log.Printf("panic serving %s %s (request %s): %v\n%s", r.Method, r.URL.Path, id, recovered, debug.Stack())
+			if recorder.wroteHeader {
+				return
+			}
+			// Headers describing the resource the handler meant to send no
+			// longer apply; those set by the middleware, such as CORS, still do
+			for _, name := range []string{"ETag", "Location", "Content-Length", "Content-Encoding"} {
+				w.Header().Del(name)
+			}
+			setRedfishHeaders(w)
+			sendRedfishError(w, "InternalError", "The request failed unexpectedly; request "+id, http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// recoveryWriter records whether a response has started
+type recoveryWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *recoveryWriter) WriteHeader(code int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recoveryWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the underlying writer so streaming handlers keep working
+func (rw *recoveryWriter) Flush() {
+	rw.wroteHeader = true
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *recoveryWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
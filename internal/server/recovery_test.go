@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	handler := recoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"stale"`)
+		panic("handler bug")
+	}))
+
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems/1", nil)
+	req.Header.Set(requestIDHeader, "trace-42")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected a JSON error body, got Content-Type %s", ct)
+	}
+	if w.Header().Get("ETag") != "" {
+		t.Error("Expected the handler's ETag to be dropped")
+	}
+	if id := w.Header().Get(requestIDHeader); id != "trace-42" {
+		t.Errorf("Expected the supplied request id to be echoed, got %q", id)
+	}
+	var errorResponse models.RedfishError
+	if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+		t.Fatalf("Invalid JSON error body: %v", err)
+	}
+	if errorResponse.Error.Code != baseRegistry.MessageID("InternalError") {
+		t.Errorf("Expected InternalError, got %s", errorResponse.Error.Code)
+	}
+
+	// Requests without an id get a generated one
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/redfish/v1/Systems/1", nil))
+	if w.Header().Get(requestIDHeader) == "" {
+		t.Error("Expected a generated request id")
+	}
+
+	// http.ErrAbortHandler still aborts the response
+	abort := recoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	defer func() {
+		if recover() != http.ErrAbortHandler {
+			t.Error("Expected http.ErrAbortHandler to be re-raised")
+		}
+	}()
+	abort.ServeHTTP(httptest.NewRecorder(), req)
+}
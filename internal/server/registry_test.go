@@ -0,0 +1,182 @@
+package server
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// TestErrorCodesInRegistry checks that every message key passed to
+// sendRedfishError or sendRedfishMessage anywhere in this package exists in
+// the Base registry
+func TestErrorCodesInRegistry(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Failed to list package files: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	codes := make(map[string]token.Position)
+	for _, name := range files {
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", name, err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 {
+				return true
+			}
+			if ident, ok := call.Fun.(*ast.Ident); !ok || (ident.Name != "sendRedfishError" && ident.Name != "sendRedfishMessage") {
+				return true
+			}
+			if lit, ok := call.Args[1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				code, _ := strconv.Unquote(lit.Value)
+				codes[code] = fset.Position(lit.Pos())
+			}
+			return true
+		})
+	}
+
+	if len(codes) == 0 {
+		t.Fatal("Expected to find sendRedfishError calls")
+	}
+	for code, pos := range codes {
+		if _, ok := baseRegistry.Messages[code]; !ok {
+			t.Errorf("%s: message key %q is not in the Base registry", pos, code)
+		}
+	}
+}
+
+func TestErrorMessageIDResolvesAgainstServedRegistry(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	// Trigger an error response
+	req := httptest.NewRequest("DELETE", "/redfish/v1/Systems", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected status 405, got %d", w.Code)
+	}
+
+	var errorResponse models.RedfishError
+	if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+		t.Fatalf("Invalid error response: %v", err)
+	}
+	if len(errorResponse.Error.Details) != 1 {
+		t.Fatalf("Expected one extended info entry, got %d", len(errorResponse.Error.Details))
+	}
+	messageID := errorResponse.Error.Details[0].MessageID
+	if messageID != "Base.1.0.OperationNotAllowed" {
+		t.Errorf("Expected MessageId Base.1.0.OperationNotAllowed, got %s", messageID)
+	}
+
+	// Follow the registry file to the registry it locates
+	req = httptest.NewRequest("GET", "/redfish/v1/Registries/Base.1.0.0", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var registryFile models.MessageRegistryFile
+	if err := json.Unmarshal(w.Body.Bytes(), &registryFile); err != nil {
+		t.Fatalf("Invalid registry file response: %v", err)
+	}
+
+	req = httptest.NewRequest("GET", registryFile.Location[0].Uri, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for %s, got %d", registryFile.Location[0].Uri, w.Code)
+	}
+	var registry models.MessageRegistry
+	if err := json.Unmarshal(w.Body.Bytes(), &registry); err != nil {
+		t.Fatalf("Invalid registry response: %v", err)
+	}
+
+	parts := strings.Split(messageID, ".")
+	if prefix := parts[0]; prefix != registry.RegistryPrefix {
+		t.Errorf("MessageId prefix %s does not match registry prefix %s", prefix, registry.RegistryPrefix)
+	}
+	if !strings.HasPrefix(registry.RegistryVersion, parts[1]+"."+parts[2]+".") {
+		t.Errorf("MessageId version %s.%s does not match registry version %s", parts[1], parts[2], registry.RegistryVersion)
+	}
+	if _, ok := registry.Messages[parts[3]]; !ok {
+		t.Errorf("MessageId %s not found in served registry", messageID)
+	}
+}
+
+func TestDisallowedVersusUnimplementedMethods(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, tc := range []struct {
+		method, uri string
+		status      int
+		key         string
+	}{
+		// Chassis are never deleted, so DELETE is disallowed outright
+		{"DELETE", "/redfish/v1/Chassis/1", http.StatusMethodNotAllowed, "OperationNotAllowed"},
+		// PATCH is valid on a chassis but not implemented yet
+		{"PATCH", "/redfish/v1/Chassis/1", http.StatusNotImplemented, "OperationNotImplemented"},
+		{"PATCH", "/redfish/v1/AccountService/Accounts/admin", http.StatusNotImplemented, "OperationNotImplemented"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.uri, strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != tc.status {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.uri, tc.status, w.Code)
+		}
+		var errorResponse models.RedfishError
+		json.Unmarshal(w.Body.Bytes(), &errorResponse)
+		if len(errorResponse.Error.Details) != 1 || errorResponse.Error.Details[0].MessageID != baseRegistry.MessageID(tc.key) {
+			t.Errorf("%s %s: expected %s, got %s", tc.method, tc.uri, tc.key, w.Body.String())
+		}
+	}
+
+	// Allow lists the unimplemented methods but not the disallowed ones
+	req := httptest.NewRequest("GET", "/redfish/v1/Chassis/1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if allow := w.Header().Get("Allow"); allow != "GET, PATCH, PUT" {
+		t.Errorf("Expected Allow GET, PATCH, PUT on a chassis, got %q", allow)
+	}
+}
+
+func TestResourceNotFoundNamesTheURI(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, path := range []string{
+		"/redfish/v1/Systems/no-such-system",
+		"/redfish/v1/TaskService/Tasks/no-such-task",
+		"/redfish/v1/Registries/NoSuchRegistry",
+		"/redfish/v1/Managers/no-such-manager/SerialInterfaces",
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s, got %d", path, w.Code)
+			continue
+		}
+
+		var errorResponse models.RedfishError
+		if err := json.NewDecoder(w.Body).Decode(&errorResponse); err != nil {
+			t.Fatalf("Expected a Redfish error body for %s: %v", path, err)
+		}
+		want := "The requested resource " + path + " was not found"
+		if errorResponse.Error.Code != baseRegistry.MessageID("ResourceNotFound") || errorResponse.Error.Message != want {
+			t.Errorf("Expected ResourceNotFound %q, got %s %q", want, errorResponse.Error.Code, errorResponse.Error.Message)
+		}
+		if details := errorResponse.Error.Details; len(details) != 1 || len(details[0].MessageArgs) != 1 || details[0].MessageArgs[0] != path {
+			t.Errorf("Expected MessageArgs [%s], got %+v", path, details)
+		}
+	}
+}
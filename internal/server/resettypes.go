@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+// Reset types supported by systems and managers that have no configured set
+var (
+	defaultSystemResetTypes  = []string{"On", "ForceOff", "ForceRestart", "Nmi", "PushPowerButton", "GracefulRestart", "GracefulShutdown", "ForceOn"}
+	defaultManagerResetTypes = []string{"ForceRestart", "GracefulRestart"}
+)
+
+// resetTypes is the configured reset types per system and manager. Both the
+// Reset ActionInfo and the Reset action read from it, so what is advertised
+// and what is accepted cannot drift. New sets it from the configuration.
+var resetTypes atomic.Pointer[config.ResetConfig]
+
+func init() {
+	resetTypes.Store(&config.ResetConfig{})
+}
+
+// setResetTypes applies the configured reset types
+func setResetTypes(cfg config.ResetConfig) {
+	resetTypes.Store(&config.ResetConfig{
+		SystemTypes:  maps.Clone(cfg.SystemTypes),
+		ManagerTypes: maps.Clone(cfg.ManagerTypes),
+	})
+	resourceResponses.clear()
+}
+
+// systemResetTypes returns the reset types a computer system supports
+func systemResetTypes(systemID string) []string {
+	if allowed, ok := resetTypes.Load().SystemTypes[systemID]; ok {
+		return slices.Clone(allowed)
+	}
+	return slices.Clone(defaultSystemResetTypes)
+}
+
+// managerResetTypes returns the reset types a manager supports
+func managerResetTypes(managerID string) []string {
+	if allowed, ok := resetTypes.Load().ManagerTypes[managerID]; ok {
+		return slices.Clone(allowed)
+	}
+	return slices.Clone(defaultManagerResetTypes)
+}
+
+// defaultResetType is the reset type used when a request omits ResetType:
+// preferred if the resource supports it, otherwise its first supported type
+func defaultResetType(allowed []string, preferred string) string {
+	if slices.Contains(allowed, preferred) || len(allowed) == 0 {
+		return preferred
+	}
+	return allowed[0]
+}
+
+// resetSteps returns the steps of a composite reset, which are carried out
+// as sub-tasks: a restart or power cycle powers the system off and back on.
+// Other resets are a single step and have none.
+func resetSteps(resetType string) []string {
+	switch resetType {
+	case "ForceRestart", "GracefulRestart", "PowerCycle", "FullPowerCycle":
+		return []string{"Power off", "Power on"}
+	}
+	return nil
+}
+
+// resetPowerState returns the PowerState a reset leaves a system in when it
+// starts out in powerState
+func resetPowerState(resetType, powerState string) string {
+	switch resetType {
+	case "ForceOff", "GracefulShutdown":
+		return "Off"
+	case "PushPowerButton":
+		if powerState == "On" {
+			return "Off"
+		}
+		return "On"
+	case "Nmi", "Suspend", "Pause", "Resume":
+		return powerState
+	}
+	return "On"
+}
+
+// checkResetPowerState rejects a reset that would do nothing, or cannot be
+// carried out, in the system's current PowerState: powering on a system that
+// is on, or shutting down, restarting or interrupting one that is off
+func checkResetPowerState(resetType, powerState string) error {
+	switch {
+	case powerState == "On" && (resetType == "On" || resetType == "ForceOn"):
+		return fmt.Errorf("ResetType %s is not supported while the system is already On", resetType)
+	case powerState == "Off" && slices.Contains([]string{"ForceOff", "GracefulShutdown", "GracefulRestart", "Nmi"}, resetType):
+		return fmt.Errorf("ResetType %s is not supported while the system is Off", resetType)
+	}
+	return nil
+}
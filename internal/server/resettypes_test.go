@@ -0,0 +1,257 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestConfiguredResetTypes(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		Reset: config.ResetConfig{
+			SystemTypes:  map[string][]string{"1": {"On", "GracefulShutdown"}},
+			ManagerTypes: map[string][]string{"1": {"GracefulRestart"}},
+		},
+	}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { setResetTypes(config.ResetConfig{}) })
+
+	for _, tc := range []struct {
+		uri      string
+		allowed  []string
+		rejected string
+	}{
+		{"/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", []string{"On", "GracefulShutdown"}, "ForceOff"},
+		{"/redfish/v1/Managers/1/Actions/Manager.Reset", []string{"GracefulRestart"}, "ForceRestart"},
+	} {
+		req := httptest.NewRequest("GET", tc.uri, nil)
+		req.SetBasicAuth("admin", "password")
+		w := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		var actionInfo struct {
+			Parameters []struct {
+				AllowableValues []string
+			}
+		}
+		json.Unmarshal(w.Body.Bytes(), &actionInfo)
+		if len(actionInfo.Parameters) != 1 || !slices.Equal(actionInfo.Parameters[0].AllowableValues, tc.allowed) {
+			t.Errorf("Expected %s ActionInfo to allow %v, got %s", tc.uri, tc.allowed, w.Body.String())
+		}
+
+		// A type the resource does not support is rejected even though the
+		// default set includes it
+		req = httptest.NewRequest("POST", tc.uri, strings.NewReader(`{"ResetType": "`+tc.rejected+`"}`))
+		req.SetBasicAuth("admin", "password")
+		w = httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400 resetting %s with %s, got %d", tc.uri, tc.rejected, w.Code)
+		}
+	}
+
+	// Resources without a configured set keep the defaults
+	if got := systemResetTypes("2"); !slices.Equal(got, defaultSystemResetTypes) {
+		t.Errorf("Expected unconfigured system to support the defaults, got %v", got)
+	}
+}
+
+func TestActionParametersValidatedAgainstActionInfo(t *testing.T) {
+	setResetTypes(config.ResetConfig{SystemTypes: map[string][]string{"1": {"GracefulShutdown", "On"}}})
+	t.Cleanup(func() { setResetTypes(config.ResetConfig{}) })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	send := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// The values the POST accepts are those the ActionInfo lists, in its order
+	var info models.ActionInfo
+	json.Unmarshal(send("GET", "").Body.Bytes(), &info)
+	parameter, ok := info.Parameter("ResetType")
+	if !ok {
+		t.Fatalf("Expected a ResetType parameter, got %+v", info.Parameters)
+	}
+	w := send("POST", `{"ResetType": "ForceOff"}`)
+	var errorResponse models.RedfishError
+	json.NewDecoder(w.Body).Decode(&errorResponse)
+	if w.Code != http.StatusBadRequest || errorResponse.Error.Code != baseRegistry.MessageID("ActionParameterValueNotInList") {
+		t.Fatalf("Expected ActionParameterValueNotInList, got %d %s", w.Code, errorResponse.Error.Code)
+	}
+	if want := "allowed values: " + strings.Join(parameter.AllowableValues, ", "); !strings.Contains(errorResponse.Error.Message, want) {
+		t.Errorf("Expected the rejection to list %q, got %q", want, errorResponse.Error.Message)
+	}
+
+	// A required parameter must be present
+	required := models.NewActionInfo("/redfish/v1/Actions/Test", "Test", "Test",
+		models.ActionParameter{Name: "Target", Required: true, DataType: "String"},
+		models.ActionParameter{Name: "Mode", DataType: "String", AllowableValues: []string{"Fast", "Safe"}},
+	)
+	for _, tc := range []struct {
+		body    string
+		wantKey string
+	}{
+		{`{"Mode": "Fast"}`, "ActionParameterMissing"},
+		{``, "ActionParameterMissing"},
+		{`{"Target": "a", "Mode": "Slow"}`, "ActionParameterValueNotInList"},
+		{`{"Target": "a"}`, ""},
+	} {
+		var body struct{ Target, Mode string }
+		w := httptest.NewRecorder()
+		accepted := decodeActionRequest(w, httptest.NewRequest("POST", "/redfish/v1/Actions/Test", strings.NewReader(tc.body)), required, &body)
+		if tc.wantKey == "" {
+			if !accepted || body.Target != "a" {
+				t.Errorf("Expected %s to be accepted, got %d: %s", tc.body, w.Code, w.Body.String())
+			}
+			continue
+		}
+		var errorResponse models.RedfishError
+		json.NewDecoder(w.Body).Decode(&errorResponse)
+		if accepted || errorResponse.Error.Code != baseRegistry.MessageID(tc.wantKey) {
+			t.Errorf("Expected %s to be rejected with %s, got %s", tc.body, tc.wantKey, errorResponse.Error.Code)
+		}
+	}
+}
+
+func TestInvalidResetTypesRejected(t *testing.T) {
+	for _, reset := range []config.ResetConfig{
+		{SystemTypes: map[string][]string{"1": {"Explode"}}},
+		{ManagerTypes: map[string][]string{"1": {}}},
+	} {
+		cfg := &config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}, Reset: reset}
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected reset configuration %+v to be rejected", reset)
+		}
+	}
+}
+
+func TestSystemWithoutResetOmitsAction(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		Reset:   config.ResetConfig{SystemTypes: map[string][]string{"1": {}}},
+	}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { setResetTypes(config.ResetConfig{}) })
+
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems/1", nil)
+	req.SetBasicAuth("admin", "password")
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	var system struct {
+		Actions map[string]interface{}
+	}
+	json.Unmarshal(w.Body.Bytes(), &system)
+	if _, ok := system.Actions["#ComputerSystem.Reset"]; ok {
+		t.Errorf("Expected the reset action to be omitted, got %v", system.Actions)
+	}
+
+	req = httptest.NewRequest("POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "On"}`))
+	req.SetBasicAuth("admin", "password")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", w.Code)
+	}
+
+	// A PATCH answers with the system as GET serves it, so its ETag can be
+	// used for the next If-Match
+	defer systemStore.Put("1", models.NewComputerSystem("1"))
+	req = httptest.NewRequest("PATCH", "/redfish/v1/Systems/1", strings.NewReader(`{"AssetTag": "no-reset"}`))
+	req.SetBasicAuth("admin", "password")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	system.Actions = nil
+	json.Unmarshal(w.Body.Bytes(), &system)
+	if _, ok := system.Actions["#ComputerSystem.Reset"]; ok {
+		t.Errorf("Expected the PATCH response to omit the reset action, got %v", system.Actions)
+	}
+	patched := w.Header().Get("ETag")
+
+	req = httptest.NewRequest("GET", "/redfish/v1/Systems/1", nil)
+	req.SetBasicAuth("admin", "password")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if etag := w.Header().Get("ETag"); patched == "" || etag != patched {
+		t.Errorf("Expected the PATCH ETag %q to match GET, got %q", patched, etag)
+	}
+}
+
+func TestResetRejectedInCurrentPowerState(t *testing.T) {
+	previous := systemResetDuration
+	systemResetDuration = 10 * time.Millisecond
+	defer func() { systemResetDuration = previous }()
+	systemStore.Put("power-test", models.NewComputerSystem("power-test"))
+	t.Cleanup(func() { systemStore.Delete("power-test") })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	reset := func(resetType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/redfish/v1/Systems/power-test/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "`+resetType+`"}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	expectConflict := func(resetType string) {
+		t.Helper()
+		w := reset(resetType)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("Expected status 409 for %s, got %d", resetType, w.Code)
+		}
+		var errorResponse models.RedfishError
+		json.Unmarshal(w.Body.Bytes(), &errorResponse)
+		if errorResponse.Error.Code != baseRegistry.MessageID("ActionParameterNotSupported") {
+			t.Errorf("Expected ActionParameterNotSupported for %s, got %s", resetType, errorResponse.Error.Code)
+		}
+	}
+	waitForPowerState := func(state string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			system, _ := systemStore.Get("power-test")
+			if system.PowerState == state {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected PowerState %s, still %s", state, system.PowerState)
+			}
+			time.Sleep(systemResetDuration)
+		}
+	}
+
+	// The system starts out On
+	expectConflict("On")
+
+	if w := reset("ForceOff"); w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202 for ForceOff, got %d", w.Code)
+	}
+	waitForPowerState("Off")
+	expectConflict("ForceOff")
+
+	if w := reset("On"); w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202 for On, got %d", w.Code)
+	}
+	waitForPowerState("On")
+}
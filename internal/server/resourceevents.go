@@ -0,0 +1,46 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// lastEventID numbers the events the service raises itself
+var lastEventID atomic.Uint64
+
+// resourceChangedEvent builds the ResourceChanged event announcing that
+// properties of the resource at origin were modified
+func resourceChangedEvent(origin models.ODataID, properties []string) *models.Event {
+	return models.NewEvent("", []models.EventRecord{{
+		EventType:         "ResourceUpdated",
+		EventId:           strconv.FormatUint(lastEventID.Add(1), 10),
+		EventTimestamp:    time.Now().Format(time.RFC3339),
+		Severity:          "OK",
+		MessageSeverity:   "OK",
+		Message:           "One or more resource properties have changed: " + strings.Join(properties, ", "),
+		MessageId:         "ResourceEvent.1.0.ResourceChanged",
+		OriginOfCondition: &origin,
+		MemberId:          "0",
+	}})
+}
+
+// serviceShuttingDownEvent builds the final event announcing that the
+// service is shutting down cleanly
+func serviceShuttingDownEvent() *models.Event {
+	origin := models.ODataID("/redfish/v1")
+	return models.NewEvent("", []models.EventRecord{{
+		EventType:         "Alert",
+		EventId:           strconv.FormatUint(lastEventID.Add(1), 10),
+		EventTimestamp:    time.Now().Format(time.RFC3339),
+		Severity:          "Warning",
+		MessageSeverity:   "Warning",
+		Message:           "The service is shutting down and can no longer take incoming requests",
+		MessageId:         baseRegistry.MessageID("ServiceShuttingDown"),
+		OriginOfCondition: &origin,
+		MemberId:          "0",
+	}})
+}
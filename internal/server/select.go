@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// selectProperties renders resource with only the properties named by
+// $select. OData annotations such as @odata.id are always kept.
+func selectProperties(resource interface{}, selected []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	for name := range properties {
+		if !strings.HasPrefix(name, "@") && !slices.Contains(selected, name) {
+			delete(properties, name)
+		}
+	}
+	return properties, nil
+}
+
+// selectedProperties returns the properties listed in a $select, without
+// annotations or repeats, in the order given
+func selectedProperties(properties []string) []string {
+	var listed []string
+	for _, property := range properties {
+		if strings.HasPrefix(property, "@") || slices.Contains(listed, property) {
+			continue
+		}
+		listed = append(listed, property)
+	}
+	return listed
+}
+
+// expandsMembers reports whether an $expand asks for a collection's members
+// inline: by name, or through "*" or ".", which include them
+func expandsMembers(expand []string) bool {
+	return expandsProperty(expand, "Members")
+}
+
+// expandsProperty reports whether an $expand asks for a navigation property
+// inline: by name, or through "*" or ".", which include every one
+func expandsProperty(expand []string, property string) bool {
+	return slices.Contains(expand, property) || slices.Contains(expand, "*") || slices.Contains(expand, ".")
+}
+
+// expandedSystemCollection is a systems collection with its members inline
+type expandedSystemCollection struct {
+	*models.ComputerSystemCollection
+	Members []interface{} `json:"Members"`
+}
+
+// expandSystemMembers inlines the members of a systems collection page,
+// projecting each with $select when one is given. The collection's
+// @odata.context then lists the projection as Members(...).
+func expandSystemMembers(collection *models.ComputerSystemCollection, selectProps []string) (*expandedSystemCollection, error) {
+	result := &expandedSystemCollection{Members: make([]interface{}, 0, len(collection.Members))}
+	projected := *collection
+	result.ComputerSystemCollection = &projected
+
+	for _, link := range collection.Members {
+		system, ok := getSystem(path.Base(string(link.ODataID)))
+		if !ok {
+			continue
+		}
+		if len(selectProps) == 0 {
+			result.Members = append(result.Members, system)
+			continue
+		}
+		system = applySelectToSystem(system, selectProps)
+		member, err := selectProperties(system, selectProps)
+		if err != nil {
+			return nil, err
+		}
+		result.Members = append(result.Members, member)
+	}
+
+	if len(selectProps) > 0 {
+		if listed := selectedProperties(validSystemProperties(selectProps)); len(listed) > 0 {
+			projected.ODataContext = projectedContext(collection.ODataContext, []string{"Members(" + strings.Join(listed, ",") + ")"})
+		}
+	}
+	return result, nil
+}
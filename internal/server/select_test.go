@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestSelectOnExpandedSystemMembers(t *testing.T) {
+	systemStore.Put("2", models.NewComputerSystem("2"))
+	defer systemStore.Delete("2")
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/redfish/v1/Systems?$expand=Members&$select=Id,PowerState", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var collection struct {
+		ODataContext string                       `json:"@odata.context"`
+		Count        int                          `json:"Members@odata.count"`
+		Members      []map[string]json.RawMessage `json:"Members"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &collection); err != nil {
+		t.Fatalf("Failed to decode collection: %v", err)
+	}
+	wantContext := "/redfish/v1/$metadata#ComputerSystemCollection.ComputerSystemCollection(Members(Id,PowerState))"
+	if collection.ODataContext != wantContext {
+		t.Errorf("Expected @odata.context %s, got %s", wantContext, collection.ODataContext)
+	}
+	if collection.Count != 2 || len(collection.Members) != 2 {
+		t.Fatalf("Expected 2 expanded members, got %d of %d", len(collection.Members), collection.Count)
+	}
+
+	for _, member := range collection.Members {
+		for _, property := range []string{"@odata.id", "@odata.context", "Id", "PowerState"} {
+			if _, ok := member[property]; !ok {
+				t.Errorf("Expected %s in every projected member, got %v", property, member)
+			}
+		}
+		for _, property := range []string{"Name", "Boot", "Status"} {
+			if _, ok := member[property]; ok {
+				t.Errorf("Expected %s to be projected out, got %v", property, member)
+			}
+		}
+		var context string
+		json.Unmarshal(member["@odata.context"], &context)
+		if context != "/redfish/v1/$metadata#ComputerSystem.ComputerSystem(Id,PowerState)" {
+			t.Errorf("Expected each member's @odata.context to list the projection, got %s", context)
+		}
+	}
+
+	// Without $select the members are inlined in full
+	req = httptest.NewRequest("GET", "/redfish/v1/Systems?$expand=Members", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var full struct {
+		ODataContext string                  `json:"@odata.context"`
+		Members      []models.ComputerSystem `json:"Members"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &full); err != nil {
+		t.Fatalf("Failed to decode collection: %v", err)
+	}
+	if full.ODataContext != "/redfish/v1/$metadata#ComputerSystemCollection.ComputerSystemCollection" {
+		t.Errorf("Expected an unprojected @odata.context, got %s", full.ODataContext)
+	}
+	if len(full.Members) != 2 || full.Members[0].Name == "" {
+		t.Errorf("Expected full members, got %+v", full.Members)
+	}
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// Global serial interface storage for demo purposes, keyed by manager ID and
+// then interface ID. Each manager starts with a single console port.
+var (
+	serialMutex      sync.Mutex
+	serialInterfaces = make(map[string]map[string]*models.SerialInterface)
+)
+
+// managerSerialInterfaces returns the serial interfaces of a manager, creating
+// the default console port on first use. The caller must hold serialMutex.
+func managerSerialInterfaces(managerID string) map[string]*models.SerialInterface {
+	ports, ok := serialInterfaces[managerID]
+	if !ok {
+		ports = map[string]*models.SerialInterface{
+			"TTY0": models.NewSerialInterface(managerID, "TTY0"),
+		}
+		serialInterfaces[managerID] = ports
+	}
+	return ports
+}
+
+// serialInterfacesHandler handles a manager's serial interface collection and items
+func serialInterfacesHandler(w http.ResponseWriter, r *http.Request, managerID string, subPath []string) {
+	switch len(subPath) {
+	case 0:
+		w.Header().Set("Allow", "GET")
+		switch r.Method {
+		case "GET":
+			handleGetSerialInterfaces(w, r, managerID)
+		default:
+			methodNotAllowed(w, r)
+		}
+	case 1:
+		w.Header().Set("Allow", "GET, PATCH")
+		switch r.Method {
+		case "GET":
+			handleGetSerialInterface(w, r, managerID, subPath[0])
+		case "PATCH":
+			handleUpdateSerialInterface(w, r, managerID, subPath[0])
+		default:
+			methodNotAllowed(w, r)
+		}
+	default:
+		sendResourceNotFound(w, r)
+	}
+}
+
+// handleGetSerialInterfaces returns the serial interface collection of a manager
+func handleGetSerialInterfaces(w http.ResponseWriter, r *http.Request, managerID string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	serialMutex.Lock()
+	ports := managerSerialInterfaces(managerID)
+	ids := make([]string, 0, len(ports))
+	for id := range ports {
+		ids = append(ids, id)
+	}
+	serialMutex.Unlock()
+
+	sort.Strings(ids)
+	members := make([]models.Link, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, models.Link{ODataID: models.ODataID(fmt.Sprintf("/redfish/v1/Managers/%s/SerialInterfaces/%s", managerID, id))})
+	}
+	collection := models.NewSerialInterfaceCollection(managerID, members)
+
+	etag := generateETag(collection)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, collection)
+}
+
+// serialInterface returns a copy of a serial interface
+func serialInterface(managerID, id string) (*models.SerialInterface, bool) {
+	serialMutex.Lock()
+	defer serialMutex.Unlock()
+
+	port, ok := managerSerialInterfaces(managerID)[id]
+	if !ok {
+		return nil, false
+	}
+	result := *port
+	return &result, true
+}
+
+// handleGetSerialInterface returns a single serial interface
+func handleGetSerialInterface(w http.ResponseWriter, r *http.Request, managerID, id string) {
+	port, ok := serialInterface(managerID, id)
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	etag := resourceETag(port)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, port)
+}
+
+// handleUpdateSerialInterface updates the line settings of a serial interface (PATCH)
+func handleUpdateSerialInterface(w http.ResponseWriter, r *http.Request, managerID, id string) {
+	if !requirePrivilege(w, r, "ConfigureManager") {
+		return
+	}
+	if _, ok := serialInterface(managerID, id); !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	var requestBody map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	enumProperties := map[string]struct {
+		field   func(*models.SerialInterface) *string
+		allowed []string
+	}{
+		"BitRate":     {func(p *models.SerialInterface) *string { return &p.BitRate }, models.SerialBitRates},
+		"Parity":      {func(p *models.SerialInterface) *string { return &p.Parity }, models.SerialParities},
+		"DataBits":    {func(p *models.SerialInterface) *string { return &p.DataBits }, models.SerialDataBits},
+		"StopBits":    {func(p *models.SerialInterface) *string { return &p.StopBits }, models.SerialStopBits},
+		"FlowControl": {func(p *models.SerialInterface) *string { return &p.FlowControl }, models.SerialFlowControls},
+	}
+
+	// Every property is validated before any is applied
+	var changes []func(*models.SerialInterface)
+	for name, raw := range requestBody {
+		if name == "InterfaceEnabled" {
+			var enabled bool
+			if err := json.Unmarshal(raw, &enabled); err != nil {
+				sendRedfishError(w, "PropertyValueTypeError", fmt.Sprintf("InterfaceEnabled must be a boolean, got %s", raw), http.StatusBadRequest)
+				return
+			}
+			changes = append(changes, func(port *models.SerialInterface) { port.InterfaceEnabled = enabled })
+			continue
+		}
+
+		property, writable := enumProperties[name]
+		if !writable {
+			sendRedfishError(w, "PropertyNotWritable", fmt.Sprintf("The property %s is read only", name), http.StatusBadRequest)
+			return
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			sendRedfishError(w, "PropertyValueTypeError", fmt.Sprintf("%s must be a string, got %s", name, raw), http.StatusBadRequest)
+			return
+		}
+		if err := models.ValidateEnum(name, value, property.allowed); err != nil {
+			sendRedfishError(w, "PropertyValueNotInList", err.Error(), http.StatusBadRequest)
+			return
+		}
+		changes = append(changes, func(port *models.SerialInterface) { *property.field(port) = value })
+	}
+
+	// The changes are applied to the stored interface under the lock, so
+	// concurrent PATCHes of different properties do not overwrite each other
+	serialMutex.Lock()
+	stored, ok := managerSerialInterfaces(managerID)[id]
+	var port models.SerialInterface
+	if ok {
+		port = *stored
+		for _, change := range changes {
+			change(&port)
+		}
+		updated := port
+		managerSerialInterfaces(managerID)[id] = &updated
+	}
+	serialMutex.Unlock()
+
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+	sendUpdatedResource(w, &port)
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestSerialInterfacesCollection(t *testing.T) {
+	managerStore.Put("serial-list", models.NewManager("serial-list"))
+	defer managerStore.Delete("serial-list")
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/redfish/v1/Managers/serial-list/SerialInterfaces", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var collection models.SerialInterfaceCollection
+	if err := json.NewDecoder(w.Body).Decode(&collection); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if collection.MembersODataCount != 1 || !containsLink(collection.Members, "/redfish/v1/Managers/serial-list/SerialInterfaces/TTY0") {
+		t.Errorf("Unexpected members: %+v", collection.Members)
+	}
+
+	// The manager links to the collection
+	req = httptest.NewRequest("GET", "/redfish/v1/Managers/serial-list", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	var manager models.Manager
+	if err := json.NewDecoder(w.Body).Decode(&manager); err != nil {
+		t.Fatalf("Failed to decode manager: %v", err)
+	}
+	if manager.SerialInterfaces.ODataID != "/redfish/v1/Managers/serial-list/SerialInterfaces" {
+		t.Errorf("Unexpected SerialInterfaces link: %q", manager.SerialInterfaces.ODataID)
+	}
+}
+
+func TestSerialInterfacePatch(t *testing.T) {
+	managerStore.Put("serial-patch", models.NewManager("serial-patch"))
+	defer managerStore.Delete("serial-patch")
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	path := "/redfish/v1/Managers/serial-patch/SerialInterfaces/TTY0"
+
+	req := httptest.NewRequest("PATCH", path, strings.NewReader(`{"BitRate": "9600", "FlowControl": "Hardware"}`))
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", path, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	var port models.SerialInterface
+	if err := json.NewDecoder(w.Body).Decode(&port); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if port.BitRate != "9600" || port.FlowControl != "Hardware" {
+		t.Errorf("Expected BitRate 9600 and FlowControl Hardware, got %s and %s", port.BitRate, port.FlowControl)
+	}
+
+	for _, body := range []string{
+		`{"BitRate": "12345"}`,
+		`{"BitRate": 9600}`,
+		`{"SignalType": "Rs485"}`,
+	} {
+		req := httptest.NewRequest("PATCH", path, strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/redfish/v1/Managers/serial-patch/SerialInterfaces/TTY9", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown interface, got %d", w.Code)
+	}
+}
+
+func TestSerialInterfacePatchAccess(t *testing.T) {
+	managerStore.Put("serial-access", models.NewManager("serial-access"))
+	t.Cleanup(func() {
+		managerStore.Delete("serial-access")
+		serialMutex.Lock()
+		delete(serialInterfaces, "serial-access")
+		serialMutex.Unlock()
+	})
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	path := "/redfish/v1/Managers/serial-access/SerialInterfaces/TTY0"
+	patch := func(role, body string) int {
+		req := httptest.NewRequest("PATCH", path, strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContextWithRole(req.Context(), "user", "Basic", role))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Reconfiguring the console requires the ConfigureManager privilege
+	if code := patch("Operator", `{"BitRate": "9600"}`); code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for an Operator, got %d", code)
+	}
+
+	// Concurrent PATCHes of different properties both take effect
+	for i := range 20 {
+		bitRate, parity := []string{"9600", "19200"}[i%2], []string{"Even", "Odd"}[i%2]
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); patch("Administrator", `{"BitRate": "`+bitRate+`"}`) }()
+		go func() { defer wg.Done(); patch("Administrator", `{"Parity": "`+parity+`"}`) }()
+		wg.Wait()
+
+		port, _ := serialInterface("serial-access", "TTY0")
+		if port.BitRate != bitRate || port.Parity != parity {
+			t.Fatalf("Round %d: expected BitRate %s and Parity %s, got %s and %s", i, bitRate, parity, port.BitRate, port.Parity)
+		}
+	}
+}
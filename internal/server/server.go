@@ -1,22 +1,30 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/user/redfish-server/internal/auth"
 	"github.com/user/redfish-server/internal/config"
 	"github.com/user/redfish-server/internal/middleware"
 	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
 )
 
 // Global task storage for demo purposes
@@ -25,12 +33,36 @@ var (
 	tasks      = make(map[string]*models.Task)
 )
 
+// systemStore backs the ComputerSystem collection, starting with the single
+// demo system
+var systemStore = store.NewResources[models.ComputerSystem]()
+
+// chassisStore and managerStore back the Chassis and Manager collections,
+// starting with the single demo chassis and manager. Only stored ids are served.
+var (
+	chassisStore = store.NewResources[models.Chassis]()
+	managerStore = store.NewResources[models.Manager]()
+)
+
+func init() {
+	systemStore.Put("1", models.NewComputerSystem("1"))
+	chassisStore.Put("1", models.NewChassis("1"))
+	managerStore.Put("1", models.NewManager("1"))
+}
+
+// Simulated durations of asynchronous reset operations
+var (
+	systemResetDuration  = 3 * time.Second
+	managerResetDuration = 5 * time.Second
+	demoTaskStepDuration = 2 * time.Second
+)
+
 // Server represents the Redfish HTTP server
 type Server struct {
-	httpServer    *http.Server
-	config        *config.Config
-	subscriptions map[string]*models.EventSubscription // In-memory storage for demo
-	tasks         map[string]*models.Task              // In-memory storage for demo
+	httpServer *http.Server
+	config     *config.Config
+	tracker    *requestTracker
+	tasks      map[string]*models.Task // In-memory storage for demo
 }
 
 // New creates a new Redfish server instance
@@ -39,25 +71,113 @@ func New(cfg *config.Config) (*Server, error) {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	identity, err := newServiceIdentity(cfg.Service)
+	if err != nil {
+		return nil, err
+	}
+	currentIdentity.Store(identity)
+	setMaxExpandLevels(cfg.Server.MaxExpandLevels)
+	setResetTypes(cfg.Reset)
+	setServiceFeatures(cfg)
+	setSSESettings(cfg.Events)
+	setEventDeliveryPool(cfg.Events)
+	setTaskRetention(cfg.Tasks)
+	setPowerThermalModel(cfg.Chassis.PowerThermalModel)
+	if err := setDestinationPolicy(cfg.Events); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := setDefaultRole(cfg.Auth.DefaultRoleId); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := setSessionTokenBytes(cfg.Auth.SessionTokenBytes); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	publicRoutes := cfg.Auth.PublicRoutes
+	if len(publicRoutes) == 0 {
+		publicRoutes = config.DefaultPublicRoutes
+	}
+	public, err := middleware.ParsePublicRoutes(publicRoutes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if cfg.State.Dir != "" {
+		stateStore, err := store.NewFileStore(cfg.State.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if err := auth.GetAuthService().UseStore(stateStore); err != nil {
+			return nil, err
+		}
+		if err := useSubscriptionStore(stateStore); err != nil {
+			return nil, err
+		}
+	}
+
+	corsOrigins := cfg.Server.CORSAllowedOrigins
+	if cfg.DevMode {
+		log.Println("WARNING: DEV_MODE is enabled. TLS may be off, CORS admits any origin and the built-in accounts keep the default password. Never use DEV_MODE in production!")
+		corsOrigins = []string{"*"}
+	} else if err := auth.GetAuthService().SecureDefaultAccounts(cfg.Auth.AdminPassword); err != nil {
+		return nil, fmt.Errorf("failed to secure the built-in accounts: %w", err)
+	}
+
+	if cfg.Auth.BearerEnabled() {
+		validator, err := newJWTValidator(cfg.Auth)
+		if err != nil {
+			return nil, err
+		}
+		auth.SetAuthenticator(auth.WithBearer(auth.GetAuthService(), validator))
+	}
+
 	mux := http.NewServeMux()
 	setupRoutes(mux)
 
 	// Apply middleware
-	handler := middleware.CORSMiddleware(mux)
-	handler = middleware.AuthMiddleware(handler)
-	handler = middleware.LoggingMiddleware(handler)
+	tracker := newRequestTracker()
+	handler := contentNegotiationMiddleware(mux)
+	handler = middleware.CORSMiddleware(corsOrigins, map[string]middleware.CORSRoute{
+		"/redfish/v1/EventService/SSE": sseCORS,
+	}, handler)
+	handler = middleware.AuthMiddleware(public, handler)
+	if cfg.Server.AllowMethodOverride {
+		handler = methodOverrideMiddleware(handler)
+	}
+	handler = tracker.middleware(handler)
+	if cfg.Server.BasePath != "" {
+		handler = basePathMiddleware(cfg.Server.BasePath, handler)
+	}
+	handler = recoveryMiddleware(handler)
+	// Outside everything that can answer on its own, so the 401s, 400s and
+	// 500s of the other middleware carry the security headers too
+	handler = middleware.SecurityHeadersMiddleware(middleware.SecurityHeaders{
+		ContentSecurityPolicy: cfg.Server.ContentSecurityPolicy,
+		FrameOptions:          cfg.Server.FrameOptions,
+		HSTSMaxAge:            cfg.Server.HSTSMaxAge,
+	}, handler)
+	handler = middleware.LoggingMiddleware(time.Duration(cfg.Server.SlowRequestThreshold)*time.Millisecond, handler)
 
 	httpServer := &http.Server{
-		Addr:         cfg.Server.Address,
-		Handler:      handler,
-		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		Addr:              cfg.Server.Address,
+		Handler:           handler,
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeout) * time.Second,
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeout) * time.Second,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
 	}
+	httpServer.SetKeepAlivesEnabled(!cfg.Server.DisableKeepAlives)
 
 	if cfg.TLS.Enabled {
-		cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		cert, err := loadCertificate(cfg.TLS)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load TLS certificates: %w", err)
+			return nil, err
+		}
+		if err := checkCertificateHostname(cert, certificateHostname(cfg)); err != nil {
+			if cfg.TLS.StrictHostname {
+				return nil, err
+			}
+			log.Printf("WARNING: %v", err)
 		}
 
 		httpServer.TLSConfig = &tls.Config{
@@ -67,10 +187,10 @@ func New(cfg *config.Config) (*Server, error) {
 	}
 
 	return &Server{
-		httpServer:    httpServer,
-		config:        cfg,
-		subscriptions: make(map[string]*models.EventSubscription),
-		tasks:         make(map[string]*models.Task),
+		httpServer: httpServer,
+		config:     cfg,
+		tracker:    tracker,
+		tasks:      make(map[string]*models.Task),
 	}, nil
 }
 
@@ -78,24 +198,110 @@ func New(cfg *config.Config) (*Server, error) {
 func (s *Server) Start() error {
 	fmt.Printf("Starting Redfish server on %s (TLS: %t)\n", s.config.Server.Address, s.config.TLS.Enabled)
 
+	ln, err := net.Listen("tcp", s.config.Server.Address)
+	if err != nil {
+		return err
+	}
+	if limit := s.config.Server.MaxConnections; limit > 0 {
+		ln = newLimitListener(ln, limit, !s.config.TLS.Enabled)
+	}
+
 	if s.config.TLS.Enabled {
 		fmt.Printf("TLS certificates: %s, %s\n", s.config.TLS.CertFile, s.config.TLS.KeyFile)
-		return s.httpServer.ListenAndServeTLS("", "")
+		return s.httpServer.ServeTLS(ln, "", "")
 	}
 
 	fmt.Println("WARNING: TLS is disabled. Redfish requires TLS in production!")
-	return s.httpServer.ListenAndServe()
+	return s.httpServer.Serve(ln)
 }
 
-// SendEvent sends an event to all matching subscribers
+// SendEvent sends an event to all subscribers. Each delivery runs in the
+// background and failures are logged. Subscribers that batch their events
+// receive the records in a later, combined payload.
 func (s *Server) SendEvent(event *models.Event) {
-	// For now, just log the event
-	fmt.Printf("Event sent: %+v\n", event)
-	// In a real implementation, this would filter subscribers and send HTTP POSTs
+	background := context.Background()
+	if s.tracker != nil {
+		background = s.tracker.background
+	}
+	publishEvent(background, event)
+}
+
+// publishEvent sends an event to all subscribers as SendEvent does, with
+// deliveries bounded by background. Deliveries run on the event delivery
+// pool rather than a goroutine each.
+func publishEvent(background context.Context, event *models.Event) {
+	// A disabled EventService sends nothing
+	if !serviceEnabled("/redfish/v1/EventService") {
+		return
+	}
+
+	subscriptionsMutex.RLock()
+	targets := sortedSubscriptionsLocked()
+	subscriptionsMutex.RUnlock()
+
+	for _, subscription := range targets {
+		if subscription.IsSSE() {
+			continue
+		}
+		if subscription.Batching() != nil {
+			batchEvent(subscription, event)
+			continue
+		}
+		enqueueDelivery(func() {
+			ctx, cancel := context.WithTimeout(background, eventDeliveryTimeout)
+			defer cancel()
+			if err := deliverEvent(ctx, subscription, event); err != nil {
+				log.Printf("Event delivery to subscription %s failed: %v", subscription.ID, err)
+			}
+		})
+	}
+}
+
+// announceShutdown sends the ServiceShuttingDown event to every subscriber
+// at once, bypassing batching, and waits for the deliveries. It is best
+// effort: a delivery that fails or outlasts shutdownEventTimeout is logged
+// and abandoned.
+func announceShutdown() {
+	if !serviceEnabled("/redfish/v1/EventService") {
+		return
+	}
+	subscriptionsMutex.RLock()
+	targets := sortedSubscriptionsLocked()
+	subscriptionsMutex.RUnlock()
+
+	event := serviceShuttingDownEvent()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownEventTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, subscription := range targets {
+		if subscription.IsSSE() {
+			continue
+		}
+		wg.Add(1)
+		go func(subscription *models.EventSubscription) {
+			defer wg.Done()
+			if err := deliverEvent(ctx, subscription, event); err != nil {
+				log.Printf("Shutdown event delivery to subscription %s failed: %v", subscription.ID, err)
+			}
+		}(subscription)
+	}
+	wg.Wait()
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server. Subscribers are first told the
+// service is going away, so a clean shutdown can be told from a crash. Then
+// long-lived handlers such as SSE streams and background work such as task
+// workers are cancelled so they don't hold the drain open, and the listener
+// closes while ordinary requests are allowed to finish.
 func (s *Server) Shutdown() error {
+	announceShutdown()
+
+	s.tracker.stopBackground()
+	streams := sseConnections.Load()
+	forced := s.tracker.cancelLongLived()
+	log.Printf("Shutdown: closed %d long-lived connection(s) (%d SSE stream(s)), %d request(s) in flight", forced, streams, s.tracker.Active())
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -110,11 +316,12 @@ func setupRoutes(mux *http.ServeMux) {
 	// Redfish endpoints - order matters! More specific routes first
 	mux.HandleFunc("/redfish/v1/$metadata", metadataHandler)
 	mux.HandleFunc("/redfish/v1/odata", odataHandler)
+	mux.HandleFunc("/redfish/v1/$entity", entityHandler(mux))
 
 	// Session service endpoints
 	mux.HandleFunc("/redfish/v1/SessionService/Sessions/", sessionItemHandler)
 	mux.HandleFunc("/redfish/v1/SessionService/Sessions", sessionsHandler)
-	mux.HandleFunc("/redfish/v1/SessionService/Sessions/Members", sessionsHandler)
+	mux.HandleFunc("/redfish/v1/SessionService/Sessions/Members", sessionMembersHandler)
 	mux.HandleFunc("/redfish/v1/SessionService", sessionServiceHandler)
 
 	// Account service endpoints
@@ -146,19 +353,23 @@ func setupRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/redfish/v1/TaskService/Tasks/", taskHandler)
 	mux.HandleFunc("/redfish/v1/TaskService/Tasks", tasksHandler)
 	mux.HandleFunc("/redfish/v1/TaskService", taskServiceHandler)
+	mux.HandleFunc("/redfish/v1/ServiceConditions", serviceConditionsHandler)
 
 	// Registry endpoints
 	mux.HandleFunc("/redfish/v1/Registries/", registryHandler)
 	mux.HandleFunc("/redfish/v1/Registries", registriesHandler)
 
-	// OEM endpoints
-	mux.HandleFunc("/redfish/v1/Oem/Contoso/CustomAction", oemCustomActionHandler)
+	// OEM endpoints, dispatched to registered vendor actions, and the
+	// capabilities document describing what this mux serves
+	mux.HandleFunc("/redfish/v1/Oem/", oemActionHandler)
+	mux.HandleFunc("/redfish/v1/Oem/Contoso/Capabilities", capabilitiesHandler(mux))
 
 	// OpenAPI endpoint
 	mux.HandleFunc("/redfish/v1/openapi.yaml", openapiHandler)
 
-	// Redfish root endpoint
+	// Redfish root endpoint - handle both /redfish and /redfish/
 	mux.HandleFunc("/redfish", redfishRootHandler)
+	mux.HandleFunc("/redfish/{$}", redfishRootHandler)
 
 	// Redfish v1 root endpoint - handle both /redfish/v1 and /redfish/v1/
 	mux.HandleFunc("/redfish/v1", serviceRootHandler)
@@ -168,11 +379,13 @@ func setupRoutes(mux *http.ServeMux) {
 // healthHandler handles health check requests
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, HEAD")
 
 	switch r.Method {
 	case "GET":
 		handleGetHealth(w, r)
+	case "HEAD":
+		handleGetHealth(w, r)
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -181,23 +394,24 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 // handleGetHealth returns health check information
 func handleGetHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
 
 	response := `{"status": "ok", "service": "redfish-server"}`
 	etag := generateETag(response)
 	w.Header().Set("ETag", etag)
 
-	w.Write([]byte(response))
+	writeFixedBody(w, r, []byte(response))
 }
 
 // openapiHandler serves the OpenAPI specification
 func openapiHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, HEAD")
 
 	switch r.Method {
 	case "GET":
 		handleGetOpenAPI(w, r)
+	case "HEAD":
+		handleGetOpenAPI(w, r)
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -235,11 +449,10 @@ paths:
 		}
 	}
 
-	w.Write([]byte(openapi))
+	writeFixedBody(w, r, []byte(openapi))
 }
 
-// serviceRootHandler handles the Redfish service root
-// redfishRootHandler handles requests to /redfish
+// redfishRootHandler handles requests to /redfish and /redfish/
 func redfishRootHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
 	w.Header().Set("Allow", "GET, HEAD")
@@ -254,15 +467,31 @@ func redfishRootHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// redfishRoot is the protocol version map served at /redfish
+const redfishRoot = `{"v1": "/redfish/v1/"}`
+
 // handleGetRedfishRoot returns the redfish root
 func handleGetRedfishRoot(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"v1": "/redfish/v1/"}`))
+
+	etag := generateETag(redfishRoot)
+	w.Header().Set("ETag", etag)
+
+	if sendNotModified(w, r, etag) {
+		return
+	}
+
+	writeFixedBody(w, r, []byte(redfishRoot))
 }
 
+// serviceRootHandler handles the Redfish service root
 func serviceRootHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
+	if r.URL.Path != "/redfish/v1" && r.URL.Path != "/redfish/v1/" {
+		// The service root route catches every unrouted URI below it
+		sendResourceNotFound(w, r)
+		return
+	}
 	w.Header().Set("Allow", "GET, HEAD")
 
 	switch r.Method {
@@ -279,7 +508,12 @@ func serviceRootHandler(w http.ResponseWriter, r *http.Request) {
 func handleGetServiceRoot(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	serviceRoot := models.NewServiceRoot()
+	identity := currentIdentity.Load()
+	serviceRoot := models.NewServiceRoot(identity.Name, identity.UUID, identity.RedfishVersion)
+	serviceRoot.ProtocolFeaturesSupported = models.NewProtocolFeaturesSupported(int(maxExpandLevels.Load()))
+	if identity.Identification != nil {
+		serviceRoot.Oem = &models.ServiceRootOem{Contoso: identity.Identification}
+	}
 	etag := generateETag(serviceRoot)
 	w.Header().Set("ETag", etag)
 
@@ -293,15 +527,20 @@ func handleGetServiceRoot(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(serviceRoot)
+	body, err := json.Marshal(serviceRoot)
+	if err != nil {
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeFixedBody(w, r, append(body, '\n'))
 }
 
 // handleGetAccountService returns the account service
 func handleGetAccountService(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	accountService := models.NewAccountService()
-	etag := generateETag(accountService)
+	accountService := currentAccountService()
+	etag := resourceETag(accountService)
 	w.Header().Set("ETag", etag)
 
 	// Check conditional GET
@@ -314,56 +553,53 @@ func handleGetAccountService(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(accountService)
+	sendJSON(w, http.StatusOK, accountService)
+}
+
+// currentAccountService builds the AccountService with the live account policy
+func currentAccountService() *models.AccountService {
+	accountService := models.NewAccountService()
+	policy := auth.GetAuthService().GetAccountPolicy()
+	accountService.MinPasswordLength = policy.MinPasswordLength
+	accountService.MaxPasswordLength = policy.MaxPasswordLength
+	accountService.AccountLockoutThreshold = policy.AccountLockoutThreshold
+	accountService.AccountLockoutDuration = policy.AccountLockoutDuration
+	accountService.AccountLockoutCounterResetAfter = policy.AccountLockoutCounterResetAfter
+	accountService.ServiceEnabled = serviceEnabled("/redfish/v1/AccountService")
+	accountService.Status = serviceStatus("/redfish/v1/AccountService")
+	return accountService
 }
 
 // metadataHandler serves the OData metadata document
 func metadataHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, HEAD")
 
 	switch r.Method {
-	case "GET":
+	case "GET", "HEAD":
 		handleGetMetadata(w, r)
 	default:
 		methodNotAllowed(w, r)
 	}
 }
 
-// handleGetMetadata returns the OData metadata document
+// handleGetMetadata returns the OData metadata document, as CSDL XML or, when
+// selected with $format=json, as CSDL JSON
 func handleGetMetadata(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Content-Type", "application/xml;charset=utf-8")
-
-	// Basic metadata document with EntityContainer
-	metadata := `<?xml version="1.0" encoding="utf-8"?>
-<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
-  <edmx:DataServices>
-    <Schema Namespace="Service" xmlns="http://docs.oasis-open.org/odata/ns/edm">
-      <EntityType Name="ServiceRoot">
-        <Key>
-          <PropertyRef Name="Id" />
-        </Key>
-        <Property Name="Id" Type="Edm.String" Nullable="false" />
-        <Property Name="Name" Type="Edm.String" Nullable="false" />
-        <Property Name="RedfishVersion" Type="Edm.String" Nullable="false" />
-      </EntityType>
-      <EntityContainer Name="Service">
-        <EntitySet Name="ServiceRoot" EntityType="Service.ServiceRoot" />
-        <EntitySet Name="Systems" EntityType="ComputerSystemCollection.ComputerSystemCollection" />
-        <EntitySet Name="Chassis" EntityType="ChassisCollection.ChassisCollection" />
-        <EntitySet Name="Managers" EntityType="ManagerCollection.ManagerCollection" />
-        <EntitySet Name="TaskService" EntityType="TaskService.TaskService" />
-        <EntitySet Name="SessionService" EntityType="SessionService.SessionService" />
-        <EntitySet Name="AccountService" EntityType="AccountService.AccountService" />
-        <EntitySet Name="EventService" EntityType="EventService.EventService" />
-        <EntitySet Name="Registries" EntityType="MessageRegistryFileCollection.MessageRegistryFileCollection" />
-      </EntityContainer>
-    </Schema>
-  </edmx:DataServices>
-</edmx:Edmx>`
-
-	etag := generateETag(metadata)
+
+	metadata, contentType := []byte(metadataDocument), "application/xml;charset=utf-8"
+	if negotiateMediaType(r.Header.Get("Accept"), formatMediaTypesFor(r.URL.Path)) == "application/json" {
+		document, err := metadataJSON(metadataDocument)
+		if err != nil {
+			sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		metadata, contentType = document, "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+
+	etag := generateETag(string(metadata))
 	w.Header().Set("ETag", etag)
 
 	// Check conditional GET
@@ -376,7 +612,7 @@ func handleGetMetadata(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	w.Write([]byte(metadata))
+	writeFixedBody(w, r, metadata)
 }
 
 // handleGetOdata returns the OData service document
@@ -406,16 +642,16 @@ func handleGetOdata(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	w.Write([]byte(response))
+	writeFixedBody(w, r, []byte(response))
 }
 
 // odataHandler serves the OData service document
 func odataHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, HEAD")
 
 	switch r.Method {
-	case "GET":
+	case "GET", "HEAD":
 		handleGetOdata(w, r)
 	default:
 		methodNotAllowed(w, r)
@@ -425,11 +661,13 @@ func odataHandler(w http.ResponseWriter, r *http.Request) {
 // sessionServiceHandler handles the SessionService resource
 func sessionServiceHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, PATCH")
 
 	switch r.Method {
 	case "GET":
 		handleGetSessionService(w, r)
+	case "PATCH":
+		handlePatchServiceEnabled(w, r, "/redfish/v1/SessionService", func() interface{} { return currentSessionService() })
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -439,23 +677,7 @@ func sessionServiceHandler(w http.ResponseWriter, r *http.Request) {
 func handleGetSessionService(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	response := `{
-		"@odata.context": "/redfish/v1/$metadata#SessionService.SessionService",
-		"@odata.id": "/redfish/v1/SessionService",
-		"@odata.type": "#SessionService.v1_1_8.SessionService",
-		"Id": "SessionService",
-		"Name": "Session Service",
-		"Status": {
-			"State": "Enabled",
-			"Health": "OK"
-		},
-		"ServiceEnabled": true,
-		"SessionTimeout": 3600,
-		"Sessions": {
-			"@odata.id": "/redfish/v1/SessionService/Sessions"
-		}
-	}`
-
+	response := currentSessionService()
 	etag := generateETag(response)
 	w.Header().Set("ETag", etag)
 
@@ -469,7 +691,24 @@ func handleGetSessionService(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	w.Write([]byte(response))
+	sendJSON(w, http.StatusOK, response)
+}
+
+// currentSessionService builds the SessionService resource
+func currentSessionService() map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.context": "/redfish/v1/$metadata#SessionService.SessionService",
+		"@odata.id":      "/redfish/v1/SessionService",
+		"@odata.type":    "#SessionService.v1_1_8.SessionService",
+		"Id":             "SessionService",
+		"Name":           "Session Service",
+		"Status":         serviceStatus("/redfish/v1/SessionService"),
+		"ServiceEnabled": serviceEnabled("/redfish/v1/SessionService"),
+		"SessionTimeout": 3600,
+		"Sessions": map[string]string{
+			"@odata.id": "/redfish/v1/SessionService/Sessions",
+		},
+	}
 }
 
 // sessionsHandler handles session collection and creation
@@ -491,16 +730,53 @@ func sessionsHandler(w http.ResponseWriter, r *http.Request) {
 func handleGetSessions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	response := `{
-		"@odata.context": "/redfish/v1/$metadata#SessionCollection.SessionCollection",
-		"@odata.id": "/redfish/v1/SessionService/Sessions",
-		"@odata.type": "#SessionCollection.SessionCollection",
-		"Name": "Sessions Collection",
-		"Members": [],
-		"Members@odata.count": 0
-	}`
+	members := sessionMembers(r)
+	collection := models.Collection{
+		ODataContext:      "/redfish/v1/$metadata#SessionCollection.SessionCollection",
+		ODataID:           "/redfish/v1/SessionService/Sessions",
+		ODataType:         "#SessionCollection.SessionCollection",
+		Name:              "Sessions Collection",
+		Members:           models.CollectionMembers(members),
+		MembersODataCount: len(members),
+	}
 
-	etag := generateETag(response)
+	etag := generateETag(collection)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, collection)
+}
+
+// sessionMembersHandler handles the Members property of the sessions collection
+func sessionMembersHandler(w http.ResponseWriter, r *http.Request) {
+	setRedfishHeaders(w)
+	w.Header().Set("Allow", "GET, POST")
+
+	switch r.Method {
+	case "GET":
+		handleGetSessionMembers(w, r)
+	case "POST":
+		handleCreateSession(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// handleGetSessionMembers returns the bare Members array of the sessions collection
+func handleGetSessionMembers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	members := sessionMembers(r)
+	etag := generateETag(members)
 	w.Header().Set("ETag", etag)
 
 	// Check conditional GET
@@ -513,11 +789,40 @@ func handleGetSessions(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	w.Write([]byte(response))
+	sendJSON(w, http.StatusOK, members)
+}
+
+// sessionMembers returns links to the active sessions the requester may
+// see: every session for holders of ConfigureUsers, and otherwise only the
+// requester's own, which ConfigureSelf covers
+func sessionMembers(r *http.Request) []models.Link {
+	sessions := auth.GetAuthService().ListSessions()
+	members := make([]models.Link, 0, len(sessions))
+	for _, session := range sessions {
+		if !maySeeSession(r, *session) {
+			continue
+		}
+		members = append(members, models.Link{ODataID: models.NewODataID("/redfish/v1/SessionService/Sessions", session.ID)})
+	}
+	return members
+}
+
+// maySeeSession reports whether the requester may see a session: their own,
+// or anyone's with ConfigureUsers
+func maySeeSession(r *http.Request, session auth.Session) bool {
+	if hasPrivilege(r, "ConfigureUsers") {
+		return true
+	}
+	userCtx, authenticated := auth.GetUserContext(r.Context())
+	return authenticated && session.Username == userCtx.Username
 }
 
 // handleCreateSession creates a new session (login)
 func handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	if !requireServiceEnabled(w, "/redfish/v1/SessionService") {
+		return
+	}
+
 	var username, password string
 	var ok bool
 
@@ -538,99 +843,152 @@ func handleCreateSession(w http.ResponseWriter, r *http.Request) {
 
 	if !ok || username == "" || password == "" {
 		w.Header().Set("WWW-Authenticate", `Basic realm="Redfish Service"`)
-		http.Error(w, `{"error": {"code": "Base.1.0.InsufficientPrivilege", "message": "Authentication required"}}`, http.StatusUnauthorized)
+		sendRedfishError(w, "InsufficientPrivilege", "Authentication required", http.StatusUnauthorized)
 		return
 	}
 
-	// Validate credentials
+	// Validate credentials with the configured provider; sessions are always
+	// kept by the in-memory AuthService
 	authService := auth.GetAuthService()
-	if !authService.ValidateBasicAuth(username, password) {
+	if !auth.GetAuthenticator().AuthenticateBasic(username, password) {
 		w.Header().Set("WWW-Authenticate", `Basic realm="Redfish Service"`)
-		http.Error(w, `{"error": {"code": "Base.1.0.InsufficientPrivilege", "message": "Invalid credentials"}}`, http.StatusUnauthorized)
+		sendRedfishError(w, "InsufficientPrivilege", "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
 	// Create session
 	token, err := authService.CreateSession(username)
 	if err != nil {
-		http.Error(w, `{"error": {"code": "Base.1.0.InternalError", "message": "Failed to create session"}}`, http.StatusInternalServerError)
+		log.Printf("Failed to create session for %s: %v", username, err)
+		sendRedfishError(w, "InternalError", "Failed to create session", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	session, _ := authService.GetSession(token)
+	resource := sessionResource(session)
 	w.Header().Set("X-Auth-Token", token)
-	w.Header().Set("Location", "https://"+r.Host+"/redfish/v1/SessionService/Sessions/"+token)
-	w.WriteHeader(http.StatusCreated)
-
-	response := fmt.Sprintf(`{
-		"@odata.context": "/redfish/v1/$metadata#Session.Session",
-		"@odata.id": "/redfish/v1/SessionService/Sessions/%s",
-		"@odata.type": "#Session.v1_1_6.Session",
-		"Id": "%s",
-		"Name": "User Session",
-		"UserName": "%s"
-	}`, token, token, username)
-
-	w.Write([]byte(response))
+	w.Header().Set("Location", "https://"+r.Host+string(resource.ODataID))
+	w.Header().Set("ETag", resourceETag(resource))
+	sendJSON(w, http.StatusCreated, resource)
 }
 
 // sessionItemHandler handles individual session resources
 func sessionItemHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET, DELETE")
+	w.Header().Set("Allow", "GET, POST, DELETE")
 
 	// Extract session ID from URL path
 	sessionID := strings.TrimPrefix(r.URL.Path, "/redfish/v1/SessionService/Sessions/")
 
-	// Validate session exists and authentication
-	authService := auth.GetAuthService()
-	_, sessionExists := authService.ValidateSessionToken(sessionID)
-	if !sessionExists {
-		sendRedfishError(w, "ResourceNotFound", "Session not found", http.StatusNotFound)
+	// The Id only names the session; the token is never part of the URI
+	session, ok := auth.GetAuthService().GetSessionByID(sessionID)
+	if !ok {
+		sendResourceNotFound(w, r)
 		return
 	}
 
-	// For session resources, the session ID in the URL serves as authentication
-	// No additional token validation required
-
 	switch r.Method {
 	case "GET":
-		handleGetSession(w, r, sessionID)
+		handleGetSession(w, r, session)
+	case "POST":
+		handleRefreshSession(w, r, session)
 	case "DELETE":
-		handleDeleteSession(w, r, sessionID)
+		handleDeleteSession(w, r, session)
 	default:
 		methodNotAllowed(w, r)
 	}
 }
 
-// handleGetSession returns a specific session
-func handleGetSession(w http.ResponseWriter, r *http.Request, sessionID string) {
-	// Session existence already validated in sessionItemHandler
-	authService := auth.GetAuthService()
-	username, _ := authService.ValidateSessionToken(sessionID)
+// handleGetSession returns a specific session. Like the collection, it shows
+// the sessions of other users only to holders of ConfigureUsers.
+func handleGetSession(w http.ResponseWriter, r *http.Request, session auth.Session) {
+	if !maySeeSession(r, session) {
+		sendResourceNotFound(w, r)
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	resource := sessionResource(session)
+	etag := resourceETag(resource)
+	w.Header().Set("ETag", etag)
+
+	// Check conditional GET; the ETag changes when the session is refreshed
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	sendJSON(w, http.StatusOK, resource)
+}
+
+// handleRefreshSession extends the expiry of a session (POST)
+func handleRefreshSession(w http.ResponseWriter, r *http.Request, session auth.Session) {
+	// Only the holder of the token may refresh it
+	if r.Header.Get("X-Auth-Token") != session.Token {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Redfish Service"`)
+		sendRedfishError(w, "InsufficientPrivilege", "A valid X-Auth-Token for this session is required", http.StatusUnauthorized)
+		return
+	}
+
+	session, ok := auth.GetAuthService().RefreshSession(session.Token)
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	sendUpdatedResource(w, sessionResource(session))
+}
 
-	response := fmt.Sprintf(`{
-		"@odata.context": "/redfish/v1/$metadata#Session.Session",
-		"@odata.id": "/redfish/v1/SessionService/Sessions/%s",
-		"@odata.type": "#Session.v1_1_6.Session",
-		"Id": "%s",
-		"Name": "User Session",
-		"UserName": "%s"
-	}`, sessionID, sessionID, username)
+// sessionETag is the ETag of a session's representation. It changes when the
+// session is refreshed.
+func sessionETag(session auth.Session) string {
+	return generateETag(sessionResource(session))
+}
 
-	w.Write([]byte(response))
+// sessionResource builds the Session resource for a session
+func sessionResource(session auth.Session) *models.Session {
+	return models.NewSession(session.ID, session.Username, session.Expires)
 }
 
 // handleDeleteSession terminates a session
-func handleDeleteSession(w http.ResponseWriter, r *http.Request, sessionID string) {
-	authService := auth.GetAuthService()
-	authService.DeleteSession(sessionID)
+// Clients that send "Prefer: return=representation" and accept JSON get a 200
+// with a Base.1.0.Success message for their audit trail; others get a 204.
+func handleDeleteSession(w http.ResponseWriter, r *http.Request, session auth.Session) {
+	if !mayDeleteSession(w, r, session) {
+		return
+	}
+	if !ifMatchSatisfied(r, sessionETag(session)) {
+		sendPreconditionFailed(w, r.URL.Path)
+		return
+	}
+	auth.GetAuthService().DeleteSession(session.Token)
+
+	accept := r.Header.Get("Accept")
+	if prefersRepresentation(r) && accept != "" && acceptableMediaType(accept, []string{"application/json"}) {
+		w.Header().Set("Preference-Applied", "return=representation")
+		sendRedfishMessage(w, "Success", "Session deleted successfully", http.StatusOK)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// mayDeleteSession checks that the requester may delete the session: their
+// own with ConfigureSelf, or anyone's with ConfigureUsers or ConfigureManager.
+// Knowing the token is not enough. It sends a 403 and returns false otherwise.
+func mayDeleteSession(w http.ResponseWriter, r *http.Request, session auth.Session) bool {
+	if hasPrivilege(r, "ConfigureUsers") || hasPrivilege(r, "ConfigureManager") {
+		return true
+	}
+	if userCtx, ok := auth.GetUserContext(r.Context()); !ok || userCtx.Username != session.Username {
+		sendRedfishError(w, "InsufficientPrivilege", "The ConfigureUsers or ConfigureManager privilege is required to delete the sessions of other users", http.StatusForbidden)
+		return false
+	}
+	return requirePrivilege(w, r, "ConfigureSelf")
+}
+
 // accountServiceHandler handles the AccountService resource
 func accountServiceHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
@@ -646,19 +1004,92 @@ func accountServiceHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleUpdateAccountService updates the account service (PATCH)
+// handleUpdateAccountService updates the password and lockout policy, and
+// enables or disables the service (PATCH)
 func handleUpdateAccountService(w http.ResponseWriter, r *http.Request) {
-	sendRedfishError(w, "MethodNotAllowed", "AccountService updates not implemented", http.StatusMethodNotAllowed)
+	if !requirePrivilege(w, r, "ConfigureManager") {
+		return
+	}
+
+	var requestBody struct {
+		ServiceEnabled                  *bool `json:"ServiceEnabled"`
+		MinPasswordLength               *int  `json:"MinPasswordLength"`
+		MaxPasswordLength               *int  `json:"MaxPasswordLength"`
+		AccountLockoutThreshold         *int  `json:"AccountLockoutThreshold"`
+		AccountLockoutDuration          *int  `json:"AccountLockoutDuration"`
+		AccountLockoutCounterResetAfter *int  `json:"AccountLockoutCounterResetAfter"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	authService := auth.GetAuthService()
+	policy := authService.GetAccountPolicy()
+	updates := []struct {
+		value *int
+		field *int
+	}{
+		{requestBody.MinPasswordLength, &policy.MinPasswordLength},
+		{requestBody.MaxPasswordLength, &policy.MaxPasswordLength},
+		{requestBody.AccountLockoutThreshold, &policy.AccountLockoutThreshold},
+		{requestBody.AccountLockoutDuration, &policy.AccountLockoutDuration},
+		{requestBody.AccountLockoutCounterResetAfter, &policy.AccountLockoutCounterResetAfter},
+	}
+	for _, update := range updates {
+		if update.value != nil {
+			*update.field = *update.value
+		}
+	}
+
+	var rangeErr *auth.PolicyRangeError
+	err := authService.SetAccountPolicy(policy)
+	switch {
+	case errors.As(err, &rangeErr):
+		sendRedfishError(w, "PropertyValueOutOfRange", err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if requestBody.ServiceEnabled != nil {
+		toggleableServices["/redfish/v1/AccountService"].Store(*requestBody.ServiceEnabled)
+	}
+	rotateSessionToken(w, r)
+
+	sendUpdatedResource(w, currentAccountService())
+}
+
+// rotateSessionToken replaces the token of a caller who authenticated with a
+// session after a security-sensitive change, so a token captured earlier no
+// longer works, and returns the new one in X-Auth-Token. The session keeps
+// its Id and URI.
+func rotateSessionToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.GetUserContext(r.Context())
+	if !ok || user.Method != "Session" {
+		return
+	}
+	token, err := auth.GetAuthService().RotateToken(r.Header.Get("X-Auth-Token"))
+	if err != nil {
+		log.Printf("Failed to rotate the session token of %s: %v", user.Username, err)
+		return
+	}
+	w.Header().Set("X-Auth-Token", token)
 }
 
 // accountsHandler handles the accounts collection
 func accountsHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, POST")
 
 	switch r.Method {
 	case "GET":
 		handleGetAccounts(w, r)
+	case "POST":
+		if requireServiceEnabled(w, "/redfish/v1/AccountService") {
+			handleCreateAccount(w, r)
+		}
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -669,6 +1100,14 @@ func handleGetAccounts(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	accounts := models.NewManagerAccountCollection()
+	users := auth.GetAuthService().ListUsers()
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	accounts.Members = make([]models.Link, 0, len(users))
+	for _, user := range users {
+		accounts.Members = append(accounts.Members, models.Link{ODataID: models.ODataID("/redfish/v1/AccountService/Accounts/" + user.Username)})
+	}
+	accounts.MembersODataCount = len(accounts.Members)
+
 	etag := generateETag(accounts)
 	w.Header().Set("ETag", etag)
 
@@ -682,24 +1121,115 @@ func handleGetAccounts(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(accounts)
+	sendJSON(w, http.StatusOK, accounts)
 }
 
-// handleCreateAccount creates a new user account
-func handleCreateAccount(w http.ResponseWriter, r *http.Request) {
-	// For now, account creation is not implemented
-	sendRedfishError(w, "MethodNotAllowed", "Account creation not implemented", http.StatusMethodNotAllowed)
+// defaultRole is the role of accounts created without a RoleId. New sets it
+// from the configuration.
+var defaultRole atomic.Pointer[string]
+
+func init() {
+	role := config.DefaultRoleId
+	defaultRole.Store(&role)
 }
 
-// accountHandler handles individual account resources
-func accountHandler(w http.ResponseWriter, r *http.Request) {
-	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET, PATCH, PUT, DELETE")
+// setDefaultRole applies the configured default role, with empty selecting
+// config.DefaultRoleId. The role must be one of the predefined roles.
+func setDefaultRole(role string) error {
+	if role == "" {
+		role = config.DefaultRoleId
+	}
+	if _, ok := auth.RolePrivileges(role); !ok {
+		return fmt.Errorf("default role %q is not a predefined role", role)
+	}
+	defaultRole.Store(&role)
+	return nil
+}
 
-	// Extract username from URL path
+// setSessionTokenBytes sets how many random bytes session tokens hold, with
+// zero selecting auth.DefaultSessionTokenBytes
+func setSessionTokenBytes(n int) error {
+	if n == 0 {
+		n = auth.DefaultSessionTokenBytes
+	}
+	generate, err := auth.RandomTokenGenerator(n)
+	if err != nil {
+		return err
+	}
+	auth.GetAuthService().SetTokenGenerator(generate)
+	return nil
+}
+
+// handleCreateAccount creates a new user account
+func handleCreateAccount(w http.ResponseWriter, r *http.Request) {
+	if !requirePrivilege(w, r, "ConfigureUsers") {
+		return
+	}
+
+	var requestBody struct {
+		UserName string `json:"UserName"`
+		Password string `json:"Password"`
+		RoleId   string `json:"RoleId"`
+		Enabled  *bool  `json:"Enabled"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.UserName == "" {
+		sendRedfishError(w, "PropertyMissing", "UserName is required", http.StatusBadRequest)
+		return
+	}
+	if requestBody.Password == "" {
+		sendRedfishError(w, "PropertyMissing", "Password is required", http.StatusBadRequest)
+		return
+	}
+	if requestBody.RoleId == "" {
+		requestBody.RoleId = *defaultRole.Load()
+	}
+	enabled := true
+	if requestBody.Enabled != nil {
+		enabled = *requestBody.Enabled
+	}
+
+	err := auth.GetAuthService().CreateUser(requestBody.UserName, requestBody.Password, requestBody.RoleId, enabled)
+	switch {
+	case errors.Is(err, auth.ErrUserExists):
+		sendRedfishError(w, "ResourceAlreadyExists", err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, auth.ErrUnknownRole):
+		sendRedfishError(w, "PropertyValueNotInList", err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, auth.ErrPasswordPolicy):
+		sendRedfishError(w, "PropertyValueFormatError", err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	account := models.NewManagerAccount(requestBody.UserName, requestBody.RoleId, enabled)
+
+	w.Header().Set("Location", string(account.ODataID))
+	sendJSON(w, http.StatusCreated, account)
+}
+
+// accountHandler handles individual account resources
+func accountHandler(w http.ResponseWriter, r *http.Request) {
+	setRedfishHeaders(w)
+	w.Header().Set("Allow", "GET, PATCH, PUT, DELETE")
+
+	// Extract username from URL path
 	path := r.URL.Path
 	username := path[len("/redfish/v1/AccountService/Accounts/"):]
 
+	// Accounts cannot be changed while the service is disabled
+	if r.Method != "GET" && !requireServiceEnabled(w, "/redfish/v1/AccountService") {
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		handleGetAccount(w, r, username)
@@ -718,17 +1248,14 @@ func accountHandler(w http.ResponseWriter, r *http.Request) {
 func handleGetAccount(w http.ResponseWriter, r *http.Request, username string) {
 	w.Header().Set("Content-Type", "application/json")
 
-	// For demo purposes, only support admin and operator accounts
-	var account *models.ManagerAccount
-	switch username {
-	case "admin":
-		account = models.NewManagerAccount("admin", "Administrator", true)
-	case "operator":
-		account = models.NewManagerAccount("operator", "Operator", true)
-	default:
-		sendRedfishError(w, "ResourceNotFound", "Account not found", http.StatusNotFound)
+	authService := auth.GetAuthService()
+	user, exists := authService.GetUser(username)
+	if !exists {
+		sendResourceNotFound(w, r)
 		return
 	}
+	account := models.NewManagerAccount(user.Username, user.Role, user.Enabled)
+	account.Locked = authService.IsLocked(username)
 
 	etag := generateETag(account)
 	w.Header().Set("ETag", etag)
@@ -743,22 +1270,22 @@ func handleGetAccount(w http.ResponseWriter, r *http.Request, username string) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(account)
+	sendJSON(w, http.StatusOK, account)
 }
 
 // handleUpdateAccount updates an account (PATCH)
 func handleUpdateAccount(w http.ResponseWriter, r *http.Request, username string) {
-	sendRedfishError(w, "MethodNotAllowed", "Account updates not implemented", http.StatusMethodNotAllowed)
+	notImplemented(w, r)
 }
 
 // handleReplaceAccount replaces an account (PUT)
 func handleReplaceAccount(w http.ResponseWriter, r *http.Request, username string) {
-	sendRedfishError(w, "MethodNotAllowed", "Account replacement not implemented", http.StatusMethodNotAllowed)
+	notImplemented(w, r)
 }
 
 // handleDeleteAccount deletes an account
 func handleDeleteAccount(w http.ResponseWriter, r *http.Request, username string) {
-	sendRedfishError(w, "MethodNotAllowed", "Account deletion not implemented", http.StatusMethodNotAllowed)
+	notImplemented(w, r)
 }
 
 // rolesHandler handles the roles collection
@@ -794,7 +1321,7 @@ func handleGetRoles(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(roles)
+	sendJSON(w, http.StatusOK, roles)
 }
 
 // roleHandler handles individual role resources
@@ -820,18 +1347,12 @@ func roleHandler(w http.ResponseWriter, r *http.Request) {
 func handleGetRole(w http.ResponseWriter, r *http.Request, id string) {
 	w.Header().Set("Content-Type", "application/json")
 
-	var role *models.Role
-	switch id {
-	case "Administrator":
-		role = models.NewRole("Administrator", "Administrator", []string{"Login", "ConfigureManager", "ConfigureUsers", "ConfigureComponents", "ConfigureSelf"}, true)
-	case "Operator":
-		role = models.NewRole("Operator", "Operator", []string{"Login", "ConfigureComponents", "ConfigureSelf"}, true)
-	case "ReadOnly":
-		role = models.NewRole("ReadOnly", "ReadOnly", []string{"Login", "ConfigureSelf"}, true)
-	default:
-		sendRedfishError(w, "ResourceNotFound", "Role not found", http.StatusNotFound)
+	privileges, ok := auth.RolePrivileges(id)
+	if !ok {
+		sendResourceNotFound(w, r)
 		return
 	}
+	role := models.NewRole(id, id, privileges, true)
 
 	etag := generateETag(role)
 	w.Header().Set("ETag", etag)
@@ -846,7 +1367,7 @@ func handleGetRole(w http.ResponseWriter, r *http.Request, id string) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(role)
+	sendJSON(w, http.StatusOK, role)
 }
 
 // systemsHandler handles the computer systems collection
@@ -867,18 +1388,39 @@ func handleGetSystems(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	systems := models.NewComputerSystemCollection()
+	systems.Members = make([]models.Link, 0)
+	for _, id := range systemStore.IDs() {
+		systems.Members = append(systems.Members, models.Link{ODataID: models.ODataID("/redfish/v1/Systems/" + id)})
+	}
+	systems.MembersODataCount = len(systems.Members)
+	systems.Actions = composeActions()
 
 	// Parse query parameters
 	queryParams, err := parseQueryParameters(r.URL.Query())
 	if err != nil {
-		sendRedfishError(w, "QueryParameterError", err.Error(), http.StatusBadRequest)
+		sendQueryError(w, err)
 		return
 	}
 
 	// Apply query parameters
 	systems = applyQueryParametersToSystems(systems, queryParams)
 
-	etag := generateETag(systems)
+	if negotiateMediaType(r.Header.Get("Accept"), producedMediaTypes(r.URL.Path)) == csvMediaType {
+		sendSystemsCSV(w, systems.Members)
+		return
+	}
+
+	var response interface{} = systems
+	if expandsMembers(queryParams.Expand) {
+		expanded, err := expandSystemMembers(systems, queryParams.Select)
+		if err != nil {
+			sendRedfishError(w, "InternalError", "Failed to expand Members", http.StatusInternalServerError)
+			return
+		}
+		response = expanded
+	}
+
+	etag := generateETag(response)
 	w.Header().Set("ETag", etag)
 
 	// Check conditional GET
@@ -891,31 +1433,52 @@ func handleGetSystems(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(systems)
+	sendJSON(w, http.StatusOK, response)
 }
 
 // handleCreateSystem creates a new computer system (not typically allowed in Redfish)
 func handleCreateSystem(w http.ResponseWriter, r *http.Request) {
 	// Computer systems are typically not created via POST in Redfish
 	// This would be a BMC implementation detail
-	sendRedfishError(w, "MethodNotAllowed", "ComputerSystem creation not supported", http.StatusMethodNotAllowed)
+	sendRedfishError(w, "OperationNotAllowed", "ComputerSystem creation not supported", http.StatusMethodNotAllowed)
 }
 
 // systemHandler handles individual computer system resources and actions
 func systemHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET, PATCH, PUT, DELETE")
+	w.Header().Set("Allow", "GET, PATCH, PUT")
 
 	path := r.URL.Path
 
+	// Actions on the collection itself, such as composing a new system
+	if strings.HasPrefix(path, "/redfish/v1/Systems/Actions/") {
+		handleSystemsCollectionAction(w, r, path)
+		return
+	}
+
 	// Check if this is an action request
 	if strings.Contains(path, "/Actions/") {
 		handleSystemAction(w, r, path)
 		return
 	}
 
-	// Extract system ID from URL path
-	id := path[len("/redfish/v1/Systems/"):]
+	// Extract system ID and any sub-resource from URL path
+	segments := strings.Split(strings.Trim(path[len("/redfish/v1/Systems/"):], "/"), "/")
+	id := segments[0]
+
+	if len(segments) > 1 {
+		if _, ok := systemStore.Get(id); !ok {
+			sendResourceNotFound(w, r)
+			return
+		}
+		switch segments[1] {
+		case "Bios":
+			biosHandler(w, r, id, segments[2:])
+		default:
+			sendResourceNotFound(w, r)
+		}
+		return
+	}
 
 	switch r.Method {
 	case "GET":
@@ -924,8 +1487,6 @@ func systemHandler(w http.ResponseWriter, r *http.Request) {
 		handleUpdateSystem(w, r, id)
 	case "PUT":
 		handleReplaceSystem(w, r, id)
-	case "DELETE":
-		handleDeleteSystem(w, r, id)
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -935,12 +1496,24 @@ func systemHandler(w http.ResponseWriter, r *http.Request) {
 func handleGetSystem(w http.ResponseWriter, r *http.Request, id string) {
 	w.Header().Set("Content-Type", "application/json")
 
-	system := models.NewComputerSystem(id)
+	// Plain GETs, the bulk of polling traffic, are answered from the cache
+	if r.URL.RawQuery == "" {
+		if !sendCachedResource(w, r, models.NewODataID("/redfish/v1/Systems", id), func() (interface{}, bool) { return getSystem(id) }) {
+			sendResourceNotFound(w, r)
+		}
+		return
+	}
+
+	system, ok := getSystem(id)
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
 
 	// Parse query parameters
 	queryParams, err := parseQueryParameters(r.URL.Query())
 	if err != nil {
-		sendRedfishError(w, "QueryParameterError", err.Error(), http.StatusBadRequest)
+		sendQueryError(w, err)
 		return
 	}
 
@@ -954,7 +1527,7 @@ func handleGetSystem(w http.ResponseWriter, r *http.Request, id string) {
 		system = applyExpandToSystem(system, queryParams.Expand)
 	}
 
-	etag := generateETag(system)
+	etag := resourceETag(system)
 	w.Header().Set("ETag", etag)
 
 	// Check conditional GET
@@ -967,25 +1540,182 @@ func handleGetSystem(w http.ResponseWriter, r *http.Request, id string) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(system)
+	if len(queryParams.Select) > 0 {
+		projection, err := selectProperties(system, queryParams.Select)
+		if err != nil {
+			sendRedfishError(w, "InternalError", "Failed to apply $select", http.StatusInternalServerError)
+			return
+		}
+		sendJSON(w, http.StatusOK, projection)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, system)
+}
+
+// getSystem returns a copy of a stored computer system with its Oem block
+// built from the currently registered vendors
+func getSystem(id string) (*models.ComputerSystem, bool) {
+	system, ok := systemStore.Get(id)
+	if !ok {
+		return nil, false
+	}
+	decorateSystem(system)
+	return system, true
+}
+
+// decorateSystem adds what is served with a stored computer system but not
+// stored with it: the Oem block and OEM actions of the registered vendors,
+// and the Reset action only if the system supports some reset type
+func decorateSystem(system *models.ComputerSystem) {
+	system.Oem = models.BuildOem("ComputerSystem", system.ID)
+	system.Actions.Oem = oemActionTargets("ComputerSystem", system.ODataID)
+	if len(systemResetTypes(system.ID)) == 0 {
+		system.Actions.ComputerSystemReset = nil
+	}
+}
+
+// Allowed values of a ComputerSystem's IndicatorLED
+var indicatorLEDValues = []string{"Lit", "Blinking", "Off"}
+
+// Allowed values of the writable Boot properties of a ComputerSystem
+var systemBootValues = map[string][]string{
+	"BootSourceOverrideEnabled": {"Disabled", "Once", "Continuous"},
+	"BootSourceOverrideTarget":  {"None", "Pxe", "Floppy", "Cd", "Usb", "Hdd", "BiosSetup", "Utilities", "Diags", "UefiShell", "UefiTarget", "SDCard", "UefiHttp", "RemoteDrive", "UefiBootNext"},
+	"BootSourceOverrideMode":    {"Legacy", "UEFI"},
 }
 
-// handleUpdateSystem updates a computer system (PATCH)
+// handleUpdateSystem updates the writable properties of a computer system (PATCH)
 func handleUpdateSystem(w http.ResponseWriter, r *http.Request, id string) {
-	// For now, systems are read-only in this implementation
-	sendRedfishError(w, "MethodNotAllowed", "ComputerSystem updates not supported", http.StatusMethodNotAllowed)
+	if !requirePrivilege(w, r, "ConfigureComponents") {
+		return
+	}
+
+	var requestBody map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate the whole request before changing anything
+	var changes []func(*models.ComputerSystem)
+	for name, raw := range requestBody {
+		switch name {
+		case "AssetTag", "HostName", "IndicatorLED":
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				sendRedfishError(w, "PropertyValueTypeError", fmt.Sprintf("%s must be a string, got %s", name, raw), http.StatusBadRequest)
+				return
+			}
+			switch name {
+			case "AssetTag":
+				changes = append(changes, func(system *models.ComputerSystem) { system.AssetTag = value })
+			case "HostName":
+				if err := validateHostName(value); err != nil {
+					sendRedfishError(w, "PropertyValueFormatError", err.Error(), http.StatusBadRequest)
+					return
+				}
+				changes = append(changes, func(system *models.ComputerSystem) { system.HostName = value })
+			case "IndicatorLED":
+				if err := models.ValidateEnum(name, value, indicatorLEDValues); err != nil {
+					sendRedfishError(w, "PropertyValueNotInList", err.Error(), http.StatusBadRequest)
+					return
+				}
+				changes = append(changes, func(system *models.ComputerSystem) { system.IndicatorLED = value })
+			}
+		case "Boot":
+			var boot map[string]string
+			if err := json.Unmarshal(raw, &boot); err != nil {
+				sendRedfishError(w, "PropertyValueTypeError", fmt.Sprintf("Boot must be an object of strings, got %s", raw), http.StatusBadRequest)
+				return
+			}
+			for property, value := range boot {
+				allowed, writable := systemBootValues[property]
+				if !writable {
+					sendRedfishError(w, "PropertyNotWritable", fmt.Sprintf("The property Boot/%s is read only", property), http.StatusBadRequest)
+					return
+				}
+				if err := models.ValidateEnum("Boot/"+property, value, allowed); err != nil {
+					sendRedfishError(w, "PropertyValueNotInList", err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			changes = append(changes, func(system *models.ComputerSystem) {
+				for property, value := range boot {
+					switch property {
+					case "BootSourceOverrideEnabled":
+						system.Boot.BootSourceOverrideEnabled = value
+					case "BootSourceOverrideTarget":
+						system.Boot.BootSourceOverrideTarget = value
+					case "BootSourceOverrideMode":
+						system.Boot.BootSourceOverrideMode = value
+					}
+				}
+			})
+		default:
+			sendRedfishError(w, "PropertyNotWritable", fmt.Sprintf("The property %s is read only", name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var changed []string
+	system, err := systemStore.Update(id, func(system *models.ComputerSystem) error {
+		before := *system
+		for _, change := range changes {
+			change(system)
+		}
+		changed = changedSystemProperties(&before, system)
+		return nil
+	})
+	if err != nil {
+		sendResourceNotFound(w, r)
+		return
+	}
+	decorateSystem(system)
+
+	if len(changed) > 0 {
+		publishEvent(backgroundContext(r), resourceChangedEvent(system.ODataID, changed))
+	}
+
+	sendUpdatedResource(w, system)
 }
 
-// handleReplaceSystem replaces a computer system (PUT)
-func handleReplaceSystem(w http.ResponseWriter, r *http.Request, id string) {
-	// For now, systems are read-only in this implementation
-	sendRedfishError(w, "MethodNotAllowed", "ComputerSystem replacement not supported", http.StatusMethodNotAllowed)
+// validateHostName checks that a HostName is a DNS label: 1 to 63 letters,
+// digits and hyphens, neither starting nor ending with a hyphen
+func validateHostName(name string) error {
+	if len(name) == 0 || len(name) > 63 {
+		return fmt.Errorf("HostName %q must be 1 to 63 characters long", name)
+	}
+	if name[0] == '-' || name[len(name)-1] == '-' {
+		return fmt.Errorf("HostName %q must not start or end with a hyphen", name)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return fmt.Errorf("HostName %q may only contain letters, digits and hyphens", name)
+		}
+	}
+	return nil
+}
+
+// changedSystemProperties lists the identifying properties that differ
+// between two versions of a computer system
+func changedSystemProperties(before, after *models.ComputerSystem) []string {
+	var changed []string
+	if before.AssetTag != after.AssetTag {
+		changed = append(changed, "AssetTag")
+	}
+	if before.HostName != after.HostName {
+		changed = append(changed, "HostName")
+	}
+	if before.IndicatorLED != after.IndicatorLED {
+		changed = append(changed, "IndicatorLED")
+	}
+	return changed
 }
 
-// handleDeleteSystem deletes a computer system
-func handleDeleteSystem(w http.ResponseWriter, r *http.Request, id string) {
-	// Computer systems are typically not deleted in Redfish
-	sendRedfishError(w, "MethodNotAllowed", "ComputerSystem deletion not supported", http.StatusMethodNotAllowed)
+// handleReplaceSystem replaces a computer system (PUT)
+func handleReplaceSystem(w http.ResponseWriter, r *http.Request, id string) {
+	notImplemented(w, r)
 }
 
 // handleSystemAction handles ComputerSystem actions
@@ -993,12 +1723,28 @@ func handleSystemAction(w http.ResponseWriter, r *http.Request, path string) {
 	// Extract action from path: /redfish/v1/Systems/{id}/Actions/{ActionName}
 	parts := strings.Split(path, "/")
 	if len(parts) < 7 || parts[5] != "Actions" {
-		sendRedfishError(w, "InvalidAction", "Invalid action URI format", http.StatusBadRequest)
+		sendRedfishError(w, "ActionNotSupported", "Invalid action URI format", http.StatusBadRequest)
 		return
 	}
 
 	actionName := parts[6]
 	systemId := parts[4]
+	if _, ok := systemStore.Get(systemId); !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+
+	// OEM actions are dispatched to the vendor that registered them
+	if actionName == "Oem" {
+		handleOemResourceAction(w, r, "ComputerSystem", systemId, strings.Join(parts[7:], "/"))
+		return
+	}
+
+	// Only actions the system advertises are available
+	if actionName == "ComputerSystem.Reset" && len(systemResetTypes(systemId)) == 0 {
+		sendRedfishError(w, "ActionNotSupported", fmt.Sprintf("Action %s not supported by ComputerSystem %s", actionName, systemId), http.StatusBadRequest)
+		return
+	}
 
 	switch r.Method {
 	case "GET":
@@ -1011,7 +1757,10 @@ func handleSystemAction(w http.ResponseWriter, r *http.Request, path string) {
 	case "POST":
 		switch actionName {
 		case "ComputerSystem.Reset":
-			handleComputerSystemReset(w, r, systemId)
+			// The reset runs as a task
+			if requireServiceEnabled(w, "/redfish/v1/TaskService") {
+				handleComputerSystemReset(w, r, systemId)
+			}
 		default:
 			sendRedfishError(w, "ActionNotSupported", fmt.Sprintf("Action %s not supported for ComputerSystem", actionName), http.StatusBadRequest)
 		}
@@ -1024,21 +1773,7 @@ func handleSystemAction(w http.ResponseWriter, r *http.Request, path string) {
 func handleComputerSystemResetActionInfo(w http.ResponseWriter, r *http.Request, systemId string) {
 	w.Header().Set("Content-Type", "application/json")
 
-	response := map[string]interface{}{
-		"@odata.context": "/redfish/v1/$metadata#ActionInfo.ActionInfo",
-		"@odata.id":      fmt.Sprintf("/redfish/v1/Systems/%s/Actions/ComputerSystem.Reset", systemId),
-		"@odata.type":    "#ActionInfo.v1_1_2.ActionInfo",
-		"Id":             "ComputerSystem.Reset",
-		"Name":           "Computer System Reset",
-		"Parameters": []map[string]interface{}{
-			{
-				"Name":            "ResetType",
-				"Required":        false,
-				"DataType":        "String",
-				"AllowableValues": []string{"On", "ForceOff", "ForceRestart", "Nmi", "PushPowerButton", "GracefulRestart", "GracefulShutdown", "ForceOn"},
-			},
-		},
-	}
+	response := systemResetActionInfo(systemId)
 
 	etag := generateETag(response)
 	w.Header().Set("ETag", etag)
@@ -1053,72 +1788,106 @@ func handleComputerSystemResetActionInfo(w http.ResponseWriter, r *http.Request,
 		}
 	}
 
-	json.NewEncoder(w).Encode(response)
+	sendJSON(w, http.StatusOK, response)
 }
 
 // handleComputerSystemReset handles the ComputerSystem.Reset action
 func handleComputerSystemReset(w http.ResponseWriter, r *http.Request, systemId string) {
 	// Parse request body for ResetType parameter
 	var requestBody struct {
-		ResetType string `json:"ResetType"`
+		ResetType          string                    `json:"ResetType"`
+		OperationApplyTime string                    `json:"@Redfish.OperationApplyTime"`
+		MaintenanceWindow  *models.MaintenanceWindow `json:"@Redfish.MaintenanceWindow"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil && err.Error() != "EOF" {
-		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+	// The parameters are checked against the advertised ActionInfo
+	info := systemResetActionInfo(systemId)
+	if !decodeActionRequest(w, r, info, &requestBody) {
 		return
 	}
 
-	// Validate ResetType parameter
-	validResetTypes := map[string]bool{
-		"On":               true,
-		"ForceOff":         true,
-		"ForceRestart":     true,
-		"Nmi":              true,
-		"PushPowerButton":  true,
-		"GracefulRestart":  true,
-		"GracefulShutdown": true,
-		"ForceOn":          true,
+	schedule, err := parseOperationSchedule(requestBody.OperationApplyTime, requestBody.MaintenanceWindow)
+	if err != nil {
+		sendRedfishError(w, "ActionParameterValueNotInList", err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	resetType := requestBody.ResetType
 	if resetType == "" {
-		resetType = "On" // Default reset type
+		resetParameter, _ := info.Parameter("ResetType")
+		resetType = defaultResetType(resetParameter.AllowableValues, "On")
 	}
 
-	if !validResetTypes[resetType] {
-		sendRedfishError(w, "InvalidParameter", fmt.Sprintf("Invalid ResetType: %s", resetType), http.StatusBadRequest)
-		return
+	// An immediate reset must make sense in the system's current power state;
+	// a deferred one runs against whatever state the system is in by then
+	if system, ok := systemStore.Get(systemId); ok && schedule.applyTime == "Immediate" {
+		if err := checkResetPowerState(resetType, system.PowerState); err != nil {
+			sendRedfishError(w, "ActionParameterNotSupported", err.Error(), http.StatusConflict)
+			return
+		}
 	}
 
 	// Create a task for the reset operation
-	id := fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("reset-%s-%s-%s", systemId, resetType, time.Now().String()))))[:8]
-
-	task := models.NewTask(id, "POST", fmt.Sprintf("/redfish/v1/Systems/%s/Actions/ComputerSystem.Reset", systemId))
+	task := models.NewTask(newResourceID(), "POST", fmt.Sprintf("/redfish/v1/Systems/%s/Actions/ComputerSystem.Reset", systemId))
 	task.Payload.JsonBody = fmt.Sprintf(`{"ResetType": "%s"}`, resetType)
+	task.SetEstimatedDuration(systemResetDuration)
+	for _, step := range resetSteps(resetType) {
+		task.AddSubTask(step)
+	}
 
-	// Simulate asynchronous reset operation
-	go func() {
-		time.Sleep(3 * time.Second) // Simulate reset time
-		tasksMutex.Lock()
-		task.UpdateTaskState("Completed")
-		task.SetPercentComplete(100)
-		task.AddMessage(models.Message{
-			MessageID:  "Base.1.12.Success",
-			Message:    fmt.Sprintf("Computer system %s reset (%s) completed successfully", systemId, resetType),
-			Severity:   "OK",
-			Resolution: "No action required",
-		})
-		tasksMutex.Unlock()
-	}()
+	if err := addTask(task); err != nil {
+		sendAddTaskError(w, err)
+		return
+	}
+
+	// Simulate asynchronous reset operation, now or on the system's next reset
+	systemURI := fmt.Sprintf("/redfish/v1/Systems/%s", systemId)
+	ctx := backgroundContext(r)
+	scheduleOperation(task, systemURI, schedule, func() {
+		go func() {
+			version, err := startTask(task)
+			if err != nil {
+				return
+			}
+
+			if version, err = runTaskSteps(ctx, task, version, systemResetDuration); err != nil {
+				return
+			}
+
+			// A reset cancelled while it ran has no effect
+			_, err = updateTask(task, version, func(task *models.Task) {
+				task.UpdateTaskState("Completed")
+				task.SetPercentComplete(100)
+				task.AddMessage(models.Message{
+					MessageID:  "Base.1.0.Success",
+					Message:    fmt.Sprintf("Computer system %s reset (%s) completed successfully", systemId, resetType),
+					Severity:   "OK",
+					Resolution: "No action required",
+				})
+			})
+			if err != nil {
+				return
+			}
+
+			systemStore.Update(systemId, func(system *models.ComputerSystem) error {
+				system.PowerState = resetPowerState(resetType, system.PowerState)
+				return nil
+			})
 
-	tasksMutex.Lock()
-	tasks[id] = task
-	tasksMutex.Unlock()
+			// Settings staged via @Redfish.Settings take effect when the system
+			// comes back up; an NMI does not restart it
+			if resetType != "Nmi" {
+				biosSettings.Apply(biosURI(systemId))
+				releaseDeferredOperations(systemURI)
+			}
+		}()
+	})
 
 	// Return the task location
-	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Location", string(task.ODataID))
-	w.WriteHeader(http.StatusAccepted)
+	if schedule.applyTime == "Immediate" {
+		setRetryAfter(w, systemResetDuration)
+	}
 
 	response := map[string]interface{}{
 		"@odata.id":   task.ODataID,
@@ -1127,10 +1896,7 @@ func handleComputerSystemReset(w http.ResponseWriter, r *http.Request, systemId
 		"Name":        task.Name,
 	}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
-	}
+	sendJSON(w, http.StatusAccepted, response)
 }
 
 // chassisHandler handles the chassis collection
@@ -1151,11 +1917,16 @@ func handleGetChassis(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	chassis := models.NewChassisCollection()
+	chassis.Members = make([]models.Link, 0)
+	for _, id := range chassisStore.IDs() {
+		chassis.Members = append(chassis.Members, models.Link{ODataID: models.NewODataID("/redfish/v1/Chassis", id)})
+	}
+	chassis.MembersODataCount = len(chassis.Members)
 
 	// Parse query parameters
 	queryParams, err := parseQueryParameters(r.URL.Query())
 	if err != nil {
-		sendRedfishError(w, "QueryParameterError", err.Error(), http.StatusBadRequest)
+		sendQueryError(w, err)
 		return
 	}
 
@@ -1175,43 +1946,46 @@ func handleGetChassis(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(chassis)
+	sendJSON(w, http.StatusOK, chassis)
 }
 
 // handleGetChassisItem returns a specific chassis
 func handleGetChassisItem(w http.ResponseWriter, r *http.Request, id string) {
-	w.Header().Set("Content-Type", "application/json")
-
-	chassis := models.NewChassis(id)
-	etag := generateETag(chassis)
-	w.Header().Set("ETag", etag)
-
-	// Check conditional GET
-	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
-		normalizedETag := normalizeETag(etag)
-		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
-		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
-			w.WriteHeader(http.StatusNotModified)
-			return
+	found := sendCachedResource(w, r, models.NewODataID("/redfish/v1/Chassis", id), func() (interface{}, bool) {
+		chassis, ok := chassisStore.Get(id)
+		if !ok {
+			return nil, false
 		}
+		applyPowerThermalLinks(chassis)
+		return chassis, true
+	})
+	if !found {
+		sendResourceNotFound(w, r)
 	}
-
-	json.NewEncoder(w).Encode(chassis)
 }
 
 // handleCreateChassis creates a new chassis (not typically allowed)
 func handleCreateChassis(w http.ResponseWriter, r *http.Request) {
-	sendRedfishError(w, "MethodNotAllowed", "Chassis creation not supported", http.StatusMethodNotAllowed)
+	sendRedfishError(w, "OperationNotAllowed", "Chassis creation not supported", http.StatusMethodNotAllowed)
 }
 
 // chassisItemHandler handles individual chassis resources
 func chassisItemHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET, PATCH, PUT, DELETE")
+	w.Header().Set("Allow", "GET, PATCH, PUT")
 
-	// Extract chassis ID from URL path
-	path := r.URL.Path
-	id := path[len("/redfish/v1/Chassis/"):]
+	// Extract chassis ID and any sub-resource from URL path
+	segments := strings.Split(strings.Trim(r.URL.Path[len("/redfish/v1/Chassis/"):], "/"), "/")
+	id := segments[0]
+
+	if len(segments) > 1 {
+		if _, ok := chassisStore.Get(id); !ok {
+			sendResourceNotFound(w, r)
+			return
+		}
+		chassisPowerThermalHandler(w, r, id, segments[1:])
+		return
+	}
 
 	switch r.Method {
 	case "GET":
@@ -1220,8 +1994,6 @@ func chassisItemHandler(w http.ResponseWriter, r *http.Request) {
 		handleUpdateChassis(w, r, id)
 	case "PUT":
 		handleReplaceChassis(w, r, id)
-	case "DELETE":
-		handleDeleteChassis(w, r, id)
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -1229,17 +2001,12 @@ func chassisItemHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateChassis updates a chassis (PATCH)
 func handleUpdateChassis(w http.ResponseWriter, r *http.Request, id string) {
-	sendRedfishError(w, "MethodNotAllowed", "Chassis updates not supported", http.StatusMethodNotAllowed)
+	notImplemented(w, r)
 }
 
 // handleReplaceChassis replaces a chassis (PUT)
 func handleReplaceChassis(w http.ResponseWriter, r *http.Request, id string) {
-	sendRedfishError(w, "MethodNotAllowed", "Chassis replacement not supported", http.StatusMethodNotAllowed)
-}
-
-// handleDeleteChassis deletes a chassis
-func handleDeleteChassis(w http.ResponseWriter, r *http.Request, id string) {
-	sendRedfishError(w, "MethodNotAllowed", "Chassis deletion not supported", http.StatusMethodNotAllowed)
+	notImplemented(w, r)
 }
 
 // managersHandler handles the managers collection
@@ -1260,11 +2027,16 @@ func handleGetManagers(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	managers := models.NewManagerCollection()
+	managers.Members = make([]models.Link, 0)
+	for _, id := range managerStore.IDs() {
+		managers.Members = append(managers.Members, models.Link{ODataID: models.NewODataID("/redfish/v1/Managers", id)})
+	}
+	managers.MembersODataCount = len(managers.Members)
 
 	// Parse query parameters
 	queryParams, err := parseQueryParameters(r.URL.Query())
 	if err != nil {
-		sendRedfishError(w, "QueryParameterError", err.Error(), http.StatusBadRequest)
+		sendQueryError(w, err)
 		return
 	}
 
@@ -1284,39 +2056,33 @@ func handleGetManagers(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	json.NewEncoder(w).Encode(managers)
+	sendJSON(w, http.StatusOK, managers)
 }
 
 // handleGetManager returns a specific manager
 func handleGetManager(w http.ResponseWriter, r *http.Request, id string) {
-	w.Header().Set("Content-Type", "application/json")
-
-	manager := models.NewManager(id)
-	etag := generateETag(manager)
-	w.Header().Set("ETag", etag)
-
-	// Check conditional GET
-	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
-		normalizedETag := normalizeETag(etag)
-		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
-		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
-			w.WriteHeader(http.StatusNotModified)
-			return
+	found := sendCachedResource(w, r, models.NewODataID("/redfish/v1/Managers", id), func() (interface{}, bool) {
+		manager, ok := managerStore.Get(id)
+		if !ok {
+			return nil, false
 		}
+		manager.Actions.Oem = oemActionTargets("Manager", manager.ODataID)
+		return manager, true
+	})
+	if !found {
+		sendResourceNotFound(w, r)
 	}
-
-	json.NewEncoder(w).Encode(manager)
 }
 
 // handleCreateManager creates a new manager (not typically allowed)
 func handleCreateManager(w http.ResponseWriter, r *http.Request) {
-	sendRedfishError(w, "MethodNotAllowed", "Manager creation not supported", http.StatusMethodNotAllowed)
+	sendRedfishError(w, "OperationNotAllowed", "Manager creation not supported", http.StatusMethodNotAllowed)
 }
 
 // managerHandler handles individual manager resources and actions
 func managerHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET, PATCH, PUT, DELETE")
+	w.Header().Set("Allow", "GET, PATCH, PUT")
 
 	path := r.URL.Path
 
@@ -1326,8 +2092,25 @@ func managerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Extract manager ID from URL path
-	id := path[len("/redfish/v1/Managers/"):]
+	// Extract manager ID and any sub-resource from URL path
+	segments := strings.Split(strings.Trim(path[len("/redfish/v1/Managers/"):], "/"), "/")
+	id := segments[0]
+
+	if len(segments) > 1 {
+		if _, ok := managerStore.Get(id); !ok {
+			sendResourceNotFound(w, r)
+			return
+		}
+		switch segments[1] {
+		case "SerialInterfaces":
+			serialInterfacesHandler(w, r, id, segments[2:])
+		case "EthernetInterfaces":
+			ethernetInterfacesHandler(w, r, id, segments[2:])
+		default:
+			sendResourceNotFound(w, r)
+		}
+		return
+	}
 
 	switch r.Method {
 	case "GET":
@@ -1336,8 +2119,6 @@ func managerHandler(w http.ResponseWriter, r *http.Request) {
 		handleUpdateManager(w, r, id)
 	case "PUT":
 		handleReplaceManager(w, r, id)
-	case "DELETE":
-		handleDeleteManager(w, r, id)
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -1345,17 +2126,12 @@ func managerHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateManager updates a manager (PATCH)
 func handleUpdateManager(w http.ResponseWriter, r *http.Request, id string) {
-	sendRedfishError(w, "MethodNotAllowed", "Manager updates not supported", http.StatusMethodNotAllowed)
+	notImplemented(w, r)
 }
 
 // handleReplaceManager replaces a manager (PUT)
 func handleReplaceManager(w http.ResponseWriter, r *http.Request, id string) {
-	sendRedfishError(w, "MethodNotAllowed", "Manager replacement not supported", http.StatusMethodNotAllowed)
-}
-
-// handleDeleteManager deletes a manager
-func handleDeleteManager(w http.ResponseWriter, r *http.Request, id string) {
-	sendRedfishError(w, "MethodNotAllowed", "Manager deletion not supported", http.StatusMethodNotAllowed)
+	notImplemented(w, r)
 }
 
 // handleManagerAction handles Manager actions
@@ -1363,12 +2139,20 @@ func handleManagerAction(w http.ResponseWriter, r *http.Request, path string) {
 	// Extract action from path: /redfish/v1/Managers/{id}/Actions/{ActionName}
 	parts := strings.Split(path, "/")
 	if len(parts) < 7 || parts[5] != "Actions" {
-		sendRedfishError(w, "InvalidAction", "Invalid action URI format", http.StatusBadRequest)
+		sendRedfishError(w, "ActionNotSupported", "Invalid action URI format", http.StatusBadRequest)
 		return
 	}
 
 	actionName := parts[6]
 	managerId := parts[4]
+	if _, ok := managerStore.Get(managerId); !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+	if actionName == "Oem" {
+		handleOemResourceAction(w, r, "Manager", managerId, strings.Join(parts[7:], "/"))
+		return
+	}
 
 	switch r.Method {
 	case "GET":
@@ -1381,7 +2165,10 @@ func handleManagerAction(w http.ResponseWriter, r *http.Request, path string) {
 	case "POST":
 		switch actionName {
 		case "Manager.Reset":
-			handleManagerReset(w, r, managerId)
+			// The reset runs as a task
+			if requireServiceEnabled(w, "/redfish/v1/TaskService") {
+				handleManagerReset(w, r, managerId)
+			}
 		default:
 			sendRedfishError(w, "ActionNotSupported", fmt.Sprintf("Action %s not supported for Manager", actionName), http.StatusBadRequest)
 		}
@@ -1394,21 +2181,7 @@ func handleManagerAction(w http.ResponseWriter, r *http.Request, path string) {
 func handleManagerResetActionInfo(w http.ResponseWriter, r *http.Request, managerId string) {
 	w.Header().Set("Content-Type", "application/json")
 
-	response := map[string]interface{}{
-		"@odata.context": "/redfish/v1/$metadata#ActionInfo.ActionInfo",
-		"@odata.id":      fmt.Sprintf("/redfish/v1/Managers/%s/Actions/Manager.Reset", managerId),
-		"@odata.type":    "#ActionInfo.v1_1_2.ActionInfo",
-		"Id":             "Manager.Reset",
-		"Name":           "Manager Reset",
-		"Parameters": []map[string]interface{}{
-			{
-				"Name":            "ResetType",
-				"Required":        false,
-				"DataType":        "String",
-				"AllowableValues": []string{"ForceRestart", "GracefulRestart"},
-			},
-		},
-	}
+	response := managerResetActionInfo(managerId)
 
 	etag := generateETag(response)
 	w.Header().Set("ETag", etag)
@@ -1423,66 +2196,85 @@ func handleManagerResetActionInfo(w http.ResponseWriter, r *http.Request, manage
 		}
 	}
 
-	json.NewEncoder(w).Encode(response)
+	sendJSON(w, http.StatusOK, response)
 }
 
 // handleManagerReset handles the Manager.Reset action
 func handleManagerReset(w http.ResponseWriter, r *http.Request, managerId string) {
 	// Parse request body for ResetType parameter
 	var requestBody struct {
-		ResetType string `json:"ResetType"`
+		ResetType          string                    `json:"ResetType"`
+		OperationApplyTime string                    `json:"@Redfish.OperationApplyTime"`
+		MaintenanceWindow  *models.MaintenanceWindow `json:"@Redfish.MaintenanceWindow"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil && err.Error() != "EOF" {
-		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+	// The parameters are checked against the advertised ActionInfo
+	info := managerResetActionInfo(managerId)
+	if !decodeActionRequest(w, r, info, &requestBody) {
 		return
 	}
 
-	// Validate ResetType parameter
-	validResetTypes := map[string]bool{
-		"ForceRestart":    true,
-		"GracefulRestart": true,
+	schedule, err := parseOperationSchedule(requestBody.OperationApplyTime, requestBody.MaintenanceWindow)
+	if err != nil {
+		sendRedfishError(w, "ActionParameterValueNotInList", err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	resetType := requestBody.ResetType
 	if resetType == "" {
-		resetType = "GracefulRestart" // Default reset type for managers
+		resetParameter, _ := info.Parameter("ResetType")
+		resetType = defaultResetType(resetParameter.AllowableValues, "GracefulRestart")
 	}
 
-	if !validResetTypes[resetType] {
-		sendRedfishError(w, "InvalidParameter", fmt.Sprintf("Invalid ResetType: %s", resetType), http.StatusBadRequest)
+	// Create a task for the manager reset operation
+	task := models.NewTask(newResourceID(), "POST", fmt.Sprintf("/redfish/v1/Managers/%s/Actions/Manager.Reset", managerId))
+	task.Payload.JsonBody = fmt.Sprintf(`{"ResetType": "%s"}`, resetType)
+	task.SetEstimatedDuration(managerResetDuration)
+
+	if err := addTask(task); err != nil {
+		sendAddTaskError(w, err)
 		return
 	}
 
-	// Create a task for the manager reset operation
-	id := fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("mgr-reset-%s-%s-%s", managerId, resetType, time.Now().String()))))[:8]
+	// Simulate asynchronous manager reset operation, now or on the manager's next reset
+	managerURI := fmt.Sprintf("/redfish/v1/Managers/%s", managerId)
+	ctx := backgroundContext(r)
+	scheduleOperation(task, managerURI, schedule, func() {
+		go func() {
+			version, err := startTask(task)
+			if err != nil {
+				return
+			}
 
-	task := models.NewTask(id, "POST", fmt.Sprintf("/redfish/v1/Managers/%s/Actions/Manager.Reset", managerId))
-	task.Payload.JsonBody = fmt.Sprintf(`{"ResetType": "%s"}`, resetType)
+			if err := simulateWork(ctx, managerResetDuration); err != nil {
+				abandonTask(task, version, err)
+				return
+			}
 
-	// Simulate asynchronous manager reset operation
-	go func() {
-		time.Sleep(5 * time.Second) // Simulate longer reset time for manager
-		tasksMutex.Lock()
-		task.UpdateTaskState("Completed")
-		task.SetPercentComplete(100)
-		task.AddMessage(models.Message{
-			MessageID:  "Base.1.12.Success",
-			Message:    fmt.Sprintf("Manager %s reset (%s) completed successfully", managerId, resetType),
-			Severity:   "OK",
-			Resolution: "No action required",
-		})
-		tasksMutex.Unlock()
-	}()
+			// A reset cancelled while it ran has no effect
+			_, err = updateTask(task, version, func(task *models.Task) {
+				task.UpdateTaskState("Completed")
+				task.SetPercentComplete(100)
+				task.AddMessage(models.Message{
+					MessageID:  "Base.1.0.Success",
+					Message:    fmt.Sprintf("Manager %s reset (%s) completed successfully", managerId, resetType),
+					Severity:   "OK",
+					Resolution: "No action required",
+				})
+			})
+			if err != nil {
+				return
+			}
 
-	tasksMutex.Lock()
-	tasks[id] = task
-	tasksMutex.Unlock()
+			releaseDeferredOperations(managerURI)
+		}()
+	})
 
 	// Return the task location
-	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Location", string(task.ODataID))
-	w.WriteHeader(http.StatusAccepted)
+	if schedule.applyTime == "Immediate" {
+		setRetryAfter(w, managerResetDuration)
+	}
 
 	response := map[string]interface{}{
 		"@odata.id":   task.ODataID,
@@ -1491,10 +2283,7 @@ func handleManagerReset(w http.ResponseWriter, r *http.Request, managerId string
 		"Name":        task.Name,
 	}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
-	}
+	sendJSON(w, http.StatusAccepted, response)
 }
 
 // setRedfishHeaders sets common Redfish headers
@@ -1507,7 +2296,37 @@ func setRedfishHeaders(w http.ResponseWriter) {
 
 // methodNotAllowed sends a 405 Method Not Allowed response
 func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
-	sendRedfishError(w, "MethodNotAllowed", fmt.Sprintf("HTTP method %s not allowed for this resource", r.Method), http.StatusMethodNotAllowed)
+	sendRedfishError(w, "OperationNotAllowed", fmt.Sprintf("HTTP method %s not allowed for this resource", r.Method), http.StatusMethodNotAllowed)
+}
+
+// notImplemented sends a 501 Not Implemented response for a method the
+// resource supports in Redfish but this service does not implement yet. A
+// method the resource never supports gets methodNotAllowed instead.
+func notImplemented(w http.ResponseWriter, r *http.Request) {
+	sendRedfishError(w, "OperationNotImplemented", fmt.Sprintf("HTTP method %s is not implemented for this resource", r.Method), http.StatusNotImplemented)
+}
+
+// requirePrivilege checks that the authenticated user holds the privilege,
+// sending a 403 error and returning false otherwise
+func requirePrivilege(w http.ResponseWriter, r *http.Request, privilege string) bool {
+	if !hasPrivilege(r, privilege) {
+		sendRedfishError(w, "InsufficientPrivilege", fmt.Sprintf("The %s privilege is required", privilege), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// hasPrivilege reports whether the authenticated user holds the privilege
+func hasPrivilege(r *http.Request, privilege string) bool {
+	userCtx, ok := auth.GetUserContext(r.Context())
+	if !ok {
+		return false
+	}
+	if userCtx.Role != "" {
+		privileges, _ := auth.RolePrivileges(userCtx.Role)
+		return slices.Contains(privileges, privilege)
+	}
+	return auth.HasPrivilege(auth.GetAuthenticator(), userCtx.Username, privilege)
 }
 
 // generateETag generates a simple ETag for a resource
@@ -1519,7 +2338,59 @@ func generateETag(data interface{}) string {
 	return fmt.Sprintf(`"%s"`, hash[:8])
 }
 
-// normalizeETag normalizes an ETag for comparison (removes quotes if present)
+// resourceETag computes the ETag of a resource and, when the resource carries
+// an @odata.etag annotation, records it there as well
+func resourceETag(resource interface{}) string {
+	etag := generateETag(resource)
+	if annotated, ok := resource.(interface{ SetODataETag(string) }); ok {
+		annotated.SetODataETag(etag)
+	}
+	return etag
+}
+
+// sendUpdatedResource writes a resource after a successful PATCH or PUT with
+// a freshly computed ETag, so clients can chain conditional requests
+func sendUpdatedResource(w http.ResponseWriter, resource interface{}) {
+	w.Header().Set("ETag", resourceETag(resource))
+	sendJSON(w, http.StatusOK, resource)
+}
+
+// sendJSON writes v as a JSON response with the given status. The body is
+// encoded before anything is written, so an encoding failure can still be
+// reported as a Redfish InternalError; a failure while writing the body comes
+// too late for that and is only logged.
+func sendJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(v); err != nil {
+		log.Printf("Failed to encode %T response: %v", v, err)
+		w.Header().Del("ETag")
+		w.Header().Del("Location")
+		sendRedfishError(w, "InternalError", "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		log.Printf("Failed to write %T response: %v", v, err)
+	}
+}
+
+// writeFixedBody sends a body that is fully known before it is written,
+// with its Content-Length. A HEAD request gets the same headers, so the
+// length matches what GET would return, but no body.
+func writeFixedBody(w http.ResponseWriter, r *http.Request, body []byte) {
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == "HEAD" {
+		return
+	}
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// normalizeETag normalizes an ETag for comparison (removes quotes if present)
 func normalizeETag(etag string) string {
 	if len(etag) >= 2 && etag[0] == '"' && etag[len(etag)-1] == '"' {
 		return etag[1 : len(etag)-1]
@@ -1527,8 +2398,60 @@ func normalizeETag(etag string) string {
 	return etag
 }
 
-// sendRedfishError sends a Redfish-compliant error response
+// ifMatchSatisfied reports whether a request's If-Match header, if any,
+// matches the current ETag of the resource it targets. "*" matches any
+// existing resource.
+func ifMatchSatisfied(r *http.Request, etag string) bool {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return true
+	}
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || normalizeETag(candidate) == normalizeETag(etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendNotModified answers a conditional GET with 304 Not Modified when its
+// If-None-Match header matches the resource's current ETag, or is "*". It
+// reports whether it did, in which case nothing else must be written.
+func sendNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	if ifNoneMatch == "" || (ifNoneMatch != "*" && normalizeETag(ifNoneMatch) != normalizeETag(etag)) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// sendPreconditionFailed rejects a conditional request whose If-Match did
+// not match the resource's current ETag
+func sendPreconditionFailed(w http.ResponseWriter, uri string) {
+	sendRedfishError(w, "PreconditionFailed", fmt.Sprintf("If-Match does not match the current ETag of %s", uri), http.StatusPreconditionFailed)
+}
+
+// baseRegistry is the Base message registry served under /redfish/v1/Registries
+var baseRegistry = models.NewMessageRegistry("en")
+
+// sendRedfishError sends a Redfish-compliant error response. The code is a
+// message key in the Base registry; severity and resolution come from there.
 func sendRedfishError(w http.ResponseWriter, code, message string, statusCode int) {
+	writeRedfishError(w, registryMessage(code, message), statusCode)
+}
+
+// sendResourceNotFound sends a 404 with the Base ResourceNotFound message,
+// its text resolved from the registry in the request's language with the
+// request URI as the argument
+func sendResourceNotFound(w http.ResponseWriter, r *http.Request) {
+	writeRedfishError(w, localizedMessage(w, r, "ResourceNotFound", r.URL.Path), http.StatusNotFound)
+}
+
+// writeRedfishError sends an error response whose code and message are those
+// of its single extended-info message
+func writeRedfishError(w http.ResponseWriter, details models.Message, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
@@ -1538,30 +2461,92 @@ func sendRedfishError(w http.ResponseWriter, code, message string, statusCode in
 			Message string           `json:"message"`
 			Details []models.Message `json:"@Message.ExtendedInfo,omitempty"`
 		}{
-			Code:    code,
-			Message: message,
-			Details: []models.Message{
-				{
-					MessageID:  code,
-					Message:    message,
-					Severity:   "Critical",
-					Resolution: "Check the request method and try again",
-				},
-			},
+			Code:    details.MessageID,
+			Message: details.Message,
+			Details: []models.Message{details},
 		},
 	}
 
 	json.NewEncoder(w).Encode(errorResponse)
 }
 
+// sendRedfishMessage sends a non-error response whose body is a single
+// @Message.ExtendedInfo entry for a Base registry message key
+func sendRedfishMessage(w http.ResponseWriter, code, message string, statusCode int) {
+	sendJSON(w, statusCode, map[string]interface{}{
+		"@Message.ExtendedInfo": []models.Message{registryMessage(code, message)},
+	})
+}
+
+// registryMessage builds an extended-info message for a Base registry key,
+// taking severity and resolution from the registry
+func registryMessage(code, message string) models.Message {
+	severity := "Critical"
+	resolution := "Check the request and try again"
+	if entry, ok := baseRegistry.Messages[code]; ok {
+		severity = entry.MessageSeverity
+		resolution = entry.Resolution
+	}
+
+	return models.Message{
+		MessageID:  baseRegistry.MessageID(code),
+		Message:    message,
+		Severity:   severity,
+		Resolution: resolution,
+	}
+}
+
+// queryError is a malformed or unsupported OData query, naming the Base
+// registry message that describes it
+type queryError struct {
+	key     string
+	message string
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+// queryValueFormatError reports a query parameter value of the wrong form
+func queryValueFormatError(format string, args ...interface{}) *queryError {
+	return &queryError{key: "QueryParameterValueFormatError", message: fmt.Sprintf(format, args...)}
+}
+
+// queryOutOfRangeError reports a well-formed query parameter value outside
+// the range the service accepts
+func queryOutOfRangeError(format string, args ...interface{}) *queryError {
+	return &queryError{key: "QueryParameterOutOfRange", message: fmt.Sprintf(format, args...)}
+}
+
+// sendQueryError sends a 400 for an error from parseQueryParameters, using
+// the registry message the error names
+func sendQueryError(w http.ResponseWriter, err error) {
+	key := "QueryParameterValueFormatError"
+	var qe *queryError
+	if errors.As(err, &qe) {
+		key = qe.key
+	}
+	sendRedfishError(w, key, err.Error(), http.StatusBadRequest)
+}
+
 // QueryParameters represents parsed OData query parameters
 type QueryParameters struct {
-	Top     int      `json:"top,omitempty"`
-	Skip    int      `json:"skip,omitempty"`
-	Select  []string `json:"select,omitempty"`
-	Expand  []string `json:"expand,omitempty"`
-	Filter  string   `json:"filter,omitempty"`
-	OrderBy string   `json:"orderby,omitempty"`
+	Top    *int     `json:"top,omitempty"` // nil when $top is absent; $top=0 asks for an empty page
+	Skip   int      `json:"skip,omitempty"`
+	Select []string `json:"select,omitempty"`
+	Expand []string `json:"expand,omitempty"`
+	// ExpandLevels is the $levels depth of $expand, 1 unless requested
+	ExpandLevels int    `json:"levels,omitempty"`
+	Filter       string `json:"filter,omitempty"`
+	OrderBy      string `json:"orderby,omitempty"`
+	// SkipToken is the @odata.id of the last member of the previous page,
+	// decoded from $skiptoken; it cannot be combined with $skip
+	SkipToken models.ODataID `json:"skiptoken,omitempty"`
+	// Only and Excerpt record the Redfish only and excerpt parameters. They
+	// are recognized so that conflicting combinations can be rejected; like
+	// other parameters without a $ prefix, the service may otherwise ignore them.
+	Only    bool `json:"only,omitempty"`
+	Excerpt bool `json:"excerpt,omitempty"`
 }
 
 // parseQueryParameters parses OData query parameters from the URL
@@ -1571,21 +2556,40 @@ func parseQueryParameters(query url.Values) (*QueryParameters, error) {
 	// Parse $top
 	if topStr := query.Get("$top"); topStr != "" {
 		top, err := strconv.Atoi(topStr)
-		if err != nil || top < 0 {
-			return nil, fmt.Errorf("invalid $top parameter: %s", topStr)
+		if err != nil {
+			return nil, queryValueFormatError("invalid $top parameter: %s", topStr)
+		}
+		if top < 0 {
+			return nil, queryOutOfRangeError("$top must not be negative: %s", topStr)
 		}
-		params.Top = top
+		params.Top = &top
 	}
 
 	// Parse $skip
 	if skipStr := query.Get("$skip"); skipStr != "" {
 		skip, err := strconv.Atoi(skipStr)
-		if err != nil || skip < 0 {
-			return nil, fmt.Errorf("invalid $skip parameter: %s", skipStr)
+		if err != nil {
+			return nil, queryValueFormatError("invalid $skip parameter: %s", skipStr)
+		}
+		if skip < 0 {
+			return nil, queryOutOfRangeError("$skip must not be negative: %s", skipStr)
 		}
 		params.Skip = skip
 	}
 
+	// Parse $skiptoken; it resumes after a member, so an offset as well
+	// would be ambiguous
+	if token := query.Get("$skiptoken"); token != "" {
+		if query.Get("$skip") != "" {
+			return nil, &queryError{key: "QueryCombinationInvalid", message: "$skip and $skiptoken cannot be used together"}
+		}
+		last, err := decodeSkipToken(token)
+		if err != nil {
+			return nil, err
+		}
+		params.SkipToken = last
+	}
+
 	// Parse $select
 	if selectStr := query.Get("$select"); selectStr != "" {
 		params.Select = strings.Split(strings.ReplaceAll(selectStr, " ", ""), ",")
@@ -1593,7 +2597,12 @@ func parseQueryParameters(query url.Values) (*QueryParameters, error) {
 
 	// Parse $expand
 	if expandStr := query.Get("$expand"); expandStr != "" {
-		params.Expand = strings.Split(strings.ReplaceAll(expandStr, " ", ""), ",")
+		expand, levels, err := parseExpand(expandStr)
+		if err != nil {
+			return nil, err
+		}
+		params.Expand = expand
+		params.ExpandLevels = levels
 	}
 
 	// Parse $filter
@@ -1602,9 +2611,32 @@ func parseQueryParameters(query url.Values) (*QueryParameters, error) {
 	// Parse $orderby
 	params.OrderBy = query.Get("$orderby")
 
+	params.Only = query.Has("only")
+	params.Excerpt = query.Has("excerpt")
+	if err := checkQueryCombination(query, params); err != nil {
+		return nil, err
+	}
+
 	return params, nil
 }
 
+// checkQueryCombination rejects query parameters that Redfish does not allow
+// together: only, which returns a collection's sole member, with paging, and
+// excerpt with $expand
+func checkQueryCombination(query url.Values, params *QueryParameters) error {
+	if params.Only {
+		for _, paging := range []string{"$top", "$skip", "$skiptoken"} {
+			if query.Has(paging) {
+				return &queryError{key: "QueryCombinationInvalid", message: fmt.Sprintf("only cannot be used together with %s", paging)}
+			}
+		}
+	}
+	if params.Excerpt && len(params.Expand) > 0 {
+		return &queryError{key: "QueryCombinationInvalid", message: "excerpt cannot be used together with $expand"}
+	}
+	return nil
+}
+
 // applyQueryParameters applies query parameters to a ComputerSystemCollection
 func applyQueryParametersToSystems(collection *models.ComputerSystemCollection, params *QueryParameters) *models.ComputerSystemCollection {
 	if params == nil {
@@ -1619,19 +2651,7 @@ func applyQueryParametersToSystems(collection *models.ComputerSystemCollection,
 	}
 
 	// Apply $skip and $top for pagination
-	totalMembers := len(result.Members)
-	start := params.Skip
-	if start > totalMembers {
-		start = totalMembers
-	}
-
-	end := totalMembers
-	if params.Top > 0 && start+params.Top < totalMembers {
-		end = start + params.Top
-	}
-
-	result.Members = result.Members[start:end]
-	result.MembersODataCount = len(result.Members)
+	paginateCollection(&result.Collection, params)
 
 	return &result
 }
@@ -1665,19 +2685,7 @@ func applyQueryParametersToChassis(collection *models.ChassisCollection, params
 	result := *collection // Create a copy
 
 	// Apply $skip and $top for pagination
-	totalMembers := len(result.Members)
-	start := params.Skip
-	if start > totalMembers {
-		start = totalMembers
-	}
-
-	end := totalMembers
-	if params.Top > 0 && start+params.Top < totalMembers {
-		end = start + params.Top
-	}
-
-	result.Members = result.Members[start:end]
-	result.MembersODataCount = len(result.Members)
+	paginateCollection(&result.Collection, params)
 
 	return &result
 }
@@ -1691,28 +2699,22 @@ func applyQueryParametersToManagers(collection *models.ManagerCollection, params
 	result := *collection // Create a copy
 
 	// Apply $skip and $top for pagination
-	totalMembers := len(result.Members)
-	start := params.Skip
-	if start > totalMembers {
-		start = totalMembers
-	}
-
-	end := totalMembers
-	if params.Top > 0 && start+params.Top < totalMembers {
-		end = start + params.Top
-	}
-
-	result.Members = result.Members[start:end]
-	result.MembersODataCount = len(result.Members)
+	paginateCollection(&result.Collection, params)
 
 	return &result
 }
 
-// applySelectToSystem applies $select filtering to a ComputerSystem
-// For now, this validates the select parameters but returns the full object
-// TODO: Implement actual property filtering
+// applySelectToSystem records the $select projection of a ComputerSystem in
+// its @odata.context; selectProperties then trims the representation to match
 func applySelectToSystem(system *models.ComputerSystem, selectProps []string) *models.ComputerSystem {
-	// Validate that requested properties exist on ComputerSystem
+	result := *system
+	result.ODataContext = projectedContext(system.ODataContext, validSystemProperties(selectProps))
+	return &result
+}
+
+// validSystemProperties returns the $select properties that exist on
+// ComputerSystem
+func validSystemProperties(selectProps []string) []string {
 	validProps := map[string]bool{
 		"@odata.context":     true,
 		"@odata.id":          true,
@@ -1729,6 +2731,7 @@ func applySelectToSystem(system *models.ComputerSystem, selectProps []string) *m
 		"PartNumber":         true,
 		"UUID":               true,
 		"HostName":           true,
+		"IndicatorLED":       true,
 		"Status":             true,
 		"PowerState":         true,
 		"Boot":               true,
@@ -1742,21 +2745,33 @@ func applySelectToSystem(system *models.ComputerSystem, selectProps []string) *m
 		"NetworkInterfaces":  true,
 		"EthernetInterfaces": true,
 		"LogServices":        true,
+		"Bios":               true,
 		"Links":              true,
 		"Actions":            true,
 		"Oem":                true,
 	}
 
+	var selected []string
 	for _, prop := range selectProps {
 		if !validProps[prop] {
 			// For now, ignore invalid properties rather than erroring
 			// In a full implementation, this might return an error
+			continue
 		}
+		selected = append(selected, prop)
 	}
+	return selected
+}
 
-	// Return the full system for now
-	// TODO: Implement actual selective property marshaling
-	return system
+// projectedContext appends the properties selected with $select to an
+// @odata.context URL, as in "$metadata#ComputerSystem.ComputerSystem(Id,PowerState)".
+// Annotations such as @odata.id are always returned and are not listed.
+func projectedContext(context models.ODataContext, properties []string) models.ODataContext {
+	listed := selectedProperties(properties)
+	if len(listed) == 0 {
+		return context
+	}
+	return context + models.ODataContext("("+strings.Join(listed, ",")+")")
 }
 
 // applyExpandToSystem applies $expand to include related resources inline
@@ -1789,11 +2804,13 @@ func applyExpandToSystem(system *models.ComputerSystem, expandProps []string) *m
 // eventServiceHandler handles EventService requests
 func eventServiceHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, PATCH")
 
 	switch r.Method {
 	case "GET":
 		handleGetEventService(w, r)
+	case "PATCH":
+		handlePatchServiceEnabled(w, r, "/redfish/v1/EventService", func() interface{} { return currentEventService() })
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -1801,15 +2818,19 @@ func eventServiceHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleGetEventService returns the EventService resource
 func handleGetEventService(w http.ResponseWriter, r *http.Request) {
-	eventService := models.NewEventService()
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	sendJSON(w, http.StatusOK, currentEventService())
+}
 
-	if err := json.NewEncoder(w).Encode(eventService); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
+// currentEventService builds the EventService with the configured features
+// and whether it is enabled
+func currentEventService() *models.EventService {
+	eventService := models.NewEventService()
+	if !enabledFeatures.Load().sse {
+		eventService.ServerSentEventUri = ""
 	}
+	eventService.ServiceEnabled = serviceEnabled("/redfish/v1/EventService")
+	eventService.Status = serviceStatus("/redfish/v1/EventService")
+	return eventService
 }
 
 // eventSubscriptionsHandler handles EventService Subscriptions collection requests
@@ -1821,7 +2842,9 @@ func eventSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
 	case "GET":
 		handleGetEventSubscriptions(w, r)
 	case "POST":
-		handlePostEventSubscription(w, r)
+		if requireServiceEnabled(w, "/redfish/v1/EventService") {
+			handlePostEventSubscription(w, r)
+		}
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -1829,23 +2852,30 @@ func eventSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleGetEventSubscriptions returns the EventSubscriptions collection
 func handleGetEventSubscriptions(w http.ResponseWriter, r *http.Request) {
-	// For now, return empty collection
+	queryParams, err := parseQueryParameters(r.URL.Query())
+	if err != nil {
+		sendQueryError(w, err)
+		return
+	}
+
+	subscriptionsMutex.RLock()
+	members := make([]models.Link, 0, len(subscriptions))
+	for _, subscription := range sortedSubscriptionsLocked() {
+		members = append(members, models.Link{ODataID: subscription.ODataID})
+	}
+	subscriptionsMutex.RUnlock()
+
 	collection := models.Collection{
 		ODataContext:      "/redfish/v1/$metadata#EventDestinationCollection.EventDestinationCollection",
 		ODataID:           "/redfish/v1/EventService/Subscriptions",
 		ODataType:         "#EventDestinationCollection.EventDestinationCollection",
 		Name:              "Event Subscriptions Collection",
-		Members:           []models.Link{},
-		MembersODataCount: 0,
+		Members:           members,
+		MembersODataCount: len(members),
 	}
+	paginateCollection(&collection, queryParams)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(collection); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
-	}
+	sendJSON(w, http.StatusOK, collection)
 }
 
 // handlePostEventSubscription creates a new event subscription
@@ -1861,15 +2891,43 @@ func handlePostEventSubscription(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Destination is required", http.StatusBadRequest)
 		return
 	}
+	if err := checkDestination(subscription.Destination); err != nil {
+		sendRedfishError(w, "PropertyValueNotInList", err.Error(), http.StatusBadRequest)
+		return
+	}
 	if subscription.Protocol == "" {
 		subscription.Protocol = "Redfish" // Default
 	}
 
-	// Generate ID (in a real implementation, this would be stored)
-	id := fmt.Sprintf("%x", md5.Sum([]byte(subscription.Destination+time.Now().String())))[:8]
+	// Only accept filters the EventService advertises, so a subscriber never
+	// waits on events the service does not emit
+	eventService := models.NewEventService()
+	for _, filter := range []struct {
+		name            string
+		values, allowed []string
+	}{
+		{"ResourceTypes", subscription.ResourceTypes, eventService.ResourceTypes},
+		{"RegistryPrefixes", subscription.RegistryPrefixes, eventService.RegistryPrefixes},
+	} {
+		for _, value := range filter.values {
+			if err := models.ValidateEnum(filter.name, value, filter.allowed); err != nil {
+				sendRedfishError(w, "PropertyValueNotInList", err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	if err := validateHttpHeaders(subscription.HttpHeaders); err != nil {
+		sendRedfishError(w, "PropertyValueFormatError", err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateEventBatching(subscription.Batching()); err != nil {
+		sendRedfishError(w, "PropertyValueOutOfRange", err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Create the subscription
-	newSubscription := models.NewEventSubscription(id, subscription.Destination, subscription.Protocol)
+	newSubscription := models.NewEventSubscription(newResourceID(), subscription.Destination, subscription.Protocol)
 	if subscription.Context != "" {
 		newSubscription.Context = subscription.Context
 	}
@@ -1884,15 +2942,19 @@ func handlePostEventSubscription(w http.ResponseWriter, r *http.Request) {
 	}
 	newSubscription.IncludeOriginOfCondition = subscription.IncludeOriginOfCondition
 	newSubscription.SubordinateResources = subscription.SubordinateResources
+	if subscription.VerifyCertificate != nil {
+		newSubscription.VerifyCertificate = subscription.VerifyCertificate
+	}
+	newSubscription.HttpHeaders = subscription.HttpHeaders
+	newSubscription.Oem = subscription.Oem
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Location", string(newSubscription.ODataID))
-	w.WriteHeader(http.StatusCreated)
-
-	if err := json.NewEncoder(w).Encode(newSubscription); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	if err := addSubscription(newSubscription); err != nil {
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	w.Header().Set("Location", string(newSubscription.ODataID))
+	sendJSON(w, http.StatusCreated, subscriptionResponse(newSubscription))
 }
 
 // eventSubscriptionHandler handles individual EventSubscription requests
@@ -1910,6 +2972,15 @@ func eventSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(parts) > 1 {
+		if parts[1] != "Certificates" {
+			sendResourceNotFound(w, r)
+			return
+		}
+		subscriptionCertificatesHandler(w, r, id, parts[2:])
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		handleGetEventSubscription(w, r, id)
@@ -1922,52 +2993,50 @@ func eventSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleGetEventSubscription returns a specific event subscription
 func handleGetEventSubscription(w http.ResponseWriter, r *http.Request, id string) {
-	// For now, return 404 as we don't persist subscriptions
-	http.Error(w, "Subscription not found", http.StatusNotFound)
-}
+	stored, ok := getSubscription(id)
+	if !ok {
+		sendResourceNotFound(w, r)
+		return
+	}
+	subscription := subscriptionResponse(stored)
 
-// handleDeleteEventSubscription deletes an event subscription
-func handleDeleteEventSubscription(w http.ResponseWriter, r *http.Request, id string) {
-	// For now, return 404 as we don't persist subscriptions
-	http.Error(w, "Subscription not found", http.StatusNotFound)
-}
+	w.Header().Set("Content-Type", "application/json")
 
-// eventSSEHandler handles Server-Sent Events requests
-func eventSSEHandler(w http.ResponseWriter, r *http.Request) {
-	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	etag := generateETag(subscription)
+	w.Header().Set("ETag", etag)
 
-	switch r.Method {
-	case "GET":
-		handleGetEventSSE(w, r)
-	default:
-		methodNotAllowed(w, r)
+	// Check conditional GET
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		normalizedETag := normalizeETag(etag)
+		normalizedIfNoneMatch := normalizeETag(ifNoneMatch)
+		if normalizedIfNoneMatch == normalizedETag || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
 	}
-}
 
-// handleGetEventSSE handles Server-Sent Events connections
-func handleGetEventSSE(w http.ResponseWriter, r *http.Request) {
-	// Set SSE headers
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	sendJSON(w, http.StatusOK, subscription)
+}
 
-	// For now, just send a test event and close
-	// In a real implementation, this would maintain persistent connections
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+// handleDeleteEventSubscription deletes an event subscription
+func handleDeleteEventSubscription(w http.ResponseWriter, r *http.Request, id string) {
+	existed, err := deleteSubscription(id, func(subscription *models.EventSubscription) bool {
+		return ifMatchSatisfied(r, generateETag(subscriptionResponse(subscription)))
+	})
+	if !existed {
+		sendResourceNotFound(w, r)
+		return
+	}
+	if errors.Is(err, errPreconditionFailed) {
+		sendPreconditionFailed(w, r.URL.Path)
+		return
+	}
+	if err != nil {
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Send a heartbeat event
-	fmt.Fprintf(w, "event: heartbeat\n")
-	fmt.Fprintf(w, "data: {\"EventType\": \"Heartbeat\", \"Message\": \"Connection established\"}\n\n")
-	flusher.Flush()
-
-	// Close the connection after a short time for demo purposes
-	time.Sleep(1 * time.Second)
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // registriesHandler handles Registries collection requests
@@ -1986,7 +3055,7 @@ func registriesHandler(w http.ResponseWriter, r *http.Request) {
 // handleGetRegistries returns the Registries collection
 func handleGetRegistries(w http.ResponseWriter, r *http.Request) {
 	// Create sample registry files
-	baseRegistry := models.NewMessageRegistryFile("Base.1.0.0", "Base.1.0")
+	baseRegistry := models.NewMessageRegistryFile("Base.1.0.0", "Base.1.0", registryLanguages("Base.1.0.0")...)
 	taskRegistry := models.NewMessageRegistryFile("Task.1.0.0", "Task.1.0")
 
 	members := []models.Link{
@@ -2003,13 +3072,7 @@ func handleGetRegistries(w http.ResponseWriter, r *http.Request) {
 		MembersODataCount: len(members),
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(collection); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
-	}
+	sendJSON(w, http.StatusOK, collection)
 }
 
 // registryHandler handles individual Registry requests
@@ -2029,6 +3092,10 @@ func registryHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case "GET":
+		if registryID, ok := strings.CutSuffix(id, ".json"); ok {
+			handleGetMessageRegistry(w, r, registryID)
+			return
+		}
 		handleGetRegistry(w, r, id)
 	default:
 		methodNotAllowed(w, r)
@@ -2041,34 +3108,34 @@ func handleGetRegistry(w http.ResponseWriter, r *http.Request, id string) {
 
 	switch id {
 	case "Base.1.0.0":
-		registry = models.NewMessageRegistryFile("Base.1.0.0", "Base.1.0")
+		registry = models.NewMessageRegistryFile("Base.1.0.0", "Base.1.0", registryLanguages("Base.1.0.0")...)
 	case "Task.1.0.0":
 		registry = models.NewMessageRegistryFile("Task.1.0.0", "Task.1.0")
 	default:
-		http.Error(w, "Registry not found", http.StatusNotFound)
+		sendResourceNotFound(w, r)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	sendJSON(w, http.StatusOK, registry)
+}
 
-	if err := json.NewEncoder(w).Encode(registry); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+// handleGetMessageRegistry returns the message registry referenced by a
+// registry file location. The English location serves the translation the
+// request's Accept-Language prefers; the other locations name their language.
+func handleGetMessageRegistry(w http.ResponseWriter, r *http.Request, id string) {
+	if _, ok := messageRegistry(id, defaultLanguage); ok {
+		sendJSON(w, http.StatusOK, requestRegistry(w, r, id))
 		return
 	}
-}
 
-// oemCustomActionHandler handles OEM custom action requests
-func oemCustomActionHandler(w http.ResponseWriter, r *http.Request) {
-	setRedfishHeaders(w)
-	w.Header().Set("Allow", "POST")
-
-	switch r.Method {
-	case "POST":
-		handleOemCustomAction(w, r)
-	default:
-		methodNotAllowed(w, r)
+	if cut := strings.LastIndex(id, "."); cut > 0 {
+		if registry, ok := messageRegistry(id[:cut], id[cut+1:]); ok {
+			w.Header().Set("Content-Language", registry.Language)
+			sendJSON(w, http.StatusOK, registry)
+			return
+		}
 	}
+	sendResourceNotFound(w, r)
 }
 
 // handleOemCustomAction handles the OEM custom action
@@ -2096,39 +3163,75 @@ func handleOemCustomAction(w http.ResponseWriter, r *http.Request) {
 		response["Parameters"] = requestBody.Parameters
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
-	}
+	sendJSON(w, http.StatusOK, response)
 }
 
 // taskServiceHandler handles TaskService requests
 func taskServiceHandler(w http.ResponseWriter, r *http.Request) {
 	setRedfishHeaders(w)
-	w.Header().Set("Allow", "GET")
+	w.Header().Set("Allow", "GET, PATCH")
 
 	switch r.Method {
 	case "GET":
 		handleGetTaskService(w, r)
+	case "PATCH":
+		handlePatchServiceEnabled(w, r, "/redfish/v1/TaskService", func() interface{} { return currentTaskService() })
 	default:
 		methodNotAllowed(w, r)
 	}
 }
 
-// handleGetTaskService returns the TaskService resource
+// handleGetTaskService returns the TaskService resource, with its Tasks
+// collection inline when $expand asks for it
 func handleGetTaskService(w http.ResponseWriter, r *http.Request) {
-	taskService := models.NewTaskService()
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	queryParams, err := parseQueryParameters(r.URL.Query())
+	if err != nil {
+		sendQueryError(w, err)
+		return
+	}
 
-	if err := json.NewEncoder(w).Encode(taskService); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	taskService := currentTaskService()
+	if !expandsProperty(queryParams.Expand, "Tasks") {
+		sendJSON(w, http.StatusOK, taskService)
 		return
 	}
+	sendJSON(w, http.StatusOK, &expandedTaskService{TaskService: taskService, Tasks: embeddedTasks(queryParams.ExpandLevels)})
+}
+
+// expandedTaskService is the TaskService with its Tasks collection inline
+type expandedTaskService struct {
+	*models.TaskService
+	Tasks interface{} `json:"Tasks"`
+}
+
+// embeddedTasks returns the first page of the Tasks collection for
+// embedding in the TaskService, with the tasks themselves inline as well
+// when $expand reaches a second level
+func embeddedTasks(levels int) interface{} {
+	tasksMutex.RLock()
+	defer tasksMutex.RUnlock()
+
+	selected := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		selected = append(selected, task)
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+
+	collection := newTaskCollection(selected)
+	paginateEmbeddedCollection(&collection)
+	if levels > 1 {
+		return expandTaskMembers(&collection, selected)
+	}
+	return &collection
+}
+
+// currentTaskService builds the TaskService with whether it is enabled
+func currentTaskService() *models.TaskService {
+	taskService := models.NewTaskService()
+	taskService.ServiceEnabled = serviceEnabled("/redfish/v1/TaskService")
+	taskService.CompletedTaskOverWritePolicy = currentTaskRetention.Load().policy
+	taskService.Status = serviceStatus("/redfish/v1/TaskService")
+	return taskService
 }
 
 // tasksHandler handles TaskService Tasks collection requests
@@ -2140,7 +3243,9 @@ func tasksHandler(w http.ResponseWriter, r *http.Request) {
 	case "GET":
 		handleGetTasks(w, r)
 	case "POST":
-		handlePostTask(w, r)
+		if requireServiceEnabled(w, "/redfish/v1/TaskService") {
+			handlePostTask(w, r)
+		}
 	default:
 		methodNotAllowed(w, r)
 	}
@@ -2148,15 +3253,76 @@ func tasksHandler(w http.ResponseWriter, r *http.Request) {
 
 // handleGetTasks returns the Tasks collection
 func handleGetTasks(w http.ResponseWriter, r *http.Request) {
+	queryParams, err := parseQueryParameters(r.URL.Query())
+	if err != nil {
+		sendQueryError(w, err)
+		return
+	}
+
+	var filter *filterExpression
+	if queryParams.Filter != "" {
+		filter, err = parseFilter(queryParams.Filter)
+		if err == nil {
+			for _, property := range filter.properties() {
+				if _, ok := taskProperty(&models.Task{}, property); !ok {
+					err = fmt.Errorf("$filter property %s is not supported on tasks", property)
+					break
+				}
+			}
+		}
+		if err != nil {
+			sendRedfishError(w, "QueryParameterValueFormatError", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	descending := false
+	if queryParams.OrderBy != "" {
+		fields := strings.Fields(queryParams.OrderBy)
+		if len(fields) == 0 || fields[0] != "StartTime" || len(fields) > 2 || (len(fields) == 2 && fields[1] != "asc" && fields[1] != "desc") {
+			sendRedfishError(w, "QueryParameterValueFormatError", fmt.Sprintf("Unsupported $orderby on tasks: %s", queryParams.OrderBy), http.StatusBadRequest)
+			return
+		}
+		descending = len(fields) == 2 && fields[1] == "desc"
+	}
+
 	tasksMutex.RLock()
 	defer tasksMutex.RUnlock()
 
-	members := make([]models.Link, 0, len(tasks))
+	selected := make([]*models.Task, 0, len(tasks))
 	for _, task := range tasks {
+		if filter == nil || filter.matches(func(property string) (string, bool) { return taskProperty(task, property) }) {
+			selected = append(selected, task)
+		}
+	}
+
+	// Order by Id by default so the collection is stable between requests
+	sort.Slice(selected, func(i, j int) bool {
+		if queryParams.OrderBy != "" && selected[i].StartTime != selected[j].StartTime {
+			before := taskStartTime(selected[i]).Before(taskStartTime(selected[j]))
+			return before != descending
+		}
+		return selected[i].ID < selected[j].ID
+	})
+
+	collection := newTaskCollection(selected)
+	paginateCollection(&collection, queryParams)
+
+	if expandsMembers(queryParams.Expand) {
+		sendJSON(w, http.StatusOK, expandTaskMembers(&collection, selected))
+		return
+	}
+	sendJSON(w, http.StatusOK, collection)
+}
+
+// newTaskCollection builds the Tasks collection listing the given tasks
+func newTaskCollection(selected []*models.Task) models.Collection {
+	members := make([]models.Link, 0, len(selected))
+	for _, task := range selected {
 		members = append(members, models.Link{ODataID: task.ODataID})
 	}
 
-	collection := models.Collection{
+	return models.Collection{
 		ODataContext:      "/redfish/v1/$metadata#TaskCollection.TaskCollection",
 		ODataID:           "/redfish/v1/TaskService/Tasks",
 		ODataType:         "#TaskCollection.TaskCollection",
@@ -2164,51 +3330,95 @@ func handleGetTasks(w http.ResponseWriter, r *http.Request) {
 		Members:           members,
 		MembersODataCount: len(members),
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+// expandedTaskCollection is a tasks collection with its members inline
+type expandedTaskCollection struct {
+	*models.Collection
+	Members []*models.Task `json:"Members"`
+}
 
-	if err := json.NewEncoder(w).Encode(collection); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
+// expandTaskMembers inlines the tasks on a collection page, taking a
+// snapshot of each so a running task is not read while it changes
+func expandTaskMembers(collection *models.Collection, selected []*models.Task) *expandedTaskCollection {
+	byID := make(map[models.ODataID]*models.Task, len(selected))
+	for _, task := range selected {
+		byID[task.ODataID] = task
+	}
+	result := &expandedTaskCollection{Collection: collection, Members: make([]*models.Task, 0, len(collection.Members))}
+	for _, link := range collection.Members {
+		if task, ok := byID[link.ODataID]; ok {
+			result.Members = append(result.Members, task.Snapshot())
+		}
+	}
+	return result
+}
+
+// taskProperty returns a task property usable in $filter
+func taskProperty(task *models.Task, property string) (string, bool) {
+	switch property {
+	case "Id":
+		return task.ID, true
+	case "Name":
+		return task.Name, true
+	case "TaskState":
+		return task.TaskState, true
+	case "TaskStatus":
+		return task.TaskStatus, true
+	case "StartTime":
+		return task.StartTime, true
+	case "EndTime":
+		return task.EndTime, true
 	}
+	return "", false
+}
+
+// taskStartTime parses a task's StartTime, returning the zero time if unset
+func taskStartTime(task *models.Task) time.Time {
+	t, _ := time.Parse(time.RFC3339, task.StartTime)
+	return t
 }
 
 // handlePostTask creates a new task
 func handlePostTask(w http.ResponseWriter, r *http.Request) {
 	// For demo purposes, create a simple task
 	// In a real implementation, this would parse task creation parameters
-	id := fmt.Sprintf("%x", md5.Sum([]byte(time.Now().String())))[:8]
-
-	task := models.NewTask(id, "POST", "/redfish/v1/TaskService/Tasks")
+	task := models.NewTask(newResourceID(), "POST", "/redfish/v1/TaskService/Tasks")
+	task.SetEstimatedDuration(2 * demoTaskStepDuration)
+	if err := addTask(task); err != nil {
+		sendAddTaskError(w, err)
+		return
+	}
+	response := task.Snapshot()
 
 	// Simulate task execution
+	ctx := backgroundContext(r)
+	created := response.Version()
 	go func() {
-		time.Sleep(2 * time.Second) // Simulate work
-		tasksMutex.Lock()
-		task.UpdateTaskState("Running")
-		task.SetPercentComplete(50)
-		tasksMutex.Unlock()
-
-		time.Sleep(2 * time.Second) // More work
-		tasksMutex.Lock()
-		task.UpdateTaskState("Completed")
-		task.SetPercentComplete(100)
-		tasksMutex.Unlock()
-	}()
-
-	tasksMutex.Lock()
-	tasks[id] = task
-	tasksMutex.Unlock()
+		if err := simulateWork(ctx, demoTaskStepDuration); err != nil {
+			abandonTask(task, created, err)
+			return
+		}
+		version, err := startTask(task)
+		if err != nil {
+			return
+		}
+		if version, err = updateTask(task, version, func(task *models.Task) { task.SetPercentComplete(50) }); err != nil {
+			return
+		}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Location", string(task.ODataID))
-	w.WriteHeader(http.StatusCreated)
+		if err := simulateWork(ctx, demoTaskStepDuration); err != nil {
+			abandonTask(task, version, err)
+			return
+		}
+		updateTask(task, version, func(task *models.Task) {
+			task.UpdateTaskState("Completed")
+			task.SetPercentComplete(100)
+		})
+	}()
 
-	if err := json.NewEncoder(w).Encode(task); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
-	}
+	w.Header().Set("Location", string(response.ODataID))
+	sendJSON(w, http.StatusCreated, response)
 }
 
 // taskHandler handles individual Task requests
@@ -2218,13 +3428,17 @@ func taskHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Extract task ID from URL
 	path := strings.TrimPrefix(r.URL.Path, "/redfish/v1/TaskService/Tasks/")
-	parts := strings.Split(path, "/")
+	parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
 	id := parts[0]
 
 	if id == "" {
 		http.Error(w, "Task ID required", http.StatusBadRequest)
 		return
 	}
+	if len(parts) > 1 {
+		subTasksHandler(w, r, id, parts[1:])
+		return
+	}
 
 	switch r.Method {
 	case "GET":
@@ -2236,39 +3450,94 @@ func taskHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleGetTask returns a specific task
-func handleGetTask(w http.ResponseWriter, r *http.Request, id string) {
+// subTasksHandler serves the SubTasks collection of a composite task and
+// its sub-tasks, which are read only
+func subTasksHandler(w http.ResponseWriter, r *http.Request, id string, subPath []string) {
+	if subPath[0] != "SubTasks" || len(subPath) > 2 {
+		sendResourceNotFound(w, r)
+		return
+	}
+
 	tasksMutex.RLock()
 	task, exists := tasks[id]
+	if exists {
+		task = task.Snapshot()
+	}
 	tasksMutex.RUnlock()
 
-	if !exists {
-		http.Error(w, "Task not found", http.StatusNotFound)
+	if !exists || task.SubTasks == nil {
+		sendResourceNotFound(w, r)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Allow", "GET")
+	if r.Method != "GET" {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	if len(subPath) == 1 {
+		members := make([]models.Link, 0, len(task.SubTaskMembers()))
+		for _, sub := range task.SubTaskMembers() {
+			members = append(members, models.Link{ODataID: sub.ODataID})
+		}
+		sendJSON(w, http.StatusOK, models.Collection{
+			ODataContext:      "/redfish/v1/$metadata#TaskCollection.TaskCollection",
+			ODataID:           models.ODataID(task.SubTasks.ODataID),
+			ODataType:         "#TaskCollection.TaskCollection",
+			Name:              "Sub-Task Collection",
+			Members:           members,
+			MembersODataCount: len(members),
+		})
+		return
+	}
 
-	if err := json.NewEncoder(w).Encode(task); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	sub, ok := task.SubTask(subPath[1])
+	if !ok {
+		sendResourceNotFound(w, r)
 		return
 	}
+	etag := fmt.Sprintf(`"%s-%s-%d"`, task.ID, sub.ID, sub.Version())
+	sub.SetODataETag(etag)
+	w.Header().Set("ETag", etag)
+	sendJSON(w, http.StatusOK, sub)
 }
 
-// handleDeleteTask deletes a task
-func handleDeleteTask(w http.ResponseWriter, r *http.Request, id string) {
-	tasksMutex.Lock()
-	_, exists := tasks[id]
+// handleGetTask returns a specific task
+func handleGetTask(w http.ResponseWriter, r *http.Request, id string) {
+	tasksMutex.RLock()
+	task, exists := tasks[id]
 	if exists {
-		delete(tasks, id)
+		task = task.Snapshot()
+	}
+	tasksMutex.RUnlock()
+
+	if !exists {
+		sendResourceNotFound(w, r)
+		return
 	}
-	tasksMutex.Unlock()
 
+	etag := taskETag(task)
+	task.SetODataETag(etag)
+	w.Header().Set("ETag", etag)
+	sendJSON(w, http.StatusOK, task)
+}
+
+// handleDeleteTask cancels a task and deletes it. A conditional DELETE fails
+// if the task changed since the client last read it.
+func handleDeleteTask(w http.ResponseWriter, r *http.Request, id string) {
+	exists, satisfied := cancelTask(id, func(etag string) bool { return ifMatchSatisfied(r, etag) })
 	if !exists {
-		http.Error(w, "Task not found", http.StatusNotFound)
+		sendResourceNotFound(w, r)
 		return
 	}
+	if !satisfied {
+		sendPreconditionFailed(w, r.URL.Path)
+		return
+	}
+
+	// A task still waiting for its maintenance window is cancelled with it
+	cancelScheduledOperation(id)
 
 	w.WriteHeader(http.StatusNoContent)
 }
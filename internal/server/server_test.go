@@ -1,11 +1,24 @@
 package server
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/user/redfish-server/internal/auth"
 	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
 )
 
 func TestHealthHandler(t *testing.T) {
@@ -35,6 +48,109 @@ func TestHealthHandler(t *testing.T) {
 	}
 }
 
+func TestFixedBodiesContentLength(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, path := range []string{
+		"/health",
+		"/redfish",
+		"/redfish/v1/openapi.yaml",
+		"/redfish/v1/",
+		"/redfish/v1/$metadata",
+		"/redfish/v1/$metadata?$format=json",
+		"/redfish/v1/odata",
+	} {
+		get := httptest.NewRecorder()
+		mux.ServeHTTP(get, httptest.NewRequest("GET", path, nil))
+		if get.Code != http.StatusOK {
+			t.Fatalf("%s: expected GET to return 200, got %d", path, get.Code)
+		}
+		if length := get.Header().Get("Content-Length"); length != strconv.Itoa(get.Body.Len()) {
+			t.Errorf("%s: expected Content-Length %d, got %q", path, get.Body.Len(), length)
+		}
+
+		head := httptest.NewRecorder()
+		mux.ServeHTTP(head, httptest.NewRequest("HEAD", path, nil))
+		if head.Code != http.StatusOK || head.Body.Len() != 0 {
+			t.Errorf("%s: expected HEAD to return 200 and no body, got %d with %d bytes", path, head.Code, head.Body.Len())
+		}
+		for _, name := range []string{"Content-Length", "Content-Type", "ETag"} {
+			if head.Header().Get(name) == "" || head.Header().Get(name) != get.Header().Get(name) {
+				t.Errorf("%s: expected HEAD to send %s %q, got %q", path, name, get.Header().Get(name), head.Header().Get(name))
+			}
+		}
+		if allow := head.Header().Get("Allow"); allow != "GET, HEAD" {
+			t.Errorf("%s: expected Allow GET, HEAD, got %q", path, allow)
+		}
+	}
+}
+
+func TestSecurityHeadersOnRejections(t *testing.T) {
+	s, err := New(&config.Config{
+		DevMode: true,
+		Server: config.ServerConfig{
+			Address:               ":0",
+			ContentSecurityPolicy: config.DefaultContentSecurityPolicy,
+			FrameOptions:          "DENY",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	// Responses the middleware sends without reaching a handler carry the
+	// headers as well
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/redfish/v1/Systems", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401, got %d", w.Code)
+	}
+	if w.Header().Get("X-Frame-Options") != "DENY" || w.Header().Get("Content-Security-Policy") != config.DefaultContentSecurityPolicy || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("Expected the security headers on a 401, got %v", w.Header())
+	}
+}
+
+func TestRedfishRoot(t *testing.T) {
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	for _, path := range []string{"/redfish", "/redfish/"} {
+		// Unauthenticated clients can probe the version map
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", path, w.Code)
+		}
+		var versions map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &versions); err != nil || versions["v1"] != "/redfish/v1/" {
+			t.Errorf("%s: expected the version map, got %s", path, w.Body.String())
+		}
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("%s: expected an ETag", path)
+		}
+
+		req = httptest.NewRequest("HEAD", path, nil)
+		w = httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Header().Get("ETag") != etag {
+			t.Errorf("%s: expected HEAD to return 200 with ETag %s, got %d and %q", path, etag, w.Code, w.Header().Get("ETag"))
+		}
+
+		req = httptest.NewRequest("GET", path, nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("%s: expected status 304 for a matching If-None-Match, got %d", path, w.Code)
+		}
+	}
+}
+
 func TestServiceRootHandler(t *testing.T) {
 	// Create a test server
 	mux := http.NewServeMux()
@@ -63,6 +179,7 @@ func TestServiceRootHandler(t *testing.T) {
 
 func TestServerCreation(t *testing.T) {
 	cfg := &config.Config{
+		DevMode: true,
 		Server: config.ServerConfig{
 			Address:      ":8443",
 			ReadTimeout:  30,
@@ -88,3 +205,1217 @@ func TestServerCreation(t *testing.T) {
 		t.Error("Server config not set correctly")
 	}
 }
+
+func TestServerTimeouts(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server: config.ServerConfig{
+			Address:           ":8443",
+			ReadTimeout:       30,
+			ReadHeaderTimeout: 5,
+			WriteTimeout:      45,
+			IdleTimeout:       90,
+		},
+		TLS: config.TLSConfig{
+			Enabled: false,
+		},
+	}
+
+	server, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	if server.httpServer.ReadTimeout != 30*time.Second {
+		t.Errorf("Expected ReadTimeout 30s, got %v", server.httpServer.ReadTimeout)
+	}
+	if server.httpServer.ReadHeaderTimeout != 5*time.Second {
+		t.Errorf("Expected ReadHeaderTimeout 5s, got %v", server.httpServer.ReadHeaderTimeout)
+	}
+	if server.httpServer.WriteTimeout != 45*time.Second {
+		t.Errorf("Expected WriteTimeout 45s, got %v", server.httpServer.WriteTimeout)
+	}
+	if server.httpServer.IdleTimeout != 90*time.Second {
+		t.Errorf("Expected IdleTimeout 90s, got %v", server.httpServer.IdleTimeout)
+	}
+
+	cfg.Server.IdleTimeout = -1
+	if _, err := New(cfg); err == nil {
+		t.Error("Expected negative timeout to be rejected")
+	}
+}
+
+func TestSessionMembersResponse(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	// Create a session through the collection
+	body := strings.NewReader(`{"UserName": "admin", "Password": "password"}`)
+	req := httptest.NewRequest("POST", "/redfish/v1/SessionService/Sessions", body)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 from POST on Sessions, got %d", w.Code)
+	}
+	token := w.Header().Get("X-Auth-Token")
+	defer auth.GetAuthService().DeleteSession(token)
+	sessionURI := sessionURIOf(t, token)
+	if location := w.Header().Get("Location"); !strings.HasSuffix(location, sessionURI) {
+		t.Errorf("Expected Location to name the session, got %s", location)
+	}
+
+	// The collection returns the full envelope
+	req = httptest.NewRequest("GET", "/redfish/v1/SessionService/Sessions", nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var collection models.Collection
+	if err := json.Unmarshal(w.Body.Bytes(), &collection); err != nil {
+		t.Fatalf("Collection response is not an object: %v", err)
+	}
+	if collection.ODataID != "/redfish/v1/SessionService/Sessions" {
+		t.Errorf("Unexpected collection @odata.id %q", collection.ODataID)
+	}
+	if collection.MembersODataCount != len(collection.Members) {
+		t.Errorf("Members@odata.count %d does not match %d members", collection.MembersODataCount, len(collection.Members))
+	}
+	if !containsLink(collection.Members, sessionURI) {
+		t.Errorf("Expected collection to contain %s", sessionURI)
+	}
+
+	// Listing sessions never reveals their tokens
+	if strings.Contains(w.Body.String(), token) {
+		t.Errorf("Expected the collection not to contain the session's token: %s", w.Body.String())
+	}
+
+	// The Members path returns only the array
+	req = httptest.NewRequest("GET", "/redfish/v1/SessionService/Sessions/Members", nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var members []models.Link
+	if err := json.Unmarshal(w.Body.Bytes(), &members); err != nil {
+		t.Fatalf("Members response is not an array: %v", err)
+	}
+	if !containsLink(members, sessionURI) {
+		t.Errorf("Expected members to contain %s", sessionURI)
+	}
+	if strings.Contains(w.Body.String(), token) {
+		t.Errorf("Expected the members not to contain the session's token: %s", w.Body.String())
+	}
+
+	// POST on Members still creates a session
+	body = strings.NewReader(`{"UserName": "operator", "Password": "password"}`)
+	req = httptest.NewRequest("POST", "/redfish/v1/SessionService/Sessions/Members", body)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 from POST on Members, got %d", w.Code)
+	}
+	auth.GetAuthService().DeleteSession(w.Header().Get("X-Auth-Token"))
+}
+
+func TestSessionsCollectionScopedToUser(t *testing.T) {
+	authService := auth.GetAuthService()
+	var tokens []string
+	for _, username := range []string{"admin", "operator", "operator"} {
+		token, err := authService.CreateSession(username)
+		if err != nil {
+			t.Fatalf("Failed to create a session for %s: %v", username, err)
+		}
+		tokens = append(tokens, token)
+	}
+	t.Cleanup(func() {
+		for _, token := range tokens {
+			authService.DeleteSession(token)
+		}
+	})
+	sessionURI := func(i int) string { return sessionURIOf(t, tokens[i]) }
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	list := func(username string) []models.Link {
+		req := httptest.NewRequest("GET", "/redfish/v1/SessionService/Sessions", nil)
+		req = req.WithContext(auth.SetUserContext(req.Context(), username, "Session"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var collection models.Collection
+		json.Unmarshal(w.Body.Bytes(), &collection)
+		if collection.MembersODataCount != len(collection.Members) {
+			t.Errorf("Members@odata.count %d does not match %d members", collection.MembersODataCount, len(collection.Members))
+		}
+		return collection.Members
+	}
+
+	// An operator, holding ConfigureSelf but not ConfigureUsers, sees only
+	// their own sessions
+	members := list("operator")
+	if !containsLink(members, sessionURI(1)) || !containsLink(members, sessionURI(2)) {
+		t.Errorf("Expected the operator to see their sessions, got %v", members)
+	}
+	for _, member := range members {
+		if session, _ := authService.GetSessionByID(path.Base(string(member.ODataID))); session.Username != "operator" {
+			t.Errorf("Expected the operator to see only their own sessions, got one of %q", session.Username)
+		}
+	}
+
+	// An administrator sees every session
+	members = list("admin")
+	for i := range tokens {
+		if !containsLink(members, sessionURI(i)) {
+			t.Errorf("Expected the administrator to see %s", sessionURI(i))
+		}
+	}
+
+	// Not even holders of ConfigureUsers learn the tokens of other sessions
+	for _, member := range members {
+		for _, token := range tokens {
+			if strings.Contains(string(member.ODataID), token) {
+				t.Errorf("Expected %s not to contain a session token", member.ODataID)
+			}
+		}
+	}
+
+	// Nor can others read a session by its Id
+	req := httptest.NewRequest("GET", sessionURI(0), nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "operator", "Session"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected the operator reading the administrator's session to get 404, got %d", w.Code)
+	}
+}
+
+// sessionURIOf returns the URI of the session a token authenticates
+func sessionURIOf(t *testing.T, token string) string {
+	t.Helper()
+	session, ok := auth.GetAuthService().GetSession(token)
+	if !ok {
+		t.Fatal("Expected the token to have a session")
+	}
+	return "/redfish/v1/SessionService/Sessions/" + session.ID
+}
+
+// containsLink reports whether links contains the given @odata.id
+func containsLink(links []models.Link, id string) bool {
+	for _, link := range links {
+		if string(link.ODataID) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConditionalDeleteSession(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	authService := auth.GetAuthService()
+	token, err := authService.CreateSession("admin")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer authService.DeleteSession(token)
+	sessionURI := sessionURIOf(t, token)
+
+	req := httptest.NewRequest("GET", sessionURI, nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected the session to carry an ETag")
+	}
+
+	// A stale ETag leaves the session in place
+	req = httptest.NewRequest("DELETE", sessionURI, nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+	req.Header.Set("If-Match", `"00000000"`)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Expected status 412, got %d", w.Code)
+	}
+	if _, ok := authService.GetSession(token); !ok {
+		t.Fatal("Expected the session to survive a failed conditional DELETE")
+	}
+
+	req = httptest.NewRequest("DELETE", sessionURI, nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+	req.Header.Set("If-Match", etag)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if _, ok := authService.GetSession(token); ok {
+		t.Error("Expected the session to be deleted")
+	}
+}
+
+func TestDeleteSessionRequiresOwnershipOrPrivilege(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	authService := auth.GetAuthService()
+	newSession := func(username string) string {
+		token, err := authService.CreateSession(username)
+		if err != nil {
+			t.Fatalf("Failed to create a session for %s: %v", username, err)
+		}
+		t.Cleanup(func() { authService.DeleteSession(token) })
+		return token
+	}
+	deleteAs := func(username, token string) int {
+		req := httptest.NewRequest("DELETE", sessionURIOf(t, token), nil)
+		if username != "" {
+			req = req.WithContext(auth.SetUserContext(req.Context(), username, "Session"))
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Knowing another user's token is not enough
+	adminToken := newSession("admin")
+	if code := deleteAs("operator", adminToken); code != http.StatusForbidden {
+		t.Errorf("Expected an operator deleting the administrator's session to get 403, got %d", code)
+	}
+	if code := deleteAs("", adminToken); code != http.StatusForbidden {
+		t.Errorf("Expected an unauthenticated DELETE to get 403, got %d", code)
+	}
+	if _, ok := authService.GetSession(adminToken); !ok {
+		t.Fatal("Expected the administrator's session to survive")
+	}
+
+	// Users may end their own sessions
+	operatorToken := newSession("operator")
+	if code := deleteAs("operator", operatorToken); code != http.StatusNoContent {
+		t.Errorf("Expected an operator deleting their own session to get 204, got %d", code)
+	}
+
+	// ConfigureUsers covers the sessions of others
+	operatorToken = newSession("operator")
+	if code := deleteAs("admin", operatorToken); code != http.StatusNoContent {
+		t.Errorf("Expected the administrator deleting an operator's session to get 204, got %d", code)
+	}
+	if _, ok := authService.GetSession(operatorToken); ok {
+		t.Error("Expected the operator's session to be deleted")
+	}
+}
+
+func TestRefreshSession(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	authService := auth.GetAuthService()
+	token, err := authService.CreateSession("admin")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer authService.DeleteSession(token)
+	before, _ := authService.GetSession(token)
+	sessionURI := sessionURIOf(t, token)
+
+	// Without the session's token the refresh is rejected
+	req := httptest.NewRequest("POST", sessionURI, nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without token, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", sessionURI, nil)
+	req.Header.Set("X-Auth-Token", "not-the-token")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with wrong token, got %d", w.Code)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+
+	req = httptest.NewRequest("POST", sessionURI, nil)
+	req.Header.Set("X-Auth-Token", token)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		ID  string `json:"Id"`
+		Oem struct {
+			Contoso struct {
+				Expires string `json:"Expires"`
+			} `json:"Contoso"`
+		} `json:"Oem"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if response.ID != before.ID {
+		t.Errorf("Expected session Id %s, got %s", before.ID, response.ID)
+	}
+	expires, err := time.Parse(time.RFC3339, response.Oem.Contoso.Expires)
+	if err != nil {
+		t.Fatalf("Invalid Expires value %q: %v", response.Oem.Contoso.Expires, err)
+	}
+	if !expires.After(before.Expires.Truncate(time.Second)) {
+		t.Errorf("Expected Expires to move past %v, got %v", before.Expires, expires)
+	}
+}
+
+func TestSessionResponseEscapesUserName(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	userName := `o"brien\\ <admin> & ü`
+	authService := auth.GetAuthService()
+	token, err := authService.CreateSession(userName)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer authService.DeleteSession(token)
+
+	req := httptest.NewRequest("GET", sessionURIOf(t, token), nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var session models.Session
+	if err := json.Unmarshal(w.Body.Bytes(), &session); err != nil {
+		t.Fatalf("Expected valid JSON, got %v: %s", err, w.Body.String())
+	}
+	if session.UserName != userName {
+		t.Errorf("Expected UserName %q, got %q", userName, session.UserName)
+	}
+	if session.ODataEtag == "" || session.ODataEtag != w.Header().Get("ETag") {
+		t.Errorf("Expected @odata.etag to match the ETag header, got %q and %q", session.ODataEtag, w.Header().Get("ETag"))
+	}
+	if session.Oem == nil || session.Oem.Contoso == nil || session.Oem.Contoso.Expires == "" {
+		t.Errorf("Expected the Contoso expiry in Oem, got %+v", session.Oem)
+	}
+}
+
+func TestSessionResponseResistsInjection(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	userName := `x", "Injected": true, "Oem": {}, "Name": "y`
+	authService := auth.GetAuthService()
+	token, err := authService.CreateSession(userName)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer authService.DeleteSession(token)
+
+	req := httptest.NewRequest("GET", sessionURIOf(t, token), nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &properties); err != nil {
+		t.Fatalf("Expected valid JSON, got %v: %s", err, w.Body.String())
+	}
+	if _, ok := properties["Injected"]; ok {
+		t.Error("Expected the username not to inject properties")
+	}
+	var name, stored string
+	json.Unmarshal(properties["Name"], &name)
+	json.Unmarshal(properties["UserName"], &stored)
+	if name != "User Session" || stored != userName {
+		t.Errorf("Expected Name and UserName to be intact, got %q and %q", name, stored)
+	}
+}
+
+func TestSessionConditionalGet(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	authService := auth.GetAuthService()
+	token, err := authService.CreateSession("admin")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer authService.DeleteSession(token)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", sessionURIOf(t, token), nil)
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	etag := get("").Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected the session to carry an ETag")
+	}
+	w := get(etag)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 with no body for the current ETag, got %d", w.Code)
+	}
+	if w.Header().Get("ETag") != etag {
+		t.Errorf("Expected the 304 to repeat the ETag, got %q", w.Header().Get("ETag"))
+	}
+	if w := get(`"outdated"`); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for an outdated ETag, got %d", w.Code)
+	}
+}
+
+func TestAccountServicePolicyUpdate(t *testing.T) {
+	authService := auth.GetAuthService()
+	previous := authService.GetAccountPolicy()
+	defer authService.SetAccountPolicy(previous)
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	send := func(method, path, body, username string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), username, "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	// Operators lack ConfigureManager
+	if w := send("PATCH", "/redfish/v1/AccountService", `{"MinPasswordLength": 12}`, "operator"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for operator, got %d", w.Code)
+	}
+
+	w := send("PATCH", "/redfish/v1/AccountService", `{"MinPasswordLength": 12}`, "admin")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var accountService models.AccountService
+	if err := json.NewDecoder(w.Body).Decode(&accountService); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if accountService.MinPasswordLength != 12 {
+		t.Errorf("Expected MinPasswordLength 12, got %d", accountService.MinPasswordLength)
+	}
+
+	// Account creation reads the live policy
+	if w := send("POST", "/redfish/v1/AccountService/Accounts", `{"UserName": "policy-short", "Password": "tenletters"}`, "admin"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a 10 character password, got %d", w.Code)
+	}
+	if w := send("POST", "/redfish/v1/AccountService/Accounts", `{"UserName": "policy-long", "Password": "twelveletter"}`, "admin"); w.Code != http.StatusCreated {
+		t.Errorf("Expected status 201 for a 12 character password, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send("GET", "/redfish/v1/AccountService/Accounts/policy-long", "", "admin"); w.Code != http.StatusOK {
+		t.Errorf("Expected created account to be readable, got %d", w.Code)
+	}
+}
+
+func TestAccountServicePolicyUpdateRotatesSessionToken(t *testing.T) {
+	authService := auth.GetAuthService()
+	previous := authService.GetAccountPolicy()
+	defer authService.SetAccountPolicy(previous)
+
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	send := func(method, path, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-Auth-Token", token)
+		w := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	oldToken, err := authService.CreateSession("admin")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer authService.DeleteSession(oldToken)
+	oldURI := sessionURIOf(t, oldToken)
+
+	w := send("PATCH", "/redfish/v1/AccountService", fmt.Sprintf(`{"MinPasswordLength": %d}`, previous.MinPasswordLength), oldToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	newToken := w.Header().Get("X-Auth-Token")
+	if newToken == "" || newToken == oldToken {
+		t.Fatalf("Expected a new X-Auth-Token, got %q", newToken)
+	}
+	defer authService.DeleteSession(newToken)
+
+	if w := send("GET", "/redfish/v1/Systems", "", oldToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the old token to be rejected, got %d", w.Code)
+	}
+	if w := send("GET", "/redfish/v1/Systems", "", newToken); w.Code != http.StatusOK {
+		t.Errorf("Expected the new token to authenticate, got %d", w.Code)
+	}
+	if w := send("GET", sessionURIOf(t, newToken), "", newToken); w.Code != http.StatusOK || sessionURIOf(t, newToken) != oldURI {
+		t.Errorf("Expected the session to keep its URI, got %d", w.Code)
+	}
+
+	// Basic authentication has no token to rotate
+	req := httptest.NewRequest("PATCH", "/redfish/v1/AccountService", strings.NewReader(`{}`))
+	req.SetBasicAuth("admin", "password")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("X-Auth-Token") != "" {
+		t.Errorf("Expected no X-Auth-Token for basic authentication, got %d %q", w.Code, w.Header().Get("X-Auth-Token"))
+	}
+}
+
+func TestAccountPolicySurvivesRestart(t *testing.T) {
+	resetStateStores(t)
+	authService := auth.GetAuthService()
+	previous := authService.GetAccountPolicy()
+	defer authService.SetAccountPolicy(previous)
+
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		State:   config.StateConfig{Dir: t.TempDir()},
+	}
+
+	first, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("PATCH", "/redfish/v1/AccountService", strings.NewReader(`{"MinPasswordLength": 12, "AccountLockoutThreshold": 3}`))
+	req.SetBasicAuth("admin", "password")
+	w := httptest.NewRecorder()
+	first.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Simulate a restart: detach from the state directory, go back to the
+	// default policy and start a new server on the same state directory
+	authService.UseStore(store.NewMemoryStore())
+	authService.SetAccountPolicy(auth.DefaultAccountPolicy())
+
+	second, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to restart server: %v", err)
+	}
+
+	req = httptest.NewRequest("GET", "/redfish/v1/AccountService", nil)
+	req.SetBasicAuth("admin", "password")
+	w = httptest.NewRecorder()
+	second.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var accountService models.AccountService
+	json.Unmarshal(w.Body.Bytes(), &accountService)
+	if accountService.MinPasswordLength != 12 || accountService.AccountLockoutThreshold != 3 {
+		t.Errorf("Expected the account policy to survive the restart, got MinPasswordLength %d and AccountLockoutThreshold %d",
+			accountService.MinPasswordLength, accountService.AccountLockoutThreshold)
+	}
+}
+
+func TestAccountServicePolicyOutOfRange(t *testing.T) {
+	authService := auth.GetAuthService()
+	before := authService.GetAccountPolicy()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, body := range []string{
+		`{"MinPasswordLength": 0}`,
+		`{"MinPasswordLength": 100}`,
+		`{"AccountLockoutThreshold": -1}`,
+		`{"AccountLockoutDuration": 1000000}`,
+	} {
+		req := httptest.NewRequest("PATCH", "/redfish/v1/AccountService", strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "Base.1.0.PropertyValueOutOfRange") {
+			t.Errorf("%s: expected PropertyValueOutOfRange, got %s", body, w.Body.String())
+		}
+	}
+
+	if after := authService.GetAccountPolicy(); after != before {
+		t.Errorf("Rejected updates changed the policy: %+v -> %+v", before, after)
+	}
+}
+
+func TestAccountCreationUsesDefaultRole(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		Auth:    config.AuthConfig{DefaultRoleId: "Operator"},
+	}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { setDefaultRole("") })
+
+	req := httptest.NewRequest("POST", "/redfish/v1/AccountService/Accounts", strings.NewReader(`{"UserName": "default-role", "Password": "defaultrole1"}`))
+	req.SetBasicAuth("admin", "password")
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var account models.ManagerAccount
+	json.Unmarshal(w.Body.Bytes(), &account)
+	if account.RoleId != "Operator" {
+		t.Errorf("Expected the configured default role Operator, got %q", account.RoleId)
+	}
+}
+
+func TestInvalidDefaultRoleRejected(t *testing.T) {
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		Auth:    config.AuthConfig{DefaultRoleId: "Superuser"},
+	}
+	if _, err := New(cfg); err == nil {
+		t.Fatal("Expected an unknown default role to be rejected")
+	}
+	if role := *defaultRole.Load(); role != config.DefaultRoleId {
+		t.Errorf("Expected the default role to stay %s, got %s", config.DefaultRoleId, role)
+	}
+}
+
+func TestDevModeRelaxesValidation(t *testing.T) {
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Expected DEV_MODE to allow TLS to be off, got %v", err)
+	}
+	req := httptest.NewRequest("GET", "/redfish/v1", nil)
+	req.Header.Set("Origin", "https://dev.example.com")
+	w := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "*" {
+		t.Errorf("Expected DEV_MODE to admit any origin, got %q", origin)
+	}
+
+	// Without DEV_MODE the secure defaults are enforced
+	tlsFiles := config.TLSConfig{Enabled: true, CertFile: "server.crt", KeyFile: "server.key"}
+	for name, cfg := range map[string]*config.Config{
+		"TLS disabled":        {Server: config.ServerConfig{Address: ":0"}, Auth: config.AuthConfig{AdminPassword: "s3cure-admin"}},
+		"generated TLS":       {Server: config.ServerConfig{Address: ":0"}, TLS: config.TLSConfig{Enabled: true, AutoGenerate: true, AutoGenerateHosts: []string{"localhost"}}, Auth: config.AuthConfig{AdminPassword: "s3cure-admin"}},
+		"no admin password":   {Server: config.ServerConfig{Address: ":0"}, TLS: tlsFiles},
+		"any origin for CORS": {Server: config.ServerConfig{Address: ":0", CORSAllowedOrigins: []string{"*"}}, TLS: tlsFiles, Auth: config.AuthConfig{AdminPassword: "s3cure-admin"}},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected the configuration to be rejected without DEV_MODE", name)
+		}
+		cfg.DevMode = true
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%s: expected DEV_MODE to accept the configuration, got %v", name, err)
+		}
+	}
+}
+
+func TestPatchReturnsFreshETag(t *testing.T) {
+	authService := auth.GetAuthService()
+	previous := authService.GetAccountPolicy()
+	defer authService.SetAccountPolicy(previous)
+	systemStore.Put("etag-test", models.NewComputerSystem("etag-test"))
+	t.Cleanup(func() { systemStore.Delete("etag-test") })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"AccountService", "/redfish/v1/AccountService", `{"AccountLockoutThreshold": 7}`},
+		{"Bios settings", "/redfish/v1/Systems/etag-test/Bios/Settings", `{"Attributes": {"ProcTurboMode": "Disabled"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			send := func(method, body string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(method, tt.path, strings.NewReader(body))
+				req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, req)
+				return w
+			}
+
+			before := send("GET", "").Header().Get("ETag")
+
+			w := send("PATCH", tt.body)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			etag := w.Header().Get("ETag")
+			if etag == "" || etag == before {
+				t.Errorf("Expected a new ETag after PATCH, got %q (before %q)", etag, before)
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body["@odata.etag"] != etag {
+				t.Errorf("Expected @odata.etag %q, got %v", etag, body["@odata.etag"])
+			}
+
+			if after := send("GET", "").Header().Get("ETag"); after != etag {
+				t.Errorf("Expected subsequent GET ETag %q, got %q", etag, after)
+			}
+		})
+	}
+}
+
+func TestPatchSystemHostNameRaisesEvent(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+	allowLoopbackDestinations(t)
+	systemStore.Put("hostname-test", models.NewComputerSystem("hostname-test"))
+	t.Cleanup(func() { systemStore.Delete("hostname-test") })
+
+	received := make(chan models.Event, 1)
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer destination.Close()
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	createTestSubscription(t, mux, fmt.Sprintf(`{"Destination": %q}`, destination.URL))
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", "/redfish/v1/Systems/hostname-test", strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, hostName := range []string{"-web01", "web01-", "web_01", "web01.example.com", "", strings.Repeat("a", 64)} {
+		w := patch(fmt.Sprintf(`{"HostName": %q}`, hostName))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected HostName %q to be rejected with 400, got %d", hostName, w.Code)
+		}
+	}
+	if w := patch(`{"IndicatorLED": "Flashing"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown IndicatorLED to be rejected with 400, got %d", w.Code)
+	}
+	select {
+	case event := <-received:
+		t.Fatalf("Expected no event for rejected changes, got %+v", event)
+	default:
+	}
+
+	w := patch(`{"HostName": "web-01", "AssetTag": "rack7", "IndicatorLED": "Blinking"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var system models.ComputerSystem
+	json.Unmarshal(w.Body.Bytes(), &system)
+	if system.HostName != "web-01" || system.AssetTag != "rack7" || system.IndicatorLED != "Blinking" {
+		t.Errorf("Expected the PATCH to be applied, got %+v", system)
+	}
+
+	select {
+	case event := <-received:
+		if len(event.Events) != 1 || event.Events[0].MessageId != "ResourceEvent.1.0.ResourceChanged" {
+			t.Fatalf("Expected one ResourceChanged record, got %+v", event.Events)
+		}
+		if origin := event.Events[0].OriginOfCondition; origin == nil || *origin != "/redfish/v1/Systems/hostname-test" {
+			t.Errorf("Expected the system as OriginOfCondition, got %v", origin)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a ResourceChanged event")
+	}
+
+	// Writing the same values again changes nothing and raises no event
+	if w := patch(`{"HostName": "web-01"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	select {
+	case event := <-received:
+		t.Errorf("Expected no event for an unchanged HostName, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDeleteSessionResponse(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	authService := auth.GetAuthService()
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"default", nil, http.StatusNoContent},
+		{"accept without preference", map[string]string{"Accept": "application/json"}, http.StatusNoContent},
+		{"preference without accept", map[string]string{"Prefer": "return=representation"}, http.StatusNoContent},
+		{"representation", map[string]string{"Accept": "application/json", "Prefer": "return=representation"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := authService.CreateSession("admin")
+			if err != nil {
+				t.Fatalf("Failed to create session: %v", err)
+			}
+
+			req := httptest.NewRequest("DELETE", sessionURIOf(t, token), nil)
+			req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Session"))
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if _, exists := authService.GetSession(token); exists {
+				t.Error("Session should be removed")
+			}
+
+			if tt.wantStatus == http.StatusNoContent {
+				if w.Body.Len() != 0 {
+					t.Errorf("Expected empty body, got %s", w.Body.String())
+				}
+				return
+			}
+
+			var body struct {
+				ExtendedInfo []models.Message `json:"@Message.ExtendedInfo"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(body.ExtendedInfo) != 1 || body.ExtendedInfo[0].MessageID != "Base.1.0.Success" {
+				t.Errorf("Expected a Base.1.0.Success message, got %+v", body.ExtendedInfo)
+			}
+		})
+	}
+}
+
+func TestTasksFilterAndOrder(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fixtures := []struct {
+		id    string
+		state string
+		start time.Duration
+	}{
+		{"filter-running-late", "Running", 2 * time.Hour},
+		{"filter-running-early", "Running", time.Hour},
+		{"filter-completed", "Completed", 0},
+		{"filter-exception", "Exception", 3 * time.Hour},
+	}
+
+	tasksMutex.Lock()
+	for _, f := range fixtures {
+		task := models.NewTask(f.id, "POST", "/redfish/v1/test")
+		task.TaskState = f.state
+		task.StartTime = base.Add(f.start).Format(time.RFC3339)
+		tasks[f.id] = task
+	}
+	tasksMutex.Unlock()
+	defer func() {
+		tasksMutex.Lock()
+		for _, f := range fixtures {
+			delete(tasks, f.id)
+		}
+		tasksMutex.Unlock()
+	}()
+
+	getMembers := func(query string) []models.Link {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/redfish/v1/TaskService/Tasks?"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", query, w.Code, w.Body.String())
+		}
+		var collection models.Collection
+		if err := json.NewDecoder(w.Body).Decode(&collection); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return collection.Members
+	}
+
+	members := getMembers("$filter=TaskState%20eq%20'Running'&$orderby=StartTime")
+	var ours []string
+	for _, member := range members {
+		id := string(member.ODataID)[len("/redfish/v1/TaskService/Tasks/"):]
+		tasksMutex.RLock()
+		state := tasks[id].TaskState
+		tasksMutex.RUnlock()
+		if state != "Running" {
+			t.Errorf("Filtered collection contains %s task %s", state, id)
+		}
+		if strings.HasPrefix(id, "filter-") {
+			ours = append(ours, id)
+		}
+	}
+	if len(ours) != 2 || ours[0] != "filter-running-early" || ours[1] != "filter-running-late" {
+		t.Errorf("Expected running tasks ordered by StartTime, got %v", ours)
+	}
+
+	members = getMembers("$filter=TaskState%20eq%20'Running'&$orderby=StartTime%20desc")
+	ours = nil
+	for _, member := range members {
+		if id := string(member.ODataID)[len("/redfish/v1/TaskService/Tasks/"):]; strings.HasPrefix(id, "filter-") {
+			ours = append(ours, id)
+		}
+	}
+	if len(ours) != 2 || ours[0] != "filter-running-late" {
+		t.Errorf("Expected descending StartTime order, got %v", ours)
+	}
+
+	for _, query := range []string{"$filter=Bogus%20eq%20'x'", "$filter=TaskState%20eq", "$orderby=Name", "$orderby=%20"} {
+		req := httptest.NewRequest("GET", "/redfish/v1/TaskService/Tasks?"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestSelectProjectsODataContext(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	tests := map[string]string{
+		"":                       "/redfish/v1/$metadata#ComputerSystem.ComputerSystem",
+		"?$select=Id,PowerState": "/redfish/v1/$metadata#ComputerSystem.ComputerSystem(Id,PowerState)",
+		"?$select=@odata.id,Name,Name,NotAProperty": "/redfish/v1/$metadata#ComputerSystem.ComputerSystem(Name)",
+	}
+	for query, want := range tests {
+		req := httptest.NewRequest("GET", "/redfish/v1/Systems/1"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d", query, w.Code)
+		}
+
+		var system models.ComputerSystem
+		if err := json.Unmarshal(w.Body.Bytes(), &system); err != nil {
+			t.Fatalf("%q: invalid response: %v", query, err)
+		}
+		if string(system.ODataContext) != want {
+			t.Errorf("%q: expected @odata.context %s, got %s", query, want, system.ODataContext)
+		}
+		if system.ODataID != "/redfish/v1/Systems/1" {
+			t.Errorf("%q: expected @odata.id /redfish/v1/Systems/1, got %s", query, system.ODataID)
+		}
+	}
+}
+
+func TestConcurrentSystemGetAndPatch(t *testing.T) {
+	defer systemStore.Put("1", models.NewComputerSystem("1"))
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	targets := []string{"Pxe", "Hdd", "Cd", "Usb"}
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				body := fmt.Sprintf(`{"AssetTag": "tag-%d-%d", "Boot": {"BootSourceOverrideTarget": %q}}`, worker, i, targets[i%len(targets)])
+				req := httptest.NewRequest("PATCH", "/redfish/v1/Systems/1", strings.NewReader(body))
+				req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, req)
+				if w.Code != http.StatusOK {
+					t.Errorf("PATCH: expected status 200, got %d: %s", w.Code, w.Body.String())
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				req := httptest.NewRequest("GET", "/redfish/v1/Systems/1", nil)
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, req)
+				var system models.ComputerSystem
+				if err := json.NewDecoder(w.Body).Decode(&system); err != nil || w.Code != http.StatusOK {
+					t.Errorf("GET: expected a system, got %d (%v)", w.Code, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	system, _ := getSystem("1")
+	if !strings.HasPrefix(system.AssetTag, "tag-") || !slices.Contains(targets, system.Boot.BootSourceOverrideTarget) {
+		t.Errorf("Expected the last PATCH to be stored, got AssetTag=%q Boot=%+v", system.AssetTag, system.Boot)
+	}
+
+	// Read-only properties and unknown boot values are rejected without side effects
+	for _, body := range []string{`{"PowerState": "Off"}`, `{"AssetTag": "x", "Boot": {"BootSourceOverrideTarget": "Tape"}}`} {
+		req := httptest.NewRequest("PATCH", "/redfish/v1/Systems/1", strings.NewReader(body))
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+	}
+	if after, _ := getSystem("1"); after.AssetTag != system.AssetTag {
+		t.Errorf("A rejected PATCH changed AssetTag to %q", after.AssetTag)
+	}
+}
+
+func TestSessionLoginFailureIsRedfishError(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, body := range []string{`{}`, `{"UserName": "admin", "Password": "wrong"}`} {
+		req := httptest.NewRequest("POST", "/redfish/v1/SessionService/Sessions", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized || w.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("Expected a JSON 401 for %s, got %d %q", body, w.Code, w.Header().Get("Content-Type"))
+		}
+		var redfishError models.RedfishError
+		if err := json.NewDecoder(w.Body).Decode(&redfishError); err != nil || redfishError.Error.Code != baseRegistry.MessageID("InsufficientPrivilege") {
+			t.Errorf("Expected an InsufficientPrivilege body for %s, got %q", body, w.Body.String())
+		}
+	}
+}
+
+// failingWriter is a ResponseWriter whose body writes always fail
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (f failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestSendJSONEncodeFailure(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Location", "/redfish/v1/Systems/1")
+	sendJSON(w, http.StatusCreated, map[string]interface{}{"Value": math.Inf(1)})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/json" || w.Header().Get("Location") != "" {
+		t.Errorf("Unexpected headers on encode failure: %v", w.Header())
+	}
+	var body models.RedfishError
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected a Redfish error body, got %q: %v", w.Body.String(), err)
+	}
+	if body.Error.Code != baseRegistry.MessageID("InternalError") {
+		t.Errorf("Expected InternalError, got %s", body.Error.Code)
+	}
+
+	// Once the status is sent a failed write can only be logged
+	failing := failingWriter{httptest.NewRecorder()}
+	sendJSON(failing, http.StatusOK, map[string]string{"Id": "1"})
+	if failing.Code != http.StatusOK {
+		t.Errorf("Expected the original status 200 to stand, got %d", failing.Code)
+	}
+}
+
+func TestDistinctDemoResources(t *testing.T) {
+	systemStore.Put("2", models.NewComputerSystem("2"))
+	defer systemStore.Delete("2")
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	getSystem := func(id string) models.ComputerSystem {
+		req := httptest.NewRequest("GET", "/redfish/v1/Systems/"+id, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for system %s, got %d", id, w.Code)
+		}
+		var system models.ComputerSystem
+		json.Unmarshal(w.Body.Bytes(), &system)
+		return system
+	}
+	first, second := getSystem("1"), getSystem("2")
+	if first.UUID == "" || first.UUID == second.UUID {
+		t.Errorf("Expected distinct UUIDs, got %q and %q", first.UUID, second.UUID)
+	}
+	if first.SerialNumber == "" || first.SerialNumber == second.SerialNumber {
+		t.Errorf("Expected distinct serial numbers, got %q and %q", first.SerialNumber, second.SerialNumber)
+	}
+	if first.Model == "" || first.Model == second.Model {
+		t.Errorf("Expected distinct models, got %q and %q", first.Model, second.Model)
+	}
+	if again := getSystem("1"); again.UUID != first.UUID {
+		t.Errorf("Expected a stable UUID, got %q then %q", first.UUID, again.UUID)
+	}
+
+	// Only ids listed in their collection are served
+	for _, path := range []string{"/redfish/v1/Systems/9", "/redfish/v1/Chassis/9", "/redfish/v1/Managers/9", "/redfish/v1/Managers/9/SerialInterfaces"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected status 404, got %d", path, w.Code)
+		}
+	}
+
+	// Nor are the actions of a system that does not exist, and no task is started
+	tasksMutex.RLock()
+	before := len(tasks)
+	tasksMutex.RUnlock()
+	for _, method := range []string{"GET", "POST"} {
+		req := httptest.NewRequest(method, "/redfish/v1/Systems/9/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "On"}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s reset of an unknown system: expected status 404, got %d", method, w.Code)
+		}
+	}
+	tasksMutex.RLock()
+	after := len(tasks)
+	tasksMutex.RUnlock()
+	if after != before {
+		t.Errorf("Expected no task for an unknown system, got %d -> %d", before, after)
+	}
+
+	for _, path := range []string{"/redfish/v1/Chassis", "/redfish/v1/Managers"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var collection models.Collection
+		json.Unmarshal(w.Body.Bytes(), &collection)
+		if collection.MembersODataCount != 1 || !containsLink(collection.Members, path+"/1") {
+			t.Errorf("%s: expected the stored member only, got %+v", path, collection.Members)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// serviceConditionsHandler handles the service-wide conditions summary
+func serviceConditionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Allow", "GET")
+	if r.Method != "GET" {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	conditions := models.NewServiceConditions(collectConditions())
+	etag := resourceETag(conditions)
+	w.Header().Set("ETag", etag)
+
+	if sendNotModified(w, r, etag) {
+		return
+	}
+
+	sendJSON(w, http.StatusOK, conditions)
+}
+
+// collectConditions returns a condition for every stored system, chassis and
+// manager whose Status reports a health other than OK
+func collectConditions() []models.Condition {
+	var conditions []models.Condition
+	add := func(kind string, uri models.ODataID, status models.Status) {
+		if status.Health == "" || status.Health == "OK" {
+			return
+		}
+		conditions = append(conditions, models.Condition{
+			MessageId:         "ResourceEvent.1.0.ResourceStatusChanged" + status.Health,
+			Message:           fmt.Sprintf("The health of %s %s is %s", kind, uri, status.Health),
+			MessageArgs:       []string{string(uri), status.Health},
+			Severity:          status.Health,
+			OriginOfCondition: &models.Link{ODataID: uri},
+		})
+	}
+
+	for _, id := range systemStore.IDs() {
+		if system, ok := systemStore.Get(id); ok {
+			add("system", system.ODataID, system.Status)
+		}
+	}
+	for _, id := range chassisStore.IDs() {
+		if chassis, ok := chassisStore.Get(id); ok {
+			add("chassis", chassis.ODataID, chassis.Status)
+		}
+	}
+	for _, id := range managerStore.IDs() {
+		if manager, ok := managerStore.Get(id); ok {
+			add("manager", manager.ODataID, manager.Status)
+		}
+	}
+	return conditions
+}
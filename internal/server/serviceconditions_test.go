@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestDegradedChassisSurfacesCondition(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	getConditions := func() models.ServiceConditions {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/redfish/v1/ServiceConditions", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var conditions models.ServiceConditions
+		json.Unmarshal(w.Body.Bytes(), &conditions)
+		return conditions
+	}
+
+	if healthy := getConditions(); healthy.HealthRollup != "OK" || len(healthy.Conditions) != 0 {
+		t.Fatalf("Expected no conditions on a healthy service, got %+v", healthy)
+	}
+
+	chassis := models.NewChassis("degraded")
+	chassis.Status.Health = "Critical"
+	chassisStore.Put("degraded", chassis)
+	t.Cleanup(func() { chassisStore.Delete("degraded") })
+
+	conditions := getConditions()
+	if conditions.HealthRollup != "Critical" || len(conditions.Conditions) != 1 {
+		t.Fatalf("Expected one critical condition, got %+v", conditions)
+	}
+	condition := conditions.Conditions[0]
+	if condition.Severity != "Critical" || condition.OriginOfCondition == nil || condition.OriginOfCondition.ODataID != "/redfish/v1/Chassis/degraded" {
+		t.Errorf("Unexpected condition: %+v", condition)
+	}
+
+	// The stored state is what the chassis itself reports
+	req := httptest.NewRequest("GET", "/redfish/v1/Chassis/degraded", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var served models.Chassis
+	json.Unmarshal(w.Body.Bytes(), &served)
+	if served.Status.Health != "Critical" {
+		t.Errorf("Expected the chassis to report Critical health, got %q", served.Status.Health)
+	}
+}
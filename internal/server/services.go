@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/user/redfish-server/internal/models"
+)
+
+// serviceDisabledRetryAfter is the Retry-After, in seconds, sent with the 503
+// of a disabled service
+const serviceDisabledRetryAfter = "60"
+
+// toggleableServices hold whether each service that carries ServiceEnabled
+// is enabled, keyed by the service's URI. A disabled service still answers
+// requests for itself, so it can be inspected and enabled again, but refuses
+// the operations it provides.
+var toggleableServices = map[models.ODataID]*atomic.Bool{
+	"/redfish/v1/AccountService": new(atomic.Bool),
+	"/redfish/v1/EventService":   new(atomic.Bool),
+	"/redfish/v1/SessionService": new(atomic.Bool),
+	"/redfish/v1/TaskService":    new(atomic.Bool),
+}
+
+func init() {
+	for _, enabled := range toggleableServices {
+		enabled.Store(true)
+	}
+}
+
+// serviceEnabled reports whether the service at uri is enabled
+func serviceEnabled(uri models.ODataID) bool {
+	return toggleableServices[uri].Load()
+}
+
+// serviceStatus is the Status of a service, Disabled while it is
+func serviceStatus(uri models.ODataID) models.Status {
+	if !serviceEnabled(uri) {
+		return models.Status{State: "Disabled", Health: "OK"}
+	}
+	return models.Status{State: "Enabled", Health: "OK"}
+}
+
+// requireServiceEnabled sends a 503 ServiceDisabled error with Retry-After
+// and returns false if the service at uri is disabled
+func requireServiceEnabled(w http.ResponseWriter, uri models.ODataID) bool {
+	if serviceEnabled(uri) {
+		return true
+	}
+	w.Header().Set("Retry-After", serviceDisabledRetryAfter)
+	sendRedfishError(w, "ServiceDisabled", fmt.Sprintf("The service at %s is disabled", uri), http.StatusServiceUnavailable)
+	return false
+}
+
+// handlePatchServiceEnabled enables or disables the service at uri (PATCH),
+// ServiceEnabled being its only writable property, and responds with the
+// service as built by current
+func handlePatchServiceEnabled(w http.ResponseWriter, r *http.Request, uri models.ODataID, current func() interface{}) {
+	if !requirePrivilege(w, r, "ConfigureManager") {
+		return
+	}
+
+	var requestBody map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		sendRedfishError(w, "MalformedJSON", "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+	for name := range requestBody {
+		if name != "ServiceEnabled" {
+			sendRedfishError(w, "PropertyNotWritable", fmt.Sprintf("The property %s is read only", name), http.StatusBadRequest)
+			return
+		}
+	}
+	var enabled *bool
+	if raw, ok := requestBody["ServiceEnabled"]; ok {
+		if err := json.Unmarshal(raw, &enabled); err != nil || enabled == nil {
+			sendRedfishError(w, "PropertyValueTypeError", "ServiceEnabled must be a boolean", http.StatusBadRequest)
+			return
+		}
+		toggleableServices[uri].Store(*enabled)
+	}
+
+	sendUpdatedResource(w, current())
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestDisabledServiceReturnsServiceDisabled(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(auth.SetUserContext(req.Context(), "admin", "Basic"))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		service      models.ODataID
+		method, path string
+		body         string
+	}{
+		{"/redfish/v1/SessionService", "POST", "/redfish/v1/SessionService/Sessions", `{"UserName": "admin", "Password": "password"}`},
+		{"/redfish/v1/AccountService", "POST", "/redfish/v1/AccountService/Accounts", `{"UserName": "disabled", "Password": "Password123", "RoleId": "ReadOnly"}`},
+		{"/redfish/v1/AccountService", "DELETE", "/redfish/v1/AccountService/Accounts/operator", ""},
+		{"/redfish/v1/EventService", "POST", "/redfish/v1/EventService/Subscriptions", `{"Destination": "http://192.0.2.1/events"}`},
+		{"/redfish/v1/TaskService", "POST", "/redfish/v1/TaskService/Tasks", `{}`},
+		{"/redfish/v1/TaskService", "POST", "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", `{"ResetType": "ForceRestart"}`},
+		{"/redfish/v1/TaskService", "POST", "/redfish/v1/Managers/1/Actions/Manager.Reset", `{"ResetType": "GracefulRestart"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			t.Cleanup(func() { toggleableServices[tt.service].Store(true) })
+
+			w := send("PATCH", string(tt.service), `{"ServiceEnabled": false}`)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected PATCH to succeed, got %d: %s", w.Code, w.Body.String())
+			}
+			var service map[string]interface{}
+			json.NewDecoder(w.Body).Decode(&service)
+			if service["ServiceEnabled"] != false {
+				t.Errorf("Expected ServiceEnabled false, got %v", service["ServiceEnabled"])
+			}
+			if status, _ := service["Status"].(map[string]interface{}); status["State"] != "Disabled" {
+				t.Errorf("Expected Status.State Disabled, got %v", service["Status"])
+			}
+
+			w = send(tt.method, tt.path, tt.body)
+			if w.Code != http.StatusServiceUnavailable {
+				t.Fatalf("Expected status 503, got %d: %s", w.Code, w.Body.String())
+			}
+			if w.Header().Get("Retry-After") == "" {
+				t.Error("Expected a Retry-After header")
+			}
+			var errorResponse models.RedfishError
+			json.NewDecoder(w.Body).Decode(&errorResponse)
+			if errorResponse.Error.Code != baseRegistry.MessageID("ServiceDisabled") {
+				t.Errorf("Expected ServiceDisabled, got %s", errorResponse.Error.Code)
+			}
+
+			// The service itself can still be read
+			if w := send("GET", string(tt.service), ""); w.Code != http.StatusOK {
+				t.Errorf("Expected the disabled service to be readable, got %d", w.Code)
+			}
+		})
+	}
+
+	// Only ServiceEnabled is writable, and only as a boolean
+	if w := send("PATCH", "/redfish/v1/TaskService", `{"ServiceEnabled": "no"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected a non-boolean ServiceEnabled to be rejected, got %d", w.Code)
+	}
+	if w := send("PATCH", "/redfish/v1/TaskService", `{"DateTime": "now"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected a read-only property to be rejected, got %d", w.Code)
+	}
+	if !serviceEnabled("/redfish/v1/TaskService") {
+		t.Error("Expected the rejected PATCHes to leave the service enabled")
+	}
+}
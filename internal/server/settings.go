@@ -0,0 +1,108 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// deferredSettings implements the @Redfish.Settings pattern. Changes written
+// to a resource's settings object are staged here and only merged into the
+// resource's current values when the resource is reset.
+type deferredSettings struct {
+	mutex    sync.Mutex
+	defaults func() map[string]interface{}
+	current  map[string]map[string]interface{} // keyed by resource @odata.id
+	pending  map[string]map[string]interface{}
+	applied  map[string]time.Time
+}
+
+// newDeferredSettings creates a settings store whose resources start out with
+// the values returned by defaults
+func newDeferredSettings(defaults func() map[string]interface{}) *deferredSettings {
+	return &deferredSettings{
+		defaults: defaults,
+		current:  make(map[string]map[string]interface{}),
+		pending:  make(map[string]map[string]interface{}),
+		applied:  make(map[string]time.Time),
+	}
+}
+
+// Current returns a copy of the values in effect for the resource
+func (d *deferredSettings) Current(uri string) map[string]interface{} {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return copyAttributes(d.currentLocked(uri))
+}
+
+// Pending returns a copy of the values staged for the resource
+func (d *deferredSettings) Pending(uri string) map[string]interface{} {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return copyAttributes(d.pending[uri])
+}
+
+// LastApplied returns when staged values were last applied to the resource
+func (d *deferredSettings) LastApplied(uri string) (time.Time, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	t, ok := d.applied[uri]
+	return t, ok
+}
+
+// Stage merges changes into the values pending for the resource
+func (d *deferredSettings) Stage(uri string, changes map[string]interface{}) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	staged, ok := d.pending[uri]
+	if !ok {
+		staged = make(map[string]interface{})
+		d.pending[uri] = staged
+	}
+	for name, value := range changes {
+		staged[name] = value
+	}
+}
+
+// Apply merges the pending values into the resource's current values and
+// clears them. It reports whether anything was staged.
+func (d *deferredSettings) Apply(uri string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	staged, ok := d.pending[uri]
+	if !ok {
+		return false
+	}
+
+	current := d.currentLocked(uri)
+	for name, value := range staged {
+		current[name] = value
+	}
+	delete(d.pending, uri)
+	d.applied[uri] = time.Now().UTC()
+	return true
+}
+
+// currentLocked returns the resource's current values, seeding them from the
+// defaults on first use. The caller must hold the mutex.
+func (d *deferredSettings) currentLocked(uri string) map[string]interface{} {
+	current, ok := d.current[uri]
+	if !ok {
+		current = d.defaults()
+		d.current[uri] = current
+	}
+	return current
+}
+
+// copyAttributes returns a shallow copy of an attribute map
+func copyAttributes(attributes map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(attributes))
+	for name, value := range attributes {
+		result[name] = value
+	}
+	return result
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/middleware"
+	"github.com/user/redfish-server/internal/models"
+)
+
+// sseSettings are the configured heartbeat interval and connection limit of
+// the SSE stream
+type sseSettings struct {
+	heartbeat      time.Duration
+	maxConnections int64 // zero means no limit
+}
+
+var (
+	currentSSESettings atomic.Pointer[sseSettings]
+
+	// sseConnections counts the open SSE streams
+	sseConnections atomic.Int64
+
+	// sseStreams closes the open SSE streams, by the ID of their subscription
+	sseStreamsMutex sync.Mutex
+	sseStreams      = make(map[string]context.CancelFunc)
+)
+
+func init() {
+	setSSESettings(config.EventsConfig{})
+}
+
+// setSSESettings applies the configured SSE heartbeat interval and
+// connection limit, with a zero interval selecting the default
+func setSSESettings(events config.EventsConfig) {
+	interval := events.SSEHeartbeatInterval
+	if interval == 0 {
+		interval = config.DefaultSSEHeartbeatInterval
+	}
+	currentSSESettings.Store(&sseSettings{
+		heartbeat:      time.Duration(interval) * time.Second,
+		maxConnections: int64(events.MaxSSEConnections),
+	})
+}
+
+// acquireSSEConnection counts a new SSE stream, reporting false without
+// counting it if the limit is already reached
+func acquireSSEConnection(limit int64) bool {
+	for {
+		open := sseConnections.Load()
+		if limit > 0 && open >= limit {
+			return false
+		}
+		if sseConnections.CompareAndSwap(open, open+1) {
+			return true
+		}
+	}
+}
+
+// closeSSEStream ends the SSE stream of a subscription, if it is open
+func closeSSEStream(id string) {
+	sseStreamsMutex.Lock()
+	defer sseStreamsMutex.Unlock()
+	if cancel, ok := sseStreams[id]; ok {
+		cancel()
+		delete(sseStreams, id)
+	}
+}
+
+// openSSESubscription lists an SSE stream as a subscription for as long as
+// ctx lasts; deleting the subscription cancels ctx. The returned function
+// removes the subscription when the stream ends.
+func openSSESubscription(ctx context.Context, r *http.Request) (context.Context, func(), error) {
+	ctx, cancel := context.WithCancel(ctx)
+	subscription := models.NewSSESubscription(newResourceID(), r.RemoteAddr)
+
+	sseStreamsMutex.Lock()
+	sseStreams[subscription.ID] = cancel
+	sseStreamsMutex.Unlock()
+
+	if err := addSubscription(subscription); err != nil {
+		closeSSEStream(subscription.ID)
+		return nil, nil, err
+	}
+	return ctx, func() {
+		closeSSEStream(subscription.ID)
+		deleteSubscription(subscription.ID, nil)
+	}, nil
+}
+
+// sseCORS is what CORS preflights for the SSE stream are told: it is only
+// read, and besides credentials a client resuming a stream sends
+// Last-Event-ID. The stream's own Access-Control-Allow-Origin comes from the
+// CORS middleware, like every other response.
+var sseCORS = middleware.CORSRoute{
+	Methods: "GET",
+	Headers: "Last-Event-ID, Authorization, X-Auth-Token",
+}
+
+// eventSSEHandler handles Server-Sent Events requests
+func eventSSEHandler(w http.ResponseWriter, r *http.Request) {
+	setRedfishHeaders(w)
+	w.Header().Set("Allow", "GET")
+
+	switch r.Method {
+	case "GET":
+		handleGetEventSSE(w, r)
+	case "OPTIONS":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// handleGetEventSSE handles Server-Sent Events connections. The stream stays
+// open, with a heartbeat at the configured interval, until the client leaves
+// or the server shuts down, and is listed among the subscriptions meanwhile.
+func handleGetEventSSE(w http.ResponseWriter, r *http.Request) {
+	if !enabledFeatures.Load().sse {
+		sendResourceNotFound(w, r)
+		return
+	}
+	if !requireServiceEnabled(w, "/redfish/v1/EventService") {
+		return
+	}
+
+	settings := currentSSESettings.Load()
+	if !acquireSSEConnection(settings.maxConnections) {
+		w.Header().Set("Retry-After", "30")
+		sendRedfishError(w, "ServiceInUse", fmt.Sprintf("The limit of %d concurrent SSE connections is reached", settings.maxConnections), http.StatusServiceUnavailable)
+		return
+	}
+	defer sseConnections.Add(-1)
+
+	// Set SSE headers
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// The server-wide WriteTimeout is sized for ordinary responses and would
+	// kill a long-lived stream, so clear the write deadline for this connection.
+	// Recorders and other writers that don't support deadlines are fine as is.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendRedfishError(w, "InternalError", "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Hold the stream open until the client leaves, its subscription is
+	// deleted or the server shuts down
+	ctx, done := beginLongLived(r)
+	defer done()
+	ctx, closeSubscription, err := openSSESubscription(ctx, r)
+	if err != nil {
+		sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer closeSubscription()
+
+	// Send a heartbeat event
+	fmt.Fprintf(w, "event: heartbeat\n")
+	fmt.Fprintf(w, "data: {\"EventType\": \"Heartbeat\", \"Message\": \"Connection established\"}\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(settings.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprintf(w, "event: heartbeat\ndata: {\"EventType\": \"Heartbeat\"}\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
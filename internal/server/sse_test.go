@@ -0,0 +1,263 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/middleware"
+	"github.com/user/redfish-server/internal/models"
+)
+
+func TestSSEHeartbeatAndConnectionLimit(t *testing.T) {
+	currentSSESettings.Store(&sseSettings{heartbeat: 20 * time.Millisecond, maxConnections: 1})
+	t.Cleanup(func() { setSSESettings(config.EventsConfig{}) })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	open := func() *http.Response {
+		resp, err := http.Get(server.URL + "/redfish/v1/EventService/SSE")
+		if err != nil {
+			t.Fatalf("SSE request failed: %v", err)
+		}
+		return resp
+	}
+
+	stream := open()
+	if stream.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", stream.StatusCode)
+	}
+
+	// The connection heartbeat is followed by one per interval
+	heartbeats := make(chan time.Time, 8)
+	go func() {
+		scanner := bufio.NewScanner(stream.Body)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "event: heartbeat") {
+				heartbeats <- time.Now()
+			}
+		}
+		close(heartbeats)
+	}()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		select {
+		case _, ok := <-heartbeats:
+			if !ok {
+				t.Fatal("SSE stream closed early")
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected heartbeat %d within the interval", i+1)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected heartbeats 20ms apart, got 4 in %v", elapsed)
+	}
+
+	// A second stream exceeds the limit
+	rejected := open()
+	defer rejected.Body.Close()
+	if rejected.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503 over the connection limit, got %d", rejected.StatusCode)
+	}
+	var errorResponse models.RedfishError
+	json.NewDecoder(rejected.Body).Decode(&errorResponse)
+	if errorResponse.Error.Code != baseRegistry.MessageID("ServiceInUse") {
+		t.Errorf("Expected ServiceInUse, got %s", errorResponse.Error.Code)
+	}
+
+	// Closing the first stream frees its slot
+	stream.Body.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for sseConnections.Load() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the closed stream to be released, %d still open", sseConnections.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	again := open()
+	defer again.Body.Close()
+	if again.StatusCode != http.StatusOK {
+		t.Errorf("Expected a new stream once the slot was freed, got %d", again.StatusCode)
+	}
+}
+
+func TestSSEStreamListedAsSubscription(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	sseSubscriptions := func() []models.EventSubscription {
+		resp, err := http.Get(server.URL + "/redfish/v1/EventService/Subscriptions")
+		if err != nil {
+			t.Fatalf("Subscriptions request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var collection models.Collection
+		json.NewDecoder(resp.Body).Decode(&collection)
+
+		var result []models.EventSubscription
+		for _, member := range collection.Members {
+			resp, err := http.Get(server.URL + string(member.ODataID))
+			if err != nil {
+				t.Fatalf("Subscription request failed: %v", err)
+			}
+			var subscription models.EventSubscription
+			json.NewDecoder(resp.Body).Decode(&subscription)
+			resp.Body.Close()
+			if subscription.SubscriptionType == "SSE" {
+				result = append(result, subscription)
+			}
+		}
+		return result
+	}
+	waitForNoSSESubscriptions := func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for len(sseSubscriptions()) != 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("Expected the SSE subscription to be removed with its stream")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	open := func() (*http.Response, *bufio.Scanner) {
+		resp, err := http.Get(server.URL + "/redfish/v1/EventService/SSE")
+		if err != nil {
+			t.Fatalf("SSE request failed: %v", err)
+		}
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() && !strings.HasPrefix(scanner.Text(), "data:") {
+		}
+		return resp, scanner
+	}
+
+	stream, _ := open()
+	listed := sseSubscriptions()
+	if len(listed) != 1 {
+		t.Fatalf("Expected the open stream to be listed as one SSE subscription, got %d", len(listed))
+	}
+	if listed[0].Destination == "" {
+		t.Error("Expected the SSE subscription to name the client")
+	}
+	stream.Body.Close()
+	waitForNoSSESubscriptions()
+
+	// Deleting the subscription closes its stream
+	stream, scanner := open()
+	defer stream.Body.Close()
+	listed = sseSubscriptions()
+	if len(listed) != 1 {
+		t.Fatalf("Expected one SSE subscription, got %d", len(listed))
+	}
+	req, _ := http.NewRequest("DELETE", server.URL+string(listed[0].ODataID), nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", resp.StatusCode)
+	}
+	closed := make(chan struct{})
+	go func() {
+		for scanner.Scan() {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected deleting the subscription to end the stream")
+	}
+	waitForNoSSESubscriptions()
+}
+
+func TestSSECORS(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	handler := middleware.CORSMiddleware([]string{"https://console.example.com"}, map[string]middleware.CORSRoute{
+		"/redfish/v1/EventService/SSE": sseCORS,
+	}, mux)
+
+	// A preflight is told the stream is only read, and may carry Last-Event-ID
+	req := httptest.NewRequest("OPTIONS", "/redfish/v1/EventService/SSE", nil)
+	req.Header.Set("Origin", "https://console.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the preflight to succeed, got %d", w.Code)
+	}
+	for name, expected := range map[string]string{
+		"Access-Control-Allow-Origin":  "https://console.example.com",
+		"Access-Control-Allow-Methods": "GET",
+		"Allow":                        "GET",
+	} {
+		if got := w.Header().Get(name); got != expected {
+			t.Errorf("Expected %s %q, got %q", name, expected, got)
+		}
+	}
+	if headers := w.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(headers, "Last-Event-ID") {
+		t.Errorf("Expected Last-Event-ID among the allowed headers, got %q", headers)
+	}
+
+	// The stream itself answers only the allowed origins, not any origin
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	for origin, expected := range map[string]string{
+		"https://console.example.com": "https://console.example.com",
+		"https://evil.example.com":    "",
+	} {
+		req, _ := http.NewRequest("GET", server.URL+"/redfish/v1/EventService/SSE", nil)
+		req.Header.Set("Origin", origin)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("SSE request failed: %v", err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != expected {
+			t.Errorf("%s: expected Access-Control-Allow-Origin %q, got %q", origin, expected, got)
+		}
+	}
+
+	// Preflights carry no credentials, so they get past authentication
+	s, err := New(&config.Config{DevMode: true, Server: config.ServerConfig{Address: ":0"}})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	req = httptest.NewRequest("OPTIONS", "/redfish/v1/EventService/SSE", nil)
+	req.Header.Set("Origin", "https://console.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Methods") != "GET" {
+		t.Errorf("Expected an unauthenticated preflight to succeed, got %d with methods %q", w.Code, w.Header().Get("Access-Control-Allow-Methods"))
+	}
+}
+
+func TestSSEWithoutStreamingIsRedfishError(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	// A writer that hides the recorder's Flush cannot stream
+	recorder := httptest.NewRecorder()
+	w := struct{ http.ResponseWriter }{recorder}
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/redfish/v1/EventService/SSE", nil))
+
+	if recorder.Code != http.StatusInternalServerError || recorder.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected a JSON 500, got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	var body models.RedfishError
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil || body.Error.Code != baseRegistry.MessageID("InternalError") {
+		t.Errorf("Expected an InternalError body, got %q", recorder.Body.String())
+	}
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+// Names subscriptions and their pinned certificates are saved under in a store
+const (
+	subscriptionsStateName            = "subscriptions"
+	subscriptionCertificatesStateName = "subscription_certificates"
+)
+
+// Global event subscription storage. Subscriptions are saved to
+// subscriptionStore on every change; by default that is an in-memory store.
+// The certificates pinned for a subscription's destination are kept
+// alongside, keyed by subscription ID.
+var (
+	subscriptionsMutex       sync.RWMutex
+	subscriptions                        = make(map[string]*models.EventSubscription)
+	subscriptionCertificates             = make(map[string][]*models.Certificate)
+	subscriptionStore        store.Store = store.NewMemoryStore()
+)
+
+// useSubscriptionStore replaces the current subscriptions with those saved
+// in s and saves future changes to it
+func useSubscriptionStore(s store.Store) error {
+	var saved []*models.EventSubscription
+	if err := s.Load(subscriptionsStateName, &saved); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("failed to load event subscriptions: %w", err)
+	}
+	certificates := make(map[string][]*models.Certificate)
+	if err := s.Load(subscriptionCertificatesStateName, &certificates); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("failed to load event subscription certificates: %w", err)
+	}
+
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscriptions = make(map[string]*models.EventSubscription, len(saved))
+	for _, subscription := range saved {
+		subscriptions[subscription.ID] = subscription
+	}
+	subscriptionCertificates = certificates
+	subscriptionStore = s
+	return nil
+}
+
+// sortedSubscriptionsLocked returns the subscriptions ordered by ID. The
+// caller must hold subscriptionsMutex.
+func sortedSubscriptionsLocked() []*models.EventSubscription {
+	result := make([]*models.EventSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, subscription)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// saveSubscriptionsLocked writes all subscriptions to the store, except the
+// subscriptions of SSE streams, which cannot outlive the process. The caller
+// must hold the subscriptionsMutex write lock.
+func saveSubscriptionsLocked() error {
+	saved := slices.DeleteFunc(sortedSubscriptionsLocked(), (*models.EventSubscription).IsSSE)
+	if err := subscriptionStore.Save(subscriptionsStateName, saved); err != nil {
+		return fmt.Errorf("failed to save event subscriptions: %w", err)
+	}
+	if err := subscriptionStore.Save(subscriptionCertificatesStateName, subscriptionCertificates); err != nil {
+		return fmt.Errorf("failed to save event subscription certificates: %w", err)
+	}
+	return nil
+}
+
+// addSubscription stores a new subscription, undoing the change if it cannot
+// be saved
+func addSubscription(subscription *models.EventSubscription) error {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	if _, ok := subscriptions[subscription.ID]; ok {
+		return fmt.Errorf("%w: subscription %s", store.ErrExists, subscription.ID)
+	}
+	subscriptions[subscription.ID] = subscription
+	if err := saveSubscriptionsLocked(); err != nil {
+		delete(subscriptions, subscription.ID)
+		return err
+	}
+	return nil
+}
+
+// getSubscription returns a copy of a subscription
+func getSubscription(id string) (*models.EventSubscription, bool) {
+	subscriptionsMutex.RLock()
+	defer subscriptionsMutex.RUnlock()
+
+	subscription, ok := subscriptions[id]
+	if !ok {
+		return nil, false
+	}
+	result := *subscription
+	return &result, true
+}
+
+// errPreconditionFailed reports that a conditional change was refused because
+// the resource no longer matched what the client last read
+var errPreconditionFailed = errors.New("precondition failed")
+
+// deleteSubscription removes a subscription, undoing the change if it cannot
+// be saved. It reports whether the subscription existed. When precondition is
+// set, the subscription is only removed if it holds for the current
+// subscription; otherwise errPreconditionFailed is returned.
+func deleteSubscription(id string, precondition func(*models.EventSubscription) bool) (bool, error) {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	subscription, ok := subscriptions[id]
+	if !ok {
+		return false, nil
+	}
+	if precondition != nil && !precondition(subscription) {
+		return true, errPreconditionFailed
+	}
+	certificates, pinned := subscriptionCertificates[id]
+	delete(subscriptions, id)
+	delete(subscriptionCertificates, id)
+	if err := saveSubscriptionsLocked(); err != nil {
+		subscriptions[id] = subscription
+		if pinned {
+			subscriptionCertificates[id] = certificates
+		}
+		return true, err
+	}
+	discardEventBatch(id)
+	closeSSEStream(id)
+	return true, nil
+}
+
+// getSubscriptionCertificates returns copies of the certificates pinned for a
+// subscription, and whether the subscription exists
+func getSubscriptionCertificates(id string) ([]*models.Certificate, bool) {
+	subscriptionsMutex.RLock()
+	defer subscriptionsMutex.RUnlock()
+
+	if _, ok := subscriptions[id]; !ok {
+		return nil, false
+	}
+	result := make([]*models.Certificate, 0, len(subscriptionCertificates[id]))
+	for _, certificate := range subscriptionCertificates[id] {
+		copied := *certificate
+		result = append(result, &copied)
+	}
+	return result, true
+}
+
+// addSubscriptionCertificate pins a certificate for a subscription, assigning
+// it an Id one past the highest in the subscription's certificate collection
+// so Ids stay unique after a certificate is removed. The change is undone if
+// it cannot be saved.
+func addSubscriptionCertificate(id string, build func(certificateID string) *models.Certificate) (*models.Certificate, error) {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	if _, ok := subscriptions[id]; !ok {
+		return nil, store.ErrResourceMissing
+	}
+	previous := subscriptionCertificates[id]
+	next := 1
+	for _, certificate := range previous {
+		if n, err := strconv.Atoi(certificate.ID); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	certificate := build(strconv.Itoa(next))
+	subscriptionCertificates[id] = append(slices.Clip(previous), certificate)
+	if err := saveSubscriptionsLocked(); err != nil {
+		subscriptionCertificates[id] = previous
+		if previous == nil {
+			delete(subscriptionCertificates, id)
+		}
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// deleteSubscriptionCertificate unpins a certificate of a subscription,
+// undoing the change if it cannot be saved. It reports whether the
+// certificate existed.
+func deleteSubscriptionCertificate(id, certificateID string) (bool, error) {
+	subscriptionsMutex.Lock()
+	defer subscriptionsMutex.Unlock()
+
+	previous := subscriptionCertificates[id]
+	index := slices.IndexFunc(previous, func(certificate *models.Certificate) bool {
+		return certificate.ID == certificateID
+	})
+	if _, ok := subscriptions[id]; !ok || index < 0 {
+		return false, nil
+	}
+	subscriptionCertificates[id] = slices.Delete(slices.Clone(previous), index, index+1)
+	if err := saveSubscriptionsLocked(); err != nil {
+		subscriptionCertificates[id] = previous
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/auth"
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+	"github.com/user/redfish-server/internal/store"
+)
+
+// resetStateStores puts the global account and subscription state back on
+// in-memory stores once a test that persisted it to disk is done
+func resetStateStores(t *testing.T) {
+	t.Cleanup(func() {
+		auth.GetAuthService().UseStore(store.NewMemoryStore())
+		useSubscriptionStore(store.NewMemoryStore())
+	})
+}
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	resetStateStores(t)
+	useSubscriptionStore(store.NewMemoryStore())
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(`{"Destination": "https://listener.example.com/events"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	location := w.Header().Get("Location")
+
+	req = httptest.NewRequest("GET", "/redfish/v1/EventService/Subscriptions", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var collection models.Collection
+	json.Unmarshal(w.Body.Bytes(), &collection)
+	if collection.MembersODataCount != 1 || string(collection.Members[0].ODataID) != location {
+		t.Fatalf("Expected the collection to list %s, got %+v", location, collection.Members)
+	}
+
+	req = httptest.NewRequest("DELETE", location, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", location, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after delete, got %d", w.Code)
+	}
+}
+
+func TestSubscriptionSurvivesRestart(t *testing.T) {
+	resetStateStores(t)
+	cfg := &config.Config{
+		DevMode: true,
+		Server:  config.ServerConfig{Address: ":0"},
+		State:   config.StateConfig{Dir: t.TempDir()},
+	}
+
+	first, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(`{"Destination": "https://listener.example.com/events", "Context": "rack-12"}`))
+	req.SetBasicAuth("admin", "password")
+	w := httptest.NewRecorder()
+	first.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	location := w.Header().Get("Location")
+
+	// Simulate a restart: drop the in-memory subscriptions and start a new
+	// server on the same state directory
+	useSubscriptionStore(store.NewMemoryStore())
+
+	second, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to restart server: %v", err)
+	}
+
+	req = httptest.NewRequest("GET", location, nil)
+	req.SetBasicAuth("admin", "password")
+	w = httptest.NewRecorder()
+	second.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the subscription to survive the restart, got %d", w.Code)
+	}
+	var subscription models.EventSubscription
+	json.Unmarshal(w.Body.Bytes(), &subscription)
+	if subscription.Destination != "https://listener.example.com/events" || subscription.Context != "rack-12" {
+		t.Errorf("Unexpected subscription after restart: %+v", subscription)
+	}
+}
+
+func TestSubscriptionFiltersMustBeAdvertised(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	for _, body := range []string{
+		`{"Destination": "https://listener.example.com/events", "ResourceTypes": ["ComputerSystem", "Thermostat"]}`,
+		`{"Destination": "https://listener.example.com/events", "RegistryPrefixes": ["Contoso"]}`,
+	} {
+		req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400 for %s, got %d", body, w.Code)
+		}
+		var errorResponse models.RedfishError
+		json.Unmarshal(w.Body.Bytes(), &errorResponse)
+		if errorResponse.Error.Code != baseRegistry.MessageID("PropertyValueNotInList") {
+			t.Errorf("Expected PropertyValueNotInList, got %s", errorResponse.Error.Code)
+		}
+	}
+	if count := len(sortedSubscriptionsLocked()); count != 0 {
+		t.Errorf("Expected rejected subscriptions not to be stored, got %d", count)
+	}
+
+	req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(`{"Destination": "https://listener.example.com/events", "ResourceTypes": ["ComputerSystem", "Manager"], "RegistryPrefixes": ["Base"]}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var subscription models.EventSubscription
+	json.Unmarshal(w.Body.Bytes(), &subscription)
+	if !slices.Equal(subscription.ResourceTypes, []string{"ComputerSystem", "Manager"}) || !slices.Equal(subscription.RegistryPrefixes, []string{"Base"}) {
+		t.Errorf("Unexpected subscription filters: %+v", subscription)
+	}
+}
+
+func TestConditionalDeleteSubscription(t *testing.T) {
+	useSubscriptionStore(store.NewMemoryStore())
+	t.Cleanup(func() { useSubscriptionStore(store.NewMemoryStore()) })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("POST", "/redfish/v1/EventService/Subscriptions", strings.NewReader(`{"Destination": "https://listener.example.com/events"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	location := w.Header().Get("Location")
+
+	req = httptest.NewRequest("GET", location, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+
+	req = httptest.NewRequest("DELETE", location, nil)
+	req.Header.Set("If-Match", `"00000000"`)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Expected status 412, got %d", w.Code)
+	}
+	var errorResponse models.RedfishError
+	json.Unmarshal(w.Body.Bytes(), &errorResponse)
+	if errorResponse.Error.Code != baseRegistry.MessageID("PreconditionFailed") {
+		t.Errorf("Expected PreconditionFailed, got %s", errorResponse.Error.Code)
+	}
+
+	req = httptest.NewRequest("GET", location, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the subscription to survive a failed conditional DELETE, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", location, nil)
+	req.Header.Set("If-Match", etag)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+}
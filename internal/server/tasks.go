@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+// errTaskModified reports that a task changed after the version an update
+// was based on, typically because it was cancelled
+var errTaskModified = errors.New("task was modified concurrently")
+
+// errTaskExists reports an attempt to add a task under an Id already in use
+var errTaskExists = errors.New("task already exists")
+
+// errTaskLimit reports that the maximum number of tasks is reached and the
+// overwrite policy frees no room for another
+var errTaskLimit = errors.New("task limit reached")
+
+// taskRetention is how many tasks are kept and what happens once the limit
+// is reached
+type taskRetention struct {
+	maxTasks int // zero means no limit
+	policy   string
+}
+
+// currentTaskRetention is set by New from the configuration; the
+// TaskService advertises the same policy
+var currentTaskRetention atomic.Pointer[taskRetention]
+
+func init() {
+	setTaskRetention(config.TasksConfig{})
+}
+
+// setTaskRetention applies the configured task limit and overwrite policy,
+// with an empty policy selecting Manual
+func setTaskRetention(tasks config.TasksConfig) {
+	policy := tasks.CompletedTaskOverWritePolicy
+	if policy == "" {
+		policy = "Manual"
+	}
+	currentTaskRetention.Store(&taskRetention{maxTasks: tasks.MaxTasks, policy: policy})
+}
+
+// addTask stores a new task, refusing to replace one with the same Id. At
+// the task limit, the Oldest policy deletes the oldest finished tasks to
+// make room; otherwise the task is refused with errTaskLimit.
+func addTask(task *models.Task) error {
+	tasksMutex.Lock()
+	defer tasksMutex.Unlock()
+
+	if _, ok := tasks[task.ID]; ok {
+		return fmt.Errorf("%w: %s", errTaskExists, task.ID)
+	}
+	retention := currentTaskRetention.Load()
+	for retention.maxTasks > 0 && len(tasks) >= retention.maxTasks {
+		if retention.policy != "Oldest" {
+			return fmt.Errorf("%w: %d tasks must be deleted before more are created", errTaskLimit, retention.maxTasks)
+		}
+		oldest := oldestFinishedTask()
+		if oldest == nil {
+			return fmt.Errorf("%w: all %d tasks are still in progress", errTaskLimit, retention.maxTasks)
+		}
+		delete(tasks, oldest.ID)
+	}
+	tasks[task.ID] = task
+	return nil
+}
+
+// oldestFinishedTask returns the earliest created task that has finished,
+// or nil if none has. The caller holds tasksMutex.
+func oldestFinishedTask() *models.Task {
+	var oldest *models.Task
+	for _, task := range tasks {
+		if task.IsFinished() && (oldest == nil || task.Created().Before(oldest.Created())) {
+			oldest = task
+		}
+	}
+	return oldest
+}
+
+// sendAddTaskError reports why addTask refused a task
+func sendAddTaskError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errTaskLimit) {
+		sendRedfishError(w, "CreateLimitReachedForResource", err.Error(), http.StatusConflict)
+		return
+	}
+	sendRedfishError(w, "InternalError", err.Error(), http.StatusInternalServerError)
+}
+
+// taskETag returns the ETag of a task. It is derived from the task's
+// version, so it changes with every update.
+func taskETag(task *models.Task) string {
+	return fmt.Sprintf(`"%s-%d"`, task.ID, task.Version())
+}
+
+// updateTask applies update to a task only if the task is still at version,
+// returning the version the update leaves it at. Workers chain the returned
+// versions, so a cancellation in between is detected rather than overwritten.
+func updateTask(task *models.Task, version uint64, update func(*models.Task)) (uint64, error) {
+	tasksMutex.Lock()
+	defer tasksMutex.Unlock()
+
+	if task.Version() != version {
+		return task.Version(), errTaskModified
+	}
+	update(task)
+	return task.Version(), nil
+}
+
+// startTask moves a task to Running for the worker carrying it out, returning
+// the version to base its later updates on. A task that already finished,
+// such as one cancelled while it waited to start, is left alone.
+func startTask(task *models.Task) (uint64, error) {
+	tasksMutex.Lock()
+	defer tasksMutex.Unlock()
+
+	if task.IsFinished() {
+		return task.Version(), errTaskModified
+	}
+	task.UpdateTaskState("Running")
+	return task.Version(), nil
+}
+
+// cancelTask cancels a task that has not finished and removes it, provided
+// precondition accepts the task's current ETag. It reports whether the task
+// existed and whether the precondition held.
+func cancelTask(id string, precondition func(etag string) bool) (found, satisfied bool) {
+	tasksMutex.Lock()
+	defer tasksMutex.Unlock()
+
+	task, ok := tasks[id]
+	if !ok {
+		return false, false
+	}
+	if !precondition(taskETag(task)) {
+		return true, false
+	}
+	if !task.IsFinished() {
+		task.UpdateTaskState("Cancelled")
+	}
+	delete(tasks, id)
+	return true, true
+}
+
+// runTaskSteps carries out a started task's work, taking duration in all,
+// and returns the version to base its completion on. The sub-tasks of a
+// composite task run in turn, sharing the duration, and the task's progress
+// follows theirs. A task stopped part way is abandoned and the error
+// returned.
+func runTaskSteps(ctx context.Context, task *models.Task, version uint64, duration time.Duration) (uint64, error) {
+	tasksMutex.RLock()
+	steps := make([]string, 0, len(task.SubTaskMembers()))
+	for _, sub := range task.SubTaskMembers() {
+		steps = append(steps, sub.ID)
+	}
+	tasksMutex.RUnlock()
+
+	if len(steps) == 0 {
+		if err := simulateWork(ctx, duration); err != nil {
+			abandonTask(task, version, err)
+			return version, err
+		}
+		return version, nil
+	}
+
+	var err error
+	for _, step := range steps {
+		if version, err = updateTask(task, version, func(task *models.Task) { task.UpdateSubTaskState(step, "Running") }); err != nil {
+			return version, err
+		}
+		if err := simulateWork(ctx, duration/time.Duration(len(steps))); err != nil {
+			abandonTask(task, version, err)
+			return version, err
+		}
+		if version, err = updateTask(task, version, func(task *models.Task) { task.UpdateSubTaskState(step, "Completed") }); err != nil {
+			return version, err
+		}
+	}
+	return version, nil
+}
+
+// setRetryAfter hints, through Retry-After, when a client should check on a
+// task expected to take estimate
+func setRetryAfter(w http.ResponseWriter, estimate time.Duration) {
+	seconds := int(math.Ceil(estimate.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+}
+
+// simulateWork stands in for a task's real work, returning early with the
+// context's error if ctx is cancelled first
+func simulateWork(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// abandonTask marks a task whose worker was stopped, for example by server
+// shutdown, as Cancelled unless it was updated since version
+func abandonTask(task *models.Task, version uint64, cause error) {
+	updateTask(task, version, func(task *models.Task) {
+		task.UpdateTaskState("Cancelled")
+		task.AddMessage(models.Message{
+			MessageID:  "TaskEvent.1.0.TaskCancelled",
+			Message:    fmt.Sprintf("The task was stopped before it finished: %v", cause),
+			Severity:   "Warning",
+			Resolution: "Resubmit the request once the service is available",
+		})
+	})
+}
@@ -0,0 +1,390 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+	"github.com/user/redfish-server/internal/models"
+)
+
+// addTestTask stores a new task and removes it when the test ends
+func addTestTask(t *testing.T, id string) *models.Task {
+	t.Helper()
+	task := models.NewTask(id, "POST", "/redfish/v1/TaskService/Tasks")
+	tasksMutex.Lock()
+	tasks[id] = task
+	tasksMutex.Unlock()
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		delete(tasks, id)
+		tasksMutex.Unlock()
+	})
+	return task
+}
+
+func TestConditionalDeleteTask(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	task := addTestTask(t, "etag-test")
+
+	req := httptest.NewRequest("GET", "/redfish/v1/TaskService/Tasks/etag-test", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected the task to carry an ETag")
+	}
+
+	// The worker moves on after the client read the task
+	if _, err := startTask(task); err != nil {
+		t.Fatalf("Expected the task to start, got %v", err)
+	}
+
+	req = httptest.NewRequest("DELETE", "/redfish/v1/TaskService/Tasks/etag-test", nil)
+	req.Header.Set("If-Match", etag)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Expected status 412 for a stale ETag, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", "/redfish/v1/TaskService/Tasks/etag-test", nil)
+	req.Header.Set("If-Match", taskETag(task))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204 for the current ETag, got %d", w.Code)
+	}
+}
+
+// TestConcurrentCancelAndStateTransitions is meant to run under -race: each
+// task's worker races a cancelling DELETE and clients polling it, and a
+// cancelled task must never be completed afterwards.
+func TestConcurrentCancelAndStateTransitions(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	const count = 20
+	workers := make([]*models.Task, count)
+	completed := make([]bool, count)
+	cancelled := make([]bool, count)
+
+	var wg sync.WaitGroup
+	for i := range count {
+		id := fmt.Sprintf("race-%d", i)
+		task := addTestTask(t, id)
+		workers[i] = task
+		uri := "/redfish/v1/TaskService/Tasks/" + id
+
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			version, err := startTask(task)
+			if err != nil {
+				return
+			}
+			for percent := 10; percent < 100; percent += 10 {
+				if version, err = updateTask(task, version, func(task *models.Task) { task.SetPercentComplete(percent) }); err != nil {
+					return
+				}
+			}
+			_, err = updateTask(task, version, func(task *models.Task) { task.UpdateTaskState("Completed") })
+			completed[i] = err == nil
+		}()
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("DELETE", uri, nil)
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+			tasksMutex.RLock()
+			cancelled[i] = task.TaskState == "Cancelled"
+			tasksMutex.RUnlock()
+		}()
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				req := httptest.NewRequest("GET", uri, nil)
+				mux.ServeHTTP(httptest.NewRecorder(), req)
+			}
+		}()
+	}
+	wg.Wait()
+
+	tasksMutex.RLock()
+	defer tasksMutex.RUnlock()
+	for i, task := range workers {
+		if _, stored := tasks[task.ID]; stored {
+			t.Errorf("Expected task %s to be deleted", task.ID)
+		}
+		// Whichever finished first wins; the other must not overwrite it
+		if cancelled[i] && (task.TaskState != "Cancelled" || completed[i]) {
+			t.Errorf("Task %s was cancelled but its worker went on to leave it %s", task.ID, task.TaskState)
+		}
+		if !cancelled[i] && (task.TaskState != "Completed" || !completed[i]) {
+			t.Errorf("Task %s finished before the cancel but was left %s", task.ID, task.TaskState)
+		}
+	}
+}
+
+func TestRapidTaskCreationUniqueIDs(t *testing.T) {
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	const count = 200
+	ids := make(chan string, count)
+	var wg sync.WaitGroup
+	for range count {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("POST", "/redfish/v1/TaskService/Tasks", nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			var task models.Task
+			json.Unmarshal(w.Body.Bytes(), &task)
+			ids <- task.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool)
+	for id := range ids {
+		if id == "" || seen[id] {
+			t.Errorf("Expected a new unique task Id, got %q", id)
+		}
+		seen[id] = true
+	}
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		defer tasksMutex.Unlock()
+		for id := range seen {
+			delete(tasks, id)
+		}
+	})
+
+	// Every task is still stored, none replaced by another with the same Id
+	tasksMutex.RLock()
+	for id := range seen {
+		if task, ok := tasks[id]; !ok || task.ID != id {
+			t.Errorf("Task %s is missing from the task map", id)
+		}
+	}
+	tasksMutex.RUnlock()
+
+	task := addTestTask(t, "duplicate-test")
+	if err := addTask(models.NewTask("duplicate-test", "POST", "/redfish/v1/TaskService/Tasks")); !errors.Is(err, errTaskExists) {
+		t.Errorf("Expected a duplicate Id to be refused, got %v", err)
+	}
+	tasksMutex.RLock()
+	stored := tasks["duplicate-test"]
+	tasksMutex.RUnlock()
+	if stored != task {
+		t.Error("Expected the original task to be kept")
+	}
+}
+
+func TestCompletedTaskOverWritePolicy(t *testing.T) {
+	tasksMutex.Lock()
+	saved := tasks
+	tasksMutex.Unlock()
+	t.Cleanup(func() {
+		tasksMutex.Lock()
+		tasks = saved
+		tasksMutex.Unlock()
+		setTaskRetention(config.TasksConfig{})
+	})
+	// start empties the task list and applies a retention configuration
+	start := func(tasksConfig config.TasksConfig) {
+		tasksMutex.Lock()
+		tasks = make(map[string]*models.Task)
+		tasksMutex.Unlock()
+		setTaskRetention(tasksConfig)
+	}
+	add := func(id string, finished bool) error {
+		task := models.NewTask(id, "POST", "/redfish/v1/TaskService/Tasks")
+		if finished {
+			task.UpdateTaskState("Completed")
+		}
+		return addTask(task)
+	}
+	stored := func() []string {
+		tasksMutex.RLock()
+		defer tasksMutex.RUnlock()
+		ids := make([]string, 0, len(tasks))
+		for id := range tasks {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	t.Run("Oldest", func(t *testing.T) {
+		start(config.TasksConfig{MaxTasks: 3, CompletedTaskOverWritePolicy: "Oldest"})
+		add("running", false)
+		add("done-1", true)
+		add("done-2", true)
+
+		// The oldest finished task makes room; running tasks are never evicted
+		if err := add("new-1", false); err != nil {
+			t.Fatalf("Expected the task to be added, got %v", err)
+		}
+		if got := stored(); !slices.Equal(got, []string{"done-2", "new-1", "running"}) {
+			t.Errorf("Expected done-1 to be evicted, got %v", got)
+		}
+		add("new-2", false)
+		if err := add("new-3", false); !errors.Is(err, errTaskLimit) {
+			t.Errorf("Expected errTaskLimit with no finished task left, got %v", err)
+		}
+
+		var taskService models.TaskService
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/redfish/v1/TaskService", nil))
+		json.NewDecoder(w.Body).Decode(&taskService)
+		if taskService.CompletedTaskOverWritePolicy != "Oldest" {
+			t.Errorf("Expected the TaskService to advertise Oldest, got %q", taskService.CompletedTaskOverWritePolicy)
+		}
+	})
+
+	t.Run("Manual", func(t *testing.T) {
+		start(config.TasksConfig{MaxTasks: 2, CompletedTaskOverWritePolicy: "Manual"})
+		add("done-1", true)
+		add("done-2", true)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("POST", "/redfish/v1/TaskService/Tasks", strings.NewReader("{}")))
+		var errorResponse models.RedfishError
+		json.NewDecoder(w.Body).Decode(&errorResponse)
+		if w.Code != http.StatusConflict || errorResponse.Error.Code != baseRegistry.MessageID("CreateLimitReachedForResource") {
+			t.Errorf("Expected CreateLimitReachedForResource, got %d %s", w.Code, errorResponse.Error.Code)
+		}
+		if got := stored(); !slices.Equal(got, []string{"done-1", "done-2"}) {
+			t.Errorf("Expected the completed tasks to be retained, got %v", got)
+		}
+
+		// Deleting a task makes room again
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("DELETE", "/redfish/v1/TaskService/Tasks/done-1", nil))
+		if err := add("new-1", false); err != nil {
+			t.Errorf("Expected the task to be added after a deletion, got %v", err)
+		}
+	})
+}
+
+func TestResetTaskEstimatedDuration(t *testing.T) {
+	previous := systemResetDuration
+	systemResetDuration = 250 * time.Millisecond
+	defer func() { systemResetDuration = previous }()
+	systemStore.Put("estimate-test", models.NewComputerSystem("estimate-test"))
+	t.Cleanup(func() { systemStore.Delete("estimate-test") })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+
+	req := httptest.NewRequest("POST", "/redfish/v1/Systems/estimate-test/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "ForceRestart"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", w.Code)
+	}
+	if retryAfter := w.Header().Get("Retry-After"); retryAfter != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", retryAfter)
+	}
+	var created struct{ Id string }
+	json.Unmarshal(w.Body.Bytes(), &created)
+
+	tasksMutex.RLock()
+	task := tasks[created.Id].Snapshot()
+	tasksMutex.RUnlock()
+	if task.EstimatedDuration != "PT0.25S" {
+		t.Errorf("Expected EstimatedDuration PT0.25S, got %q", task.EstimatedDuration)
+	}
+
+	// Let the reset finish before restoring its duration
+	deadline := time.Now().Add(2 * time.Second)
+	for taskState(created.Id) != "Completed" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCompositeResetSubTasks(t *testing.T) {
+	previous := systemResetDuration
+	systemResetDuration = 20 * time.Millisecond
+	defer func() { systemResetDuration = previous }()
+	systemStore.Put("subtask-test", models.NewComputerSystem("subtask-test"))
+	t.Cleanup(func() { systemStore.Delete("subtask-test") })
+
+	mux := http.NewServeMux()
+	setupRoutes(mux)
+	get := func(path string, v interface{}) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+				t.Fatalf("Failed to decode %s: %v", path, err)
+			}
+		}
+		return w.Code
+	}
+
+	// A restart is a power off followed by a power on
+	req := httptest.NewRequest("POST", "/redfish/v1/Systems/subtask-test/Actions/ComputerSystem.Reset", strings.NewReader(`{"ResetType": "ForceRestart"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	var created struct{ Id string }
+	json.Unmarshal(w.Body.Bytes(), &created)
+	deadline := time.Now().Add(2 * time.Second)
+	for taskState(created.Id) != "Completed" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var task models.Task
+	get("/redfish/v1/TaskService/Tasks/"+created.Id, &task)
+	if task.SubTasks == nil || task.PercentComplete != 100 {
+		t.Fatalf("Expected a completed task with SubTasks, got %+v at %d%%", task.SubTasks, task.PercentComplete)
+	}
+	var collection models.Collection
+	if code := get(task.SubTasks.ODataID, &collection); code != http.StatusOK || collection.MembersODataCount != 2 {
+		t.Fatalf("Expected 2 sub-tasks, got %d %+v", code, collection)
+	}
+	for _, member := range collection.Members {
+		var sub models.Task
+		if code := get(string(member.ODataID), &sub); code != http.StatusOK || sub.TaskState != "Completed" {
+			t.Errorf("Expected %s to be a completed sub-task, got %d %s", member.ODataID, code, sub.TaskState)
+		}
+	}
+
+	// The parent's progress follows its sub-tasks
+	parent := addTestTask(t, "subtask-progress")
+	tasksMutex.Lock()
+	parent.AddSubTask("Power off")
+	parent.AddSubTask("Power on")
+	version := parent.Version()
+	tasksMutex.Unlock()
+	if _, err := updateTask(parent, version, func(task *models.Task) { task.UpdateSubTaskState("0", "Completed") }); err != nil {
+		t.Fatalf("Failed to update the sub-task: %v", err)
+	}
+	get("/redfish/v1/TaskService/Tasks/subtask-progress", &task)
+	if task.PercentComplete != 50 {
+		t.Errorf("Expected the parent at 50%% with one of two sub-tasks done, got %d", task.PercentComplete)
+	}
+
+	if code := get("/redfish/v1/TaskService/Tasks/subtask-progress/SubTasks/9", &task); code != http.StatusNotFound {
+		t.Errorf("Expected an unknown sub-task to be 404, got %d", code)
+	}
+	if code := get("/redfish/v1/TaskService/Tasks/"+created.Id+"/Other", &task); code != http.StatusNotFound {
+		t.Errorf("Expected an unknown task sub-resource to be 404, got %d", code)
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+// loadCertificate loads the configured key pair, falling back to a generated
+// self-signed certificate when auto-generation is enabled and the files are missing
+func loadCertificate(cfg config.TLSConfig) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err == nil {
+		return cert, nil
+	}
+	if !cfg.AutoGenerate || !(missingFile(cfg.CertFile) || missingFile(cfg.KeyFile)) {
+		return tls.Certificate{}, fmt.Errorf("failed to load TLS certificates: %w", err)
+	}
+
+	log.Printf("WARNING: TLS certificate files not found, using a generated self-signed certificate for %v. Do not use this in production!", cfg.AutoGenerateHosts)
+	cert, err = generateSelfSignedCert(cfg.AutoGenerateHosts)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate TLS certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// certificateHostname is the host the certificate must cover: the configured
+// public hostname, or else the host of the server address. It is empty when
+// neither names a specific host, as with ":8443" or "0.0.0.0:8443".
+func certificateHostname(cfg *config.Config) string {
+	if cfg.TLS.Hostname != "" {
+		return cfg.TLS.Hostname
+	}
+	host, _, err := net.SplitHostPort(cfg.Server.Address)
+	if err != nil {
+		return ""
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		return ""
+	}
+	return host
+}
+
+// checkCertificateHostname reports an error when the certificate's SANs do
+// not cover host. Like TLS clients, it does not fall back to the subject CN.
+func checkCertificateHostname(cert tls.Certificate, host string) error {
+	if host == "" || len(cert.Certificate) == 0 {
+		return nil
+	}
+	leaf := cert.Leaf
+	if leaf == nil {
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse TLS certificate: %w", err)
+		}
+		leaf = parsed
+	}
+	if err := leaf.VerifyHostname(host); err != nil {
+		return fmt.Errorf("TLS certificate does not cover the configured hostname %s (DNS names %v, IP addresses %v)", host, leaf.DNSNames, leaf.IPAddresses)
+	}
+	return nil
+}
+
+// missingFile reports whether path is unset or does not exist
+func missingFile(path string) bool {
+	if path == "" {
+		return true
+	}
+	_, err := os.Stat(path)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// generateSelfSignedCert creates an in-memory self-signed certificate whose
+// SANs cover hosts; the first host doubles as the subject CN
+func generateSelfSignedCert(hosts []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: hosts[0], Organization: []string{"Redfish Server (development)"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
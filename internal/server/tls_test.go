@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/redfish-server/internal/config"
+)
+
+func TestAutoGeneratedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		DevMode: true,
+		Server: config.ServerConfig{
+			Address: "127.0.0.1:0",
+		},
+		TLS: config.TLSConfig{
+			Enabled:           true,
+			CertFile:          filepath.Join(dir, "missing.crt"),
+			KeyFile:           filepath.Join(dir, "missing.key"),
+			AutoGenerate:      true,
+			AutoGenerateHosts: []string{"bmc.example.test", "127.0.0.1"},
+		},
+	}
+
+	server, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server with auto-generated cert: %v", err)
+	}
+
+	leaf := server.httpServer.TLSConfig.Certificates[0].Leaf
+	if leaf.Subject.CommonName != "bmc.example.test" {
+		t.Errorf("Expected CN bmc.example.test, got %s", leaf.Subject.CommonName)
+	}
+	if err := leaf.VerifyHostname("bmc.example.test"); err != nil {
+		t.Errorf("Generated cert should cover the configured DNS name: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.httpServer.ServeTLS(ln, "", "")
+	defer server.httpServer.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+	}
+
+	resp, err := client.Get("https://" + ln.Addr().String() + "/health")
+	if err != nil {
+		t.Fatalf("TLS request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.TLS == nil {
+		t.Error("Expected the response to be served over TLS")
+	}
+}
+
+func TestMissingCertificateWithoutAutoGenerate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		DevMode: true,
+		Server: config.ServerConfig{
+			Address: "127.0.0.1:0",
+		},
+		TLS: config.TLSConfig{
+			Enabled:  true,
+			CertFile: filepath.Join(dir, "missing.crt"),
+			KeyFile:  filepath.Join(dir, "missing.key"),
+		},
+	}
+
+	if _, err := New(cfg); err == nil {
+		t.Error("Expected missing certificate files to fail without auto-generation")
+	}
+}
+
+func TestCertificateHostnameMismatch(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	dir := t.TempDir()
+	cfg := &config.Config{
+		DevMode: true,
+		Server: config.ServerConfig{
+			Address: ":0",
+		},
+		TLS: config.TLSConfig{
+			Enabled:           true,
+			CertFile:          filepath.Join(dir, "missing.crt"),
+			KeyFile:           filepath.Join(dir, "missing.key"),
+			AutoGenerate:      true,
+			AutoGenerateHosts: []string{"other.example.test"},
+			Hostname:          "bmc.example.test",
+		},
+	}
+
+	if _, err := New(cfg); err != nil {
+		t.Fatalf("Expected a hostname mismatch to only warn, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "does not cover the configured hostname bmc.example.test") {
+		t.Errorf("Expected a hostname mismatch warning, got %q", logs.String())
+	}
+
+	cfg.TLS.StrictHostname = true
+	if _, err := New(cfg); err == nil {
+		t.Error("Expected a hostname mismatch to fail in strict mode")
+	}
+
+	cfg.TLS.AutoGenerateHosts = []string{"bmc.example.test"}
+	if _, err := New(cfg); err != nil {
+		t.Errorf("Expected a matching certificate to pass in strict mode, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Errors returned by Resources
+var (
+	ErrExists          = errors.New("resource already exists")
+	ErrResourceMissing = errors.New("resource not found")
+)
+
+// Resources holds the mutable resources of one type, such as computer
+// systems, keyed by Id. It is safe for concurrent use. Resources are deep
+// copied on the way in and out, so a handler encoding a resource never shares
+// memory with the stored value and cannot observe a concurrent update.
+type Resources[T any] struct {
+	mutex    sync.RWMutex
+	items    map[string]*T
+	onChange []func(id string)
+}
+
+// NewResources creates an empty resource store
+func NewResources[T any]() *Resources[T] {
+	return &Resources[T]{items: make(map[string]*T)}
+}
+
+// OnChange registers fn to be called with the Id of every resource that is
+// stored, updated or deleted. fn runs before the change is released to
+// readers, so it must not call back into the store.
+func (r *Resources[T]) OnChange(fn func(id string)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// changed notifies the OnChange functions; the write lock must be held
+func (r *Resources[T]) changed(id string) {
+	for _, fn := range r.onChange {
+		fn(id)
+	}
+}
+
+// Get returns a copy of the resource with the given Id
+func (r *Resources[T]) Get(id string) (*T, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, false
+	}
+	return deepCopy(item), true
+}
+
+// IDs returns the Ids of all resources in sorted order
+func (r *Resources[T]) IDs() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ids := make([]string, 0, len(r.items))
+	for id := range r.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Put stores a copy of v under id, replacing any existing resource
+func (r *Resources[T]) Put(id string, v *T) {
+	item := deepCopy(v)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.items[id] = item
+	r.changed(id)
+}
+
+// Create stores a copy of v under id, failing with ErrExists if the Id is taken
+func (r *Resources[T]) Create(id string, v *T) error {
+	item := deepCopy(v)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.items[id]; ok {
+		return fmt.Errorf("%w: %s", ErrExists, id)
+	}
+	r.items[id] = item
+	r.changed(id)
+	return nil
+}
+
+// Update applies update to a copy of the resource and stores the result,
+// returning a copy of the updated resource. Updates to the same store are
+// serialized; if update fails the stored resource is left unchanged. A missing
+// resource yields ErrResourceMissing.
+func (r *Resources[T]) Update(id string, update func(*T) error) (*T, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrResourceMissing, id)
+	}
+	updated := deepCopy(item)
+	if err := update(updated); err != nil {
+		return nil, err
+	}
+	r.items[id] = updated
+	r.changed(id)
+	return deepCopy(updated), nil
+}
+
+// Delete removes the resource with the given Id, reporting whether it existed
+func (r *Resources[T]) Delete(id string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, ok := r.items[id]
+	delete(r.items, id)
+	if ok {
+		r.changed(id)
+	}
+	return ok
+}
+
+// deepCopy copies a resource through its JSON form. Resources are always
+// served as JSON, so a value that cannot round-trip is a programming error.
+func deepCopy[T any](v *T) *T {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("store: resource cannot be copied: %v", err))
+	}
+	result := new(T)
+	if err := json.Unmarshal(data, result); err != nil {
+		panic(fmt.Sprintf("store: resource cannot be copied: %v", err))
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotFound is returned by Load when nothing has been saved under a name
+var ErrNotFound = errors.New("no saved state")
+
+// Store persists named pieces of service state, such as accounts and event
+// subscriptions, as JSON
+type Store interface {
+	// Load decodes the state saved under name into v
+	Load(name string, v any) error
+	// Save replaces the state saved under name with v
+	Save(name string, v any) error
+}
+
+// MemoryStore keeps saved state in memory only, so it is lost on restart
+type MemoryStore struct {
+	mutex sync.RWMutex
+	data  map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string][]byte)}
+}
+
+// Load decodes the state saved under name into v
+func (m *MemoryStore) Load(name string, v any) error {
+	m.mutex.RLock()
+	data, ok := m.data[name]
+	m.mutex.RUnlock()
+	if !ok {
+		return ErrNotFound
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Save replaces the state saved under name with v
+func (m *MemoryStore) Save(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	m.data[name] = data
+	m.mutex.Unlock()
+	return nil
+}
+
+// FileStore saves each piece of state as <name>.json in a directory. Files
+// are written to a temporary file and renamed into place, so a crash mid-write
+// leaves the previous state intact.
+type FileStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewFileStore creates a store in dir, creating the directory if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load decodes the state saved under name into v
+func (f *FileStore) Load(name string, v any) error {
+	data, err := os.ReadFile(f.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", f.path(name), err)
+	}
+	return nil
+}
+
+// Save replaces the state saved under name with v
+func (f *FileStore) Save(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	tmp, err := os.CreateTemp(f.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(name))
+}
+
+// path returns the file that holds the state saved under name
+func (f *FileStore) path(name string) string {
+	return filepath.Join(f.dir, name+".json")
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+type record struct {
+	Name  string
+	Count int
+}
+
+func TestStores(t *testing.T) {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+
+	for name, s := range map[string]Store{"memory": NewMemoryStore(), "file": fileStore} {
+		var loaded []record
+		if err := s.Load("records", &loaded); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: expected ErrNotFound before saving, got %v", name, err)
+		}
+
+		saved := []record{{"a", 1}, {"b", 2}}
+		if err := s.Save("records", saved); err != nil {
+			t.Fatalf("%s: save failed: %v", name, err)
+		}
+		if err := s.Load("records", &loaded); err != nil {
+			t.Fatalf("%s: load failed: %v", name, err)
+		}
+		if len(loaded) != 2 || loaded[1] != saved[1] {
+			t.Errorf("%s: expected %v, got %v", name, saved, loaded)
+		}
+	}
+}
+
+func TestFileStoreSurvivesReopenAndLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := first.Save("counter", record{"counter", i}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	second, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen file store: %v", err)
+	}
+	var loaded record
+	if err := second.Load("counter", &loaded); err != nil || loaded.Count != 3 {
+		t.Errorf("Expected the last saved value after reopening, got %+v (%v)", loaded, err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "counter.json" {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("Expected only counter.json in the state directory, got %v", names)
+	}
+}
+
+func TestFileStoreRejectsCorruptState(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	var loaded record
+	if err := s.Load("broken", &loaded); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a decode error, got %v", err)
+	}
+}
+
+func TestResourcesReturnCopies(t *testing.T) {
+	resources := NewResources[record]()
+	original := &record{"a", 1}
+	resources.Put("a", original)
+	original.Count = 99
+
+	got, ok := resources.Get("a")
+	if !ok || got.Count != 1 {
+		t.Fatalf("Expected stored copy with Count 1, got %+v (%t)", got, ok)
+	}
+	got.Count = 42
+	if again, _ := resources.Get("a"); again.Count != 1 {
+		t.Errorf("Mutating a returned resource changed the store: %+v", again)
+	}
+
+	if err := resources.Create("a", &record{"a", 2}); !errors.Is(err, ErrExists) {
+		t.Errorf("Expected ErrExists, got %v", err)
+	}
+
+	failure := errors.New("rejected")
+	if _, err := resources.Update("a", func(r *record) error { r.Count = 5; return failure }); !errors.Is(err, failure) {
+		t.Errorf("Expected the update error, got %v", err)
+	}
+	if again, _ := resources.Get("a"); again.Count != 1 {
+		t.Errorf("A failed update changed the store: %+v", again)
+	}
+
+	updated, err := resources.Update("a", func(r *record) error { r.Count++; return nil })
+	if err != nil || updated.Count != 2 {
+		t.Errorf("Expected Count 2 after update, got %+v (%v)", updated, err)
+	}
+	if _, err := resources.Update("b", func(r *record) error { return nil }); !errors.Is(err, ErrResourceMissing) {
+		t.Errorf("Expected ErrResourceMissing, got %v", err)
+	}
+	if !resources.Delete("a") || resources.Delete("a") {
+		t.Error("Expected Delete to report the resource only once")
+	}
+}
+
+func TestResourcesNotifyChanges(t *testing.T) {
+	resources := NewResources[record]()
+	var changed []string
+	resources.OnChange(func(id string) { changed = append(changed, id) })
+
+	resources.Put("a", &record{Name: "a"})
+	resources.Create("b", &record{Name: "b"})
+	resources.Create("b", &record{Name: "again"})
+	resources.Update("a", func(r *record) error { r.Count++; return nil })
+	resources.Update("a", func(r *record) error { return errors.New("rejected") })
+	resources.Delete("b")
+	resources.Delete("missing")
+
+	if want := []string{"a", "b", "a", "b"}; !slices.Equal(changed, want) {
+		t.Errorf("Expected changes %v, got %v", want, changed)
+	}
+}
@@ -5,7 +5,7 @@ echo "Building server..."
 go build -o server cmd/server/main.go
 
 echo "Starting server..."
-SERVER_ADDRESS=:8443 TLS_ENABLED=true ./server &
+DEV_MODE=true SERVER_ADDRESS=:8443 TLS_ENABLED=true ./server &
 SERVER_PID=$!
 
 sleep 3